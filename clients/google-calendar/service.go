@@ -8,6 +8,7 @@ import (
 	"google.golang.org/api/option"
 	"log"
 	"os"
+	"sort"
 	"tg_ics_useful_bot/lib/e"
 	"time"
 )
@@ -16,25 +17,52 @@ const (
 	KeyFile       = "credentials.json"
 	ScopeCalendar = "https://www.googleapis.com/auth/calendar"
 	ScopeEvents   = "https://www.googleapis.com/auth/calendar.events"
+
+	maxResults      = 250
+	cancelledStatus = "cancelled"
 )
 
-func Lessons(calendarID string) (map[time.Weekday][]Lesson, error) {
-	lessons := make(map[time.Weekday][]Lesson)
-	events, err := allEvents(calendarID)
+// CredentialsFile путь к ключу сервисного аккаунта Google.
+var CredentialsFile = "clients/google-calendar/" + KeyFile
+
+// Lessons возвращает занятия из календаря, начинающиеся в промежутке [from, to), отсортированные по времени.
+// Повторяющиеся события разворачиваются в отдельные занятия, события на весь день пропускаются.
+func Lessons(calendarID string, from, to time.Time) ([]Lesson, error) {
+	events, err := allEvents(calendarID, from, to)
 	if err != nil {
 		return nil, err
 	}
-	items := events.Items
-	for _, item := range items {
+	lessons := make([]Lesson, 0, len(events))
+	for _, item := range events {
+		if item.Status == cancelledStatus || item.Start == nil || item.Start.DateTime == "" {
+			continue
+		}
 		l := rewLesson(item.Summary, item.Start.DateTime)
-		lessons[l.DateTime.Weekday()] = append(lessons[l.DateTime.Weekday()], l)
+		l.Location, l.Description = item.Location, item.Description
+		if item.End != nil && item.End.DateTime != "" {
+			if end, err := time.Parse(time.RFC3339, item.End.DateTime); err == nil {
+				l.End = end
+			}
+		}
+		if l.DateTime.Before(from) || !l.DateTime.Before(to) {
+			continue
+		}
+		lessons = append(lessons, l)
 	}
+	sort.SliceStable(lessons, func(i, j int) bool {
+		return lessons[i].DateTime.Before(lessons[j].DateTime)
+	})
 	return lessons, nil
 }
 
+// Lesson событие календаря. End, Location и Description - поля события как есть,
+// они разбираются в lib/schedule.
 type Lesson struct {
-	Name     string
-	DateTime time.Time
+	Name        string
+	DateTime    time.Time
+	End         time.Time
+	Location    string
+	Description string
 }
 
 func rewLesson(name string, stringTime string) Lesson {
@@ -42,32 +70,51 @@ func rewLesson(name string, stringTime string) Lesson {
 	if err != nil {
 		log.Printf("can't convert time from string %v: %v", stringTime, err)
 	}
-	return Lesson{name, t}
+	return Lesson{Name: name, DateTime: t}
 }
 
-func allEvents(calendarID string) (*calendar.Events, error) {
-	srv := service()
-	events, err := srv.Events.List(calendarID).Do()
+// allEvents возвращает все события календаря в промежутке [from, to), проходя по всем страницам ответа.
+func allEvents(calendarID string, from, to time.Time) ([]*calendar.Event, error) {
+	srv, err := service()
 	if err != nil {
-		return nil, e.Wrap(fmt.Sprintf("[ERROR] can't get events from calendar_id %s", calendarID), err)
+		return nil, err
+	}
+	call := srv.Events.List(calendarID).
+		TimeMin(from.Format(time.RFC3339)).
+		TimeMax(to.Format(time.RFC3339)).
+		SingleEvents(true).
+		OrderBy("startTime").
+		MaxResults(maxResults)
+
+	items := make([]*calendar.Event, 0)
+	pageToken := ""
+	for {
+		events, err := call.PageToken(pageToken).Do()
+		if err != nil {
+			return nil, e.Wrap(fmt.Sprintf("[ERROR] can't get events from calendar_id %s", calendarID), err)
+		}
+		items = append(items, events.Items...)
+		if events.NextPageToken == "" {
+			return items, nil
+		}
+		pageToken = events.NextPageToken
 	}
-	return events, nil
 }
 
-func service() *calendar.Service {
+func service() (*calendar.Service, error) {
 	ctx := context.Background()
 
-	data, err := os.ReadFile("clients/google-calendar/" + KeyFile)
+	data, err := os.ReadFile(CredentialsFile)
 	if err != nil {
-		log.Fatalf("Can't read credentials from file: %s", KeyFile)
+		return nil, e.Wrap(fmt.Sprintf("can't read credentials from file %s", CredentialsFile), err)
 	}
 	creds, err := google.CredentialsFromJSON(ctx, data, ScopeCalendar, ScopeEvents)
 	if err != nil {
-		log.Fatal(err)
+		return nil, e.Wrap("can't parse credentials", err)
 	}
 	srv, err := calendar.NewService(ctx, option.WithCredentials(creds))
 	if err != nil {
-		log.Fatalf("Unable to retrieve Calendar client: %v", err)
+		return nil, e.Wrap("unable to retrieve Calendar client", err)
 	}
-	return srv
+	return srv, nil
 }
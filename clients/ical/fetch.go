@@ -0,0 +1,39 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"tg_ics_useful_bot/lib/e"
+	"time"
+)
+
+const (
+	fetchTimeout = 30 * time.Second
+	// maxCalendarSize ограничивает размер скачиваемого календаря.
+	maxCalendarSize = 10 << 20
+)
+
+// Fetch скачивает календарь по ссылке http(s):// или webcal://.
+func Fetch(calendarURL string) ([]byte, error) {
+	if strings.HasPrefix(calendarURL, "webcal://") {
+		calendarURL = "https://" + strings.TrimPrefix(calendarURL, "webcal://")
+	}
+
+	c := http.Client{Timeout: fetchTimeout}
+	resp, err := c.Get(calendarURL)
+	if err != nil {
+		return nil, e.Wrap(fmt.Sprintf("can't get calendar from %s", calendarURL), err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("can't get calendar from %s: status %s", calendarURL, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxCalendarSize))
+	if err != nil {
+		return nil, e.Wrap("can't read calendar", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,297 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"tg_ics_useful_bot/lib/e"
+	"time"
+)
+
+const (
+	dateLayout      = "20060102"
+	dateTimeLayout  = "20060102T150405"
+	utcLayout       = "20060102T150405Z"
+	cancelledStatus = "CANCELLED"
+
+	// maxOccurrences ограничивает разворачивание одного повторяющегося события.
+	maxOccurrences = 5000
+)
+
+var ErrNoCalendar = errors.New("no VCALENDAR in data")
+
+// Calendar разобранный iCalendar файл.
+type Calendar struct {
+	Events []*Event
+}
+
+// Event событие календаря (VEVENT).
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Status      string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+
+	rule         *rule
+	exDates      []time.Time
+	recurrenceID time.Time
+}
+
+// property строка iCalendar вида NAME;PARAM=VALUE:value.
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse разбирает календарь в формате iCalendar (RFC 5545).
+func Parse(r io.Reader) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, e.Wrap("can't read calendar", err)
+	}
+
+	var cal *Calendar
+	var event *Event
+	defaultLoc := time.UTC
+	for _, line := range lines {
+		prop := parseProperty(line)
+		switch {
+		case prop.name == "BEGIN" && prop.value == "VCALENDAR":
+			cal = &Calendar{}
+		case prop.name == "X-WR-TIMEZONE":
+			if loc, err := time.LoadLocation(prop.value); err == nil {
+				defaultLoc = loc
+			}
+		case prop.name == "BEGIN" && prop.value == "VEVENT":
+			event = &Event{}
+		case prop.name == "END" && prop.value == "VEVENT":
+			if cal != nil && event != nil {
+				if event.End.IsZero() {
+					event.End = event.Start
+				}
+				cal.Events = append(cal.Events, event)
+			}
+			event = nil
+		case event != nil:
+			if err = event.set(prop, defaultLoc); err != nil {
+				return nil, e.Wrap(fmt.Sprintf("can't parse event %s", event.UID), err)
+			}
+		}
+	}
+	if cal == nil {
+		return nil, ErrNoCalendar
+	}
+	return cal, nil
+}
+
+// set заполняет поле события значением свойства.
+func (ev *Event) set(prop property, defaultLoc *time.Location) (err error) {
+	switch prop.name {
+	case "UID":
+		ev.UID = prop.value
+	case "SUMMARY":
+		ev.Summary = unescape(prop.value)
+	case "DESCRIPTION":
+		ev.Description = unescape(prop.value)
+	case "LOCATION":
+		ev.Location = unescape(prop.value)
+	case "STATUS":
+		ev.Status = strings.ToUpper(prop.value)
+	case "DTSTART":
+		ev.Start, ev.AllDay, err = parseTime(prop, defaultLoc)
+	case "DTEND":
+		ev.End, _, err = parseTime(prop, defaultLoc)
+	case "DURATION":
+		var d time.Duration
+		if d, err = parseDuration(prop.value); err == nil {
+			ev.End = ev.Start.Add(d)
+		}
+	case "RRULE":
+		ev.rule, err = parseRule(prop.value, defaultLoc)
+	case "EXDATE":
+		for _, value := range strings.Split(prop.value, ",") {
+			t, _, err := parseTime(property{name: prop.name, params: prop.params, value: value}, defaultLoc)
+			if err != nil {
+				return err
+			}
+			ev.exDates = append(ev.exDates, t)
+		}
+	case "RECURRENCE-ID":
+		ev.recurrenceID, _, err = parseTime(prop, defaultLoc)
+	}
+	return err
+}
+
+// Occurrences возвращает события, начинающиеся в промежутке [from, to), отсортированные по времени.
+// Повторяющиеся события разворачиваются по RRULE с учётом EXDATE и изменённых экземпляров (RECURRENCE-ID).
+func (c *Calendar) Occurrences(from, to time.Time) []Event {
+	overrides := make(map[string]map[int64]*Event)
+	for _, ev := range c.Events {
+		if ev.recurrenceID.IsZero() {
+			continue
+		}
+		if overrides[ev.UID] == nil {
+			overrides[ev.UID] = make(map[int64]*Event)
+		}
+		overrides[ev.UID][ev.recurrenceID.Unix()] = ev
+	}
+
+	result := make([]Event, 0)
+	add := func(ev Event) {
+		if ev.Status != cancelledStatus && !ev.Start.Before(from) && ev.Start.Before(to) {
+			result = append(result, ev)
+		}
+	}
+	for _, ev := range c.Events {
+		if !ev.recurrenceID.IsZero() {
+			add(*ev)
+			continue
+		}
+		if ev.rule == nil {
+			add(*ev)
+			continue
+		}
+		duration := ev.End.Sub(ev.Start)
+		for _, start := range ev.rule.starts(ev.Start, to) {
+			if _, ok := overrides[ev.UID][start.Unix()]; ok || containsTime(ev.exDates, start) {
+				continue
+			}
+			instance := *ev
+			instance.Start, instance.End = start, start.Add(duration)
+			add(instance)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
+
+// unfold читает строки iCalendar, склеивая перенесённые строки (начинающиеся с пробела или табуляции).
+func unfold(r io.Reader) ([]string, error) {
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// parseProperty разбирает строку вида NAME;PARAM=VALUE;PARAM2="V:2":value.
+func parseProperty(line string) property {
+	prop := property{params: make(map[string]string)}
+	inQuotes, sep := false, -1
+	for i, ch := range line {
+		if ch == '"' {
+			inQuotes = !inQuotes
+		}
+		if ch == ':' && !inQuotes {
+			sep = i
+			break
+		}
+	}
+	head := line
+	if sep >= 0 {
+		head, prop.value = line[:sep], line[sep+1:]
+	}
+
+	parts := strings.Split(head, ";")
+	prop.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return prop
+}
+
+// parseTime разбирает DATE или DATE-TIME значение с учётом параметра TZID.
+// Возвращает true, если значение - дата без времени.
+func parseTime(prop property, defaultLoc *time.Location) (time.Time, bool, error) {
+	loc := defaultLoc
+	if tzid, ok := prop.params["TZID"]; ok {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	value := strings.TrimSpace(prop.value)
+	if prop.params["VALUE"] == "DATE" || len(value) == len(dateLayout) {
+		t, err := time.ParseInLocation(dateLayout, value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(utcLayout, value)
+		return t, false, err
+	}
+	t, err := time.ParseInLocation(dateTimeLayout, value, loc)
+	return t, false, err
+}
+
+// parseDuration разбирает длительность вида P1DT2H30M или PT1H30M.
+func parseDuration(value string) (time.Duration, error) {
+	sign := time.Duration(1)
+	if strings.HasPrefix(value, "-") {
+		sign, value = -1, value[1:]
+	}
+	value = strings.TrimPrefix(value, "+")
+	if !strings.HasPrefix(value, "P") {
+		return 0, fmt.Errorf("wrong duration %s", value)
+	}
+
+	var result time.Duration
+	num, inTime := 0, false
+	for _, ch := range value[1:] {
+		switch {
+		case ch >= '0' && ch <= '9':
+			num = num*10 + int(ch-'0')
+			continue
+		case ch == 'T':
+			inTime = true
+		case ch == 'W':
+			result += time.Duration(num) * 7 * 24 * time.Hour
+		case ch == 'D':
+			result += time.Duration(num) * 24 * time.Hour
+		case ch == 'H' && inTime:
+			result += time.Duration(num) * time.Hour
+		case ch == 'M' && inTime:
+			result += time.Duration(num) * time.Minute
+		case ch == 'S' && inTime:
+			result += time.Duration(num) * time.Second
+		default:
+			return 0, fmt.Errorf("wrong duration %s", value)
+		}
+		num = 0
+	}
+	return sign * result, nil
+}
+
+// unescape убирает экранирование из текстовых значений.
+func unescape(value string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
+
+func containsTime(times []time.Time, t time.Time) bool {
+	for _, tt := range times {
+		if tt.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCalendar = `BEGIN:VCALENDAR
+VERSION:2.0
+X-WR-TIMEZONE:Europe/Moscow
+BEGIN:VEVENT
+UID:lecture
+SUMMARY:Математический анализ\, лекция
+LOCATION:ауд. 301
+DTSTART;TZID=Europe/Moscow:20240108T094500
+DTEND;TZID=Europe/Moscow:20240108T112000
+RRULE:FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20240131T235959Z
+EXDATE;TZID=Europe/Moscow:20240110T094500
+END:VEVENT
+BEGIN:VEVENT
+UID:lecture
+RECURRENCE-ID;TZID=Europe/Moscow:20240115T094500
+SUMMARY:Математический анализ\, лекция
+DTSTART;TZID=Europe/Moscow:20240115T130000
+DURATION:PT1H35M
+END:VEVENT
+BEGIN:VEVENT
+UID:once
+SUMMARY:Консультация по очень длинному на
+ званию
+DTSTART:20240109T120000Z
+DTEND:20240109T130000Z
+END:VEVENT
+BEGIN:VEVENT
+UID:holiday
+SUMMARY:Выходной
+DTSTART;VALUE=DATE:20240112
+END:VEVENT
+BEGIN:VEVENT
+UID:cancelled
+SUMMARY:Отменено
+STATUS:CANCELLED
+DTSTART:20240111T120000Z
+END:VEVENT
+BEGIN:VEVENT
+UID:count
+SUMMARY:Практика
+DTSTART;TZID=Europe/Moscow:20240101T150000
+RRULE:FREQ=DAILY;INTERVAL=2;COUNT=3
+END:VEVENT
+END:VCALENDAR
+`
+
+func Test_Occurrences(t *testing.T) {
+	cal, err := Parse(strings.NewReader(testCalendar))
+	if err != nil {
+		t.Fatalf("can't parse calendar: %v", err)
+	}
+
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, moscow)
+	to := time.Date(2024, time.January, 20, 0, 0, 0, 0, moscow)
+
+	want := []struct {
+		uid   string
+		start string
+	}{
+		{"count", "01.01 15:00"},
+		{"count", "03.01 15:00"},
+		{"count", "05.01 15:00"},
+		{"lecture", "08.01 09:45"},
+		{"once", "09.01 15:00"},
+		{"holiday", "12.01 00:00"},
+		{"lecture", "15.01 13:00"},
+		{"lecture", "17.01 09:45"},
+	}
+
+	got := cal.Occurrences(from, to)
+	if len(got) != len(want) {
+		for _, ev := range got {
+			t.Logf("%s %s", ev.UID, ev.Start.In(moscow).Format("02.01 15:04"))
+		}
+		t.Fatalf("got %d occurrences, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		start := got[i].Start.In(moscow).Format("02.01 15:04")
+		if got[i].UID != w.uid || start != w.start {
+			t.Errorf("occurrence %d: got %s %s, want %s %s", i, got[i].UID, start, w.uid, w.start)
+		}
+	}
+
+	if got[3].Summary != "Математический анализ, лекция" || got[3].Location != "ауд. 301" {
+		t.Errorf("wrong lecture fields: %q, %q", got[3].Summary, got[3].Location)
+	}
+	if got[3].End.Sub(got[3].Start) != 95*time.Minute || got[6].End.Sub(got[6].Start) != 95*time.Minute {
+		t.Errorf("wrong lecture duration")
+	}
+	if got[4].Summary != "Консультация по очень длинному названию" {
+		t.Errorf("wrong folded summary: %q", got[4].Summary)
+	}
+	if !got[5].AllDay {
+		t.Errorf("holiday must be all-day event")
+	}
+}
+
+func Test_ParseWithoutCalendar(t *testing.T) {
+	if _, err := Parse(strings.NewReader("not a calendar")); err != ErrNoCalendar {
+		t.Errorf("got error %v, want %v", err, ErrNoCalendar)
+	}
+}
@@ -0,0 +1,133 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type frequency string
+
+const (
+	daily   frequency = "DAILY"
+	weekly  frequency = "WEEKLY"
+	monthly frequency = "MONTHLY"
+	yearly  frequency = "YEARLY"
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// rule правило повторения события (RRULE).
+// Поддерживаются FREQ, INTERVAL, COUNT, UNTIL и BYDAY для недельных правил.
+type rule struct {
+	freq     frequency
+	interval int
+	count    int
+	until    time.Time
+	byDay    []time.Weekday
+}
+
+// parseRule разбирает значение RRULE, например FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE.
+func parseRule(value string, defaultLoc *time.Location) (*rule, error) {
+	r := &rule{interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, val, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.freq = frequency(strings.ToUpper(val))
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval <= 0 {
+				return nil, fmt.Errorf("wrong rrule interval %s", val)
+			}
+			r.interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil {
+				return nil, fmt.Errorf("wrong rrule count %s", val)
+			}
+			r.count = count
+		case "UNTIL":
+			until, _, err := parseTime(property{value: val, params: map[string]string{}}, defaultLoc)
+			if err != nil {
+				return nil, fmt.Errorf("wrong rrule until %s", val)
+			}
+			r.until = until
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				// префиксы вида 1MO и -1FR (n-й день месяца) не поддерживаются, берём только день недели
+				day = strings.TrimLeft(day, "+-0123456789")
+				if wd, ok := weekdays[strings.ToUpper(day)]; ok {
+					r.byDay = append(r.byDay, wd)
+				}
+			}
+		}
+	}
+
+	switch r.freq {
+	case daily, weekly, monthly, yearly:
+		return r, nil
+	}
+	return nil, fmt.Errorf("unsupported rrule frequency %s", r.freq)
+}
+
+// starts возвращает времена начала всех повторений, начинающихся раньше to.
+func (r *rule) starts(dtStart time.Time, to time.Time) []time.Time {
+	result := make([]time.Time, 0)
+	add := func(t time.Time) bool {
+		if t.Before(dtStart) {
+			return true
+		}
+		if !t.Before(to) || (!r.until.IsZero() && t.After(r.until)) ||
+			(r.count > 0 && len(result) >= r.count) || len(result) >= maxOccurrences {
+			return false
+		}
+		result = append(result, t)
+		return true
+	}
+
+	if r.freq == weekly && len(r.byDay) > 0 {
+		weekStart := dtStart.AddDate(0, 0, -((int(dtStart.Weekday()) + 6) % 7))
+		days := make([]int, 0, len(r.byDay))
+		for _, wd := range r.byDay {
+			days = append(days, (int(wd)+6)%7)
+		}
+		sort.Ints(days)
+		for week := 0; ; week += r.interval {
+			for _, offset := range days {
+				if !add(weekStart.AddDate(0, 0, week*7+offset)) {
+					return result
+				}
+			}
+		}
+	}
+
+	for n := 0; ; n++ {
+		var t time.Time
+		switch r.freq {
+		case daily:
+			t = dtStart.AddDate(0, 0, n*r.interval)
+		case weekly:
+			t = dtStart.AddDate(0, 0, 7*n*r.interval)
+		case monthly:
+			t = dtStart.AddDate(0, n*r.interval, 0)
+			if t.Day() != dtStart.Day() {
+				// в месяце нет такого числа (31 число и т.п.) - повторение пропускается
+				continue
+			}
+		case yearly:
+			t = dtStart.AddDate(n*r.interval, 0, 0)
+			if t.Day() != dtStart.Day() {
+				continue
+			}
+		}
+		if !add(t) {
+			return result
+		}
+	}
+}
@@ -3,7 +3,10 @@ package telegram
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"path"
@@ -29,15 +32,38 @@ const (
 
 const timeToBan = 120
 
+// MaxMessageLength максимальная длина текста одного сообщения в Telegram.
+const MaxMessageLength = 4096
+
 const (
 	getUpdatesMethod            = "getUpdates"
+	getMeMethod                 = "getMe"
 	sendMessageMethod           = "sendMessage"
 	sendPhotoMethod             = "sendPhoto"
+	sendDocumentMethod          = "sendDocument"
+	sendMediaGroupMethod        = "sendMediaGroup"
+	editMessageTextMethod       = "editMessageText"
+	editMessageMarkupMethod     = "editMessageReplyMarkup"
+	answerCallbackQueryMethod   = "answerCallbackQuery"
+	pinChatMessageMethod        = "pinChatMessage"
 	deleteMessageMethod         = "deleteMessage"
 	banChatMemberMethod         = "banChatMember"
 	getChatAdministratorsMethod = "getChatAdministrators"
+	getFileMethod               = "getFile"
+	setMyCommandsMethod         = "setMyCommands"
+)
+
+// allowedUpdates типы обновлений, которые бот получает в getUpdates.
+const allowedUpdates = `["message","callback_query","chat_member","my_chat_member"]`
+
+const (
+	minMediaGroupSize = 2
+	maxMediaGroupSize = 10
 )
 
+// MaxDownloadSize максимальный размер файла, который бот может скачать через Bot API.
+const MaxDownloadSize = 20 << 20
+
 func New(host string, token string, adminsID []int) *Client {
 	return &Client{
 		host:     host,
@@ -57,6 +83,8 @@ func (c *Client) Updates(offset int, limit int) (updates []Update, err error) {
 	q := url.Values{}
 	q.Add("offset", strconv.Itoa(offset))
 	q.Add("limit", strconv.Itoa(limit))
+	// chat_member не приходит, если не запросить его явно
+	q.Add("allowed_updates", allowedUpdates)
 	data, err := c.doRequestWithQuery(getUpdatesMethod, q)
 	if err != nil {
 		return nil, err
@@ -71,6 +99,24 @@ func (c *Client) Updates(offset int, limit int) (updates []Update, err error) {
 	return res.Result, nil
 }
 
+// GetMe возвращает информацию о самом боте.
+func (c *Client) GetMe() (*User, error) {
+	data, err := c.doRequestWithQuery(getMeMethod, url.Values{})
+	if err != nil {
+		return nil, e.Wrap("can't get bot info", err)
+	}
+
+	var res UserResponse
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, e.Wrap("can't unmarshal bot info", err)
+	}
+	if !res.Ok || res.Result.Username == "" {
+		return nil, e.Wrap("can't get bot info", errors.New(string(data)))
+	}
+
+	return &res.Result, nil
+}
+
 func (c *Client) ChatAdministrators(chatID int) ([]User, error) {
 	q := url.Values{}
 	q.Add("chat_id", strconv.Itoa(chatID))
@@ -94,32 +140,173 @@ func (c *Client) ChatAdministrators(chatID int) ([]User, error) {
 }
 
 func (c *Client) SendMessage(chatID int, text string, parseMode ParseMode, replyToMessageID int) error {
-	message := Message{chatID, text, string(parseMode), replyToMessageID}
+	_, err := c.SendMessageWithButtons(chatID, text, parseMode, replyToMessageID, nil)
+	return err
+}
+
+// SendMessageWithButtons отправляет сообщение с inline клавиатурой и возвращает отправленное сообщение.
+func (c *Client) SendMessageWithButtons(chatID int, text string, parseMode ParseMode, replyToMessageID int,
+	markup *InlineKeyboardMarkup) (*IncomingMessage, error) {
+	message := Message{
+		ChatID:           chatID,
+		Text:             text,
+		ParseMode:        string(parseMode),
+		ReplyToMessageID: replyToMessageID,
+		ReplyMarkup:      markup,
+	}
+	jsonData, err := json.Marshal(message)
+	if err != nil {
+		return nil, e.Wrap("can't convert message to json: ", err)
+	}
+	data, err := c.doRequestWithBody(sendMessageMethod, jsonData)
+	if err != nil {
+		return nil, e.Wrap("can't send message", err)
+	}
+
+	var res MessageResponse
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, e.Wrap("can't unmarshal sent message", err)
+	}
+
+	return &res.Result, nil
+}
+
+// PinChatMessage закрепляет сообщение в чате.
+func (c *Client) PinChatMessage(chatID int, messageID int, disableNotification bool) error {
+	q := url.Values{}
+	q.Add("chat_id", strconv.Itoa(chatID))
+	q.Add("message_id", strconv.Itoa(messageID))
+	q.Add("disable_notification", strconv.FormatBool(disableNotification))
+
+	_, err := c.doRequestWithQuery(pinChatMessageMethod, q)
+	if err != nil {
+		return e.Wrap("can't pin message", err)
+	}
+
+	return nil
+}
+
+// EditMessageText изменяет текст и inline клавиатуру уже отправленного сообщения.
+func (c *Client) EditMessageText(chatID int, messageID int, text string, parseMode ParseMode,
+	markup *InlineKeyboardMarkup) error {
+	message := EditMessage{
+		ChatID:      chatID,
+		MessageID:   messageID,
+		Text:        text,
+		ParseMode:   string(parseMode),
+		ReplyMarkup: markup,
+	}
 	jsonData, err := json.Marshal(message)
 	if err != nil {
 		return e.Wrap("can't convert message to json: ", err)
 	}
-	_, err = c.doRequestWithBody(sendMessageMethod, jsonData)
+	_, err = c.doRequestWithBody(editMessageTextMethod, jsonData)
 	if err != nil {
-		return e.Wrap("can't send message", err)
+		return e.Wrap("can't edit message", err)
+	}
+
+	return nil
+}
+
+// EditMessageReplyMarkup изменяет inline клавиатуру уже отправленного сообщения, nil убирает её.
+func (c *Client) EditMessageReplyMarkup(chatID int, messageID int, markup *InlineKeyboardMarkup) error {
+	message := EditMarkup{
+		ChatID:      chatID,
+		MessageID:   messageID,
+		ReplyMarkup: markup,
+	}
+	jsonData, err := json.Marshal(message)
+	if err != nil {
+		return e.Wrap("can't convert markup to json: ", err)
+	}
+	_, err = c.doRequestWithBody(editMessageMarkupMethod, jsonData)
+	if err != nil {
+		return e.Wrap("can't edit message markup", err)
 	}
 
 	return nil
 }
 
-func (c *Client) SendPhoto(chatID int, urlPhoto string) error {
+// AnswerCallbackQuery отвечает на нажатие inline кнопки, text показывается пользователю уведомлением.
+func (c *Client) AnswerCallbackQuery(callbackQueryID string, text string) error {
 	q := url.Values{}
-	q.Add("chat_id", strconv.Itoa(chatID))
-	q.Add("photo", urlPhoto)
+	q.Add("callback_query_id", callbackQueryID)
+	if text != "" {
+		q.Add("text", text)
+	}
 
-	_, err := c.doRequestWithQuery(sendPhotoMethod, q)
+	_, err := c.doRequestWithQuery(answerCallbackQueryMethod, q)
 	if err != nil {
-		return e.Wrap("can't send message", err)
+		return e.Wrap("can't answer callback query", err)
+	}
+
+	return nil
+}
+
+// SendPhoto отправляет картинку в чат: загружает её данные или передаёт file_id/URL, caption - подпись к ней.
+func (c *Client) SendPhoto(chatID int, photo InputFile, caption string, parseMode ParseMode) error {
+	_, err := c.doRequestWithFiles(sendPhotoMethod, captionFields(chatID, caption, parseMode),
+		map[string]InputFile{"photo": photo})
+	if err != nil {
+		return e.Wrap("can't send photo", err)
 	}
 
 	return nil
 }
 
+// SendDocument отправляет файл в чат как документ.
+func (c *Client) SendDocument(chatID int, document InputFile, caption string) error {
+	_, err := c.doRequestWithFiles(sendDocumentMethod, captionFields(chatID, caption, ""),
+		map[string]InputFile{"document": document})
+	if err != nil {
+		return e.Wrap("can't send document", err)
+	}
+
+	return nil
+}
+
+// SendMediaGroup отправляет в чат альбом из 2-10 картинок, документов, аудио или видео.
+func (c *Client) SendMediaGroup(chatID int, media []InputMedia) error {
+	if len(media) < minMediaGroupSize || len(media) > maxMediaGroupSize {
+		return fmt.Errorf("media group must contain %d-%d items, got %d", minMediaGroupSize, maxMediaGroupSize, len(media))
+	}
+
+	items := make([]inputMedia, 0, len(media))
+	files := make(map[string]InputFile)
+	for i, m := range media {
+		item := inputMedia{Type: m.Type, Media: m.Media.FileID, Caption: m.Caption, ParseMode: m.ParseMode}
+		if m.Media.isUpload() {
+			name := fmt.Sprintf("file%d", i)
+			item.Media = "attach://" + name
+			files[name] = m.Media
+		}
+		items = append(items, item)
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return e.Wrap("can't marshal media group", err)
+	}
+
+	fields := map[string]string{"chat_id": strconv.Itoa(chatID), "media": string(data)}
+	if _, err = c.doRequestWithFiles(sendMediaGroupMethod, fields, files); err != nil {
+		return e.Wrap("can't send media group", err)
+	}
+
+	return nil
+}
+
+// captionFields возвращает поля запроса отправки файла с подписью.
+func captionFields(chatID int, caption string, parseMode ParseMode) map[string]string {
+	fields := map[string]string{"chat_id": strconv.Itoa(chatID)}
+	if caption != "" {
+		fields["caption"] = caption
+		if parseMode != "" {
+			fields["parse_mode"] = string(parseMode)
+		}
+	}
+	return fields
+}
+
 func (c *Client) DeleteMessage(chatID int, messageID int) error {
 	q := url.Values{}
 	q.Add("chat_id", strconv.Itoa(chatID))
@@ -147,6 +334,56 @@ func (c *Client) BanChatMember(chatID int, userID int, timeout int) error {
 	return nil
 }
 
+// SetMyCommands задаёт список команд бота, который Telegram показывает в подсказках пользователям
+// с языком languageCode, пустой languageCode - всем, для чьего языка списка нет.
+func (c *Client) SetMyCommands(commands []BotCommand, languageCode string) error {
+	jsonData, err := json.Marshal(struct {
+		Commands     []BotCommand `json:"commands"`
+		LanguageCode string       `json:"language_code,omitempty"`
+	}{Commands: commands, LanguageCode: languageCode})
+	if err != nil {
+		return e.Wrap("can't convert commands to json: ", err)
+	}
+	_, err = c.doRequestWithBody(setMyCommandsMethod, jsonData)
+	if err != nil {
+		return e.Wrap("can't set commands", err)
+	}
+
+	return nil
+}
+
+// DownloadFile скачивает отправленный в чат файл по его file_id.
+func (c *Client) DownloadFile(fileID string) (data []byte, err error) {
+	defer func() { err = e.WrapIfErr("can't download file", err) }()
+	q := url.Values{}
+	q.Add("file_id", fileID)
+
+	data, err = c.doRequestWithQuery(getFileMethod, q)
+	if err != nil {
+		return nil, err
+	}
+	var fileResponse FileResponse
+	if err = json.Unmarshal(data, &fileResponse); err != nil {
+		return nil, err
+	}
+	if !fileResponse.Ok || fileResponse.Result.FilePath == "" {
+		return nil, errors.New("file is not available")
+	}
+
+	u := url.URL{
+		Scheme: "https",
+		Host:   c.host,
+		Path:   path.Join("file", c.basePath, fileResponse.Result.FilePath),
+	}
+	resp, err := c.client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	return io.ReadAll(io.LimitReader(resp.Body, MaxDownloadSize))
+}
+
 func (c *Client) doRequestWithQuery(method string, query url.Values) (data []byte, err error) {
 	defer func() { err = e.WrapIfErr("can't do request", err) }()
 	u := url.URL{
@@ -173,6 +410,67 @@ func (c *Client) doRequestWithQuery(method string, query url.Values) (data []byt
 	return body, nil
 }
 
+// doRequestWithFiles отправляет multipart/form-data запрос. Файлы из files с данными загружаются
+// в поля с соответствующими именами, для остальных передаётся FileID. Данные файлов читаются
+// по мере отправки запроса, без загрузки в память целиком.
+func (c *Client) doRequestWithFiles(method string, fields map[string]string, files map[string]InputFile) (data []byte, err error) {
+	defer func() { err = e.WrapIfErr("can't do request with files", err) }()
+	u := url.URL{
+		Scheme: "https",
+		Host:   c.host,
+		Path:   path.Join(c.basePath, method),
+	}
+
+	body, w := io.Pipe()
+	form := multipart.NewWriter(w)
+	go func() {
+		_ = w.CloseWithError(writeForm(form, fields, files))
+	}()
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), body)
+	if err != nil {
+		_ = body.CloseWithError(err)
+		return nil, err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	resultBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return resultBody, nil
+}
+
+// writeForm пишет поля и файлы в multipart форму и закрывает её.
+func writeForm(form *multipart.Writer, fields map[string]string, files map[string]InputFile) error {
+	for name, value := range fields {
+		if err := form.WriteField(name, value); err != nil {
+			return err
+		}
+	}
+	for name, file := range files {
+		if !file.isUpload() {
+			if err := form.WriteField(name, file.FileID); err != nil {
+				return err
+			}
+			continue
+		}
+		part, err := form.CreateFormFile(name, file.Name)
+		if err != nil {
+			return err
+		}
+		if _, err = io.Copy(part, file.Data); err != nil {
+			return err
+		}
+	}
+	return form.Close()
+}
+
 func (c *Client) doRequestWithBody(method string, message []byte) (data []byte, err error) {
 	defer func() { err = e.WrapIfErr("can't do request with json", err) }()
 	u := url.URL{
@@ -0,0 +1,84 @@
+package telegram
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_SendMediaGroup(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/"+sendMediaGroupMethod) {
+			t.Errorf("unexpected method %s", r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("can't parse form: %v", err)
+			return
+		}
+
+		var media []inputMedia
+		if err := json.Unmarshal([]byte(r.FormValue("media")), &media); err != nil {
+			t.Errorf("can't parse media: %v", err)
+			return
+		}
+		if len(media) != 2 || media[0].Media != "attach://file0" || media[1].Media != "https://example.com/cat.png" {
+			t.Errorf("wrong media: %+v", media)
+		}
+		if media[0].Caption != "расписание" || media[0].Type != MediaPhoto {
+			t.Errorf("wrong first item: %+v", media[0])
+		}
+
+		file, header, err := r.FormFile("file0")
+		if err != nil {
+			t.Errorf("no uploaded file: %v", err)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		if header.Filename != "schedule.png" || string(data) != "png data" {
+			t.Errorf("wrong uploaded file %s: %q", header.Filename, data)
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":[]}`))
+	}))
+	defer server.Close()
+
+	c := New(server.Listener.Addr().String(), "token", nil)
+	c.client = *server.Client()
+
+	err := c.SendMediaGroup(1, []InputMedia{
+		{Type: MediaPhoto, Media: InputFile{Name: "schedule.png", Data: strings.NewReader("png data")}, Caption: "расписание"},
+		{Type: MediaPhoto, Media: NewInputFileID("https://example.com/cat.png")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = c.SendMediaGroup(1, []InputMedia{{Type: MediaPhoto, Media: NewInputFileID("id")}}); err == nil {
+		t.Error("media group with one item must be rejected")
+	}
+}
+
+func Test_ChatMemberIsMember(t *testing.T) {
+	tests := []struct {
+		data string
+		want bool
+	}{
+		{`{"status":"member"}`, true},
+		{`{"status":"administrator"}`, true},
+		{`{"status":"restricted","is_member":true}`, true},
+		{`{"status":"restricted","is_member":false}`, false},
+		{`{"status":"left"}`, false},
+		{`{"status":"kicked"}`, false},
+	}
+	for _, tt := range tests {
+		var m ChatMember
+		if err := json.Unmarshal([]byte(tt.data), &m); err != nil {
+			t.Fatalf("%s: %v", tt.data, err)
+		}
+		if got := m.IsMember(); got != tt.want {
+			t.Errorf("%s: IsMember() = %v, want %v", tt.data, got, tt.want)
+		}
+	}
+}
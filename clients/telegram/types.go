@@ -1,5 +1,7 @@
 package telegram
 
+import "io"
+
 type ChatMemberAdministratorResponse struct {
 	Ok     bool                      `json:"ok"`
 	Result []ChatMemberAdministrator `json:"result"`
@@ -14,18 +16,90 @@ type UpdatesResponse struct {
 	Result []Update `json:"result"`
 }
 
+type UserResponse struct {
+	Ok     bool `json:"ok"`
+	Result User `json:"result"`
+}
+
+type MessageResponse struct {
+	Ok     bool            `json:"ok"`
+	Result IncomingMessage `json:"result"`
+}
+
 type Update struct {
-	ID            int              `json:"update_id"`
-	Message       *IncomingMessage `json:"message"`
-	CallbackQuery *CallbackQuery   `json:"callback_query"`
+	ID            int                `json:"update_id"`
+	Message       *IncomingMessage   `json:"message"`
+	CallbackQuery *CallbackQuery     `json:"callback_query"`
+	ChatMember    *ChatMemberUpdated `json:"chat_member"`
+	// MyChatMember изменение статуса самого бота: его добавили в чат, удалили или заблокировали.
+	MyChatMember *ChatMemberUpdated `json:"my_chat_member"`
+}
+
+// ChatMemberUpdated изменение статуса участника чата. Приходит, только если бот админ в чате.
+type ChatMemberUpdated struct {
+	Chat          Chat       `json:"chat"`
+	From          User       `json:"from"`
+	Date          int        `json:"date"`
+	OldChatMember ChatMember `json:"old_chat_member"`
+	NewChatMember ChatMember `json:"new_chat_member"`
+}
+
+// ChatMember участник чата и его статус: creator, administrator, member, restricted, left или kicked.
+type ChatMember struct {
+	Status string `json:"status"`
+	User   User   `json:"user"`
+	// InChat состоит ли ограниченный участник в чате, только для статуса restricted.
+	InChat bool `json:"is_member"`
+}
+
+// IsAdmin показывает, является ли участник админом или создателем чата.
+func (m ChatMember) IsAdmin() bool {
+	return m.Status == "creator" || m.Status == "administrator"
+}
+
+// IsMember показывает, состоит ли участник в чате. Ограниченные (restricted) участники считаются,
+// если они не вышли из чата.
+func (m ChatMember) IsMember() bool {
+	switch m.Status {
+	case "left", "kicked":
+		return false
+	case "restricted":
+		return m.InChat
+	}
+	return true
 }
 
 type IncomingMessage struct {
-	ID   int    `json:"message_id"`
-	Text string `json:"text"`
-	From User   `json:"from"`
-	Date int    `json:"date"` // Date the message was sent in Unix time
-	Chat Chat   `json:"chat"`
+	ID       int       `json:"message_id"`
+	Text     string    `json:"text"`
+	Caption  string    `json:"caption"`
+	From     User      `json:"from"`
+	Date     int       `json:"date"` // Date the message was sent in Unix time
+	Chat     Chat      `json:"chat"`
+	Document *Document `json:"document"`
+	// NewChatMembers и LeftChatMember заполнены в служебных сообщениях о входе и выходе участников.
+	NewChatMembers []User `json:"new_chat_members"`
+	LeftChatMember *User  `json:"left_chat_member"`
+}
+
+// Document файл, отправленный в сообщении.
+type Document struct {
+	FileID   string `json:"file_id"`
+	FileName string `json:"file_name"`
+	MimeType string `json:"mime_type"`
+	FileSize int    `json:"file_size"`
+}
+
+type FileResponse struct {
+	Ok     bool `json:"ok"`
+	Result File `json:"result"`
+}
+
+// File информация о файле для скачивания через DownloadFile.
+type File struct {
+	FileID   string `json:"file_id"`
+	FileSize int    `json:"file_size"`
+	FilePath string `json:"file_path"`
 }
 
 type CallbackQuery struct {
@@ -52,10 +126,31 @@ type Chat struct {
 }
 
 type Message struct {
-	ChatID           int    `json:"chat_id"`
-	Text             string `json:"text"`
-	ParseMode        string `json:"parse_mode"`
-	ReplyToMessageID int    `json:"reply_to_message_id"`
+	ChatID           int                   `json:"chat_id"`
+	Text             string                `json:"text"`
+	ParseMode        string                `json:"parse_mode"`
+	ReplyToMessageID int                   `json:"reply_to_message_id"`
+	ReplyMarkup      *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+type EditMessage struct {
+	ChatID      int                   `json:"chat_id"`
+	MessageID   int                   `json:"message_id"`
+	Text        string                `json:"text"`
+	ParseMode   string                `json:"parse_mode"`
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+type EditMarkup struct {
+	ChatID      int                   `json:"chat_id"`
+	MessageID   int                   `json:"message_id"`
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+// BotCommand команда бота для setMyCommands: название без слеша и описание.
+type BotCommand struct {
+	Command     string `json:"command"`
+	Description string `json:"description"`
 }
 
 type ForceReply struct {
@@ -66,10 +161,53 @@ type ForceReply struct {
 
 type InlineKeyboardMarkup struct {
 	Keyboard        [][]InlineKeyboardButton `json:"inline_keyboard"`
-	OneTimeKeyboard bool                     `json:"one_time_keyboard"`
+	OneTimeKeyboard bool                     `json:"one_time_keyboard,omitempty"`
 }
 
 type InlineKeyboardButton struct {
 	Text         string `json:"text"`
 	CallbackData string `json:"callback_data"`
 }
+
+// InputFile файл для отправки в Telegram: либо данные Data с именем Name,
+// которые загружаются через multipart/form-data, либо file_id или URL уже доступного файла в FileID.
+type InputFile struct {
+	Name   string
+	Data   io.Reader
+	FileID string
+}
+
+// NewInputFileID возвращает файл, уже доступный Telegram по file_id или URL.
+func NewInputFileID(fileID string) InputFile {
+	return InputFile{FileID: fileID}
+}
+
+// isUpload показывает, нужно ли загружать данные файла.
+func (f InputFile) isUpload() bool {
+	return f.Data != nil
+}
+
+// InputMedia элемент альбома для SendMediaGroup.
+type InputMedia struct {
+	Type      MediaType
+	Media     InputFile
+	Caption   string
+	ParseMode ParseMode
+}
+
+type MediaType string
+
+const (
+	MediaPhoto    MediaType = "photo"
+	MediaDocument MediaType = "document"
+	MediaAudio    MediaType = "audio"
+	MediaVideo    MediaType = "video"
+)
+
+// inputMedia элемент альбома в формате Bot API.
+type inputMedia struct {
+	Type      MediaType `json:"type"`
+	Media     string    `json:"media"`
+	Caption   string    `json:"caption,omitempty"`
+	ParseMode ParseMode `json:"parse_mode,omitempty"`
+}
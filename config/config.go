@@ -12,7 +12,9 @@ import (
 type Config struct {
 	Env           string `yaml:"env"`
 	TelegramToken string `env:"TELEGRAM_TOKEN"`
-	AdminsID      []int
+	// GoogleCredentials путь к ключу сервисного аккаунта Google Calendar.
+	GoogleCredentials string `env:"GOOGLE_CREDENTIALS_FILE" env-default:"clients/google-calendar/credentials.json"`
+	AdminsID          []int
 	PostgresSettings
 	PgAdminSettings
 }
@@ -1,7 +1,9 @@
 package event_consumer
 
 import (
+	"fmt"
 	"log"
+	"runtime/debug"
 	"tg_ics_useful_bot/events"
 	"time"
 )
@@ -45,7 +47,7 @@ func (c Consumer) Start() error {
 
 func (c *Consumer) handleEvents(events []events.Event) error {
 	for _, event := range events {
-		if err := c.processor.Process(event); err != nil {
+		if err := c.handleEvent(event); err != nil {
 			log.Printf("[ERROR] can't handle event: %s", err.Error())
 			continue
 		}
@@ -53,3 +55,13 @@ func (c *Consumer) handleEvents(events []events.Event) error {
 	}
 	return nil
 }
+
+// handleEvent обрабатывает одно событие, паника в обработчике не останавливает остальные.
+func (c *Consumer) handleEvent(event events.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return c.processor.Process(event)
+}
@@ -0,0 +1,188 @@
+package telegram
+
+import (
+	"errors"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/e"
+)
+
+// Response ответ бота на команду или нажатие inline кнопки: действия выполняются по порядку.
+type Response struct {
+	actions []action
+	// usageError ответ - подсказка о неправильных аргументах, команда на самом деле не выполнялась.
+	usageError bool
+}
+
+// newResponse создаёт ответ из действий.
+func newResponse(actions ...action) *Response {
+	return &Response{actions: actions}
+}
+
+// textResponse ответ одним текстовым сообщением. replyTo - сообщение, на которое отвечает бот,
+// если он отвечает не на сообщение с командой, то оно удаляется.
+func textResponse(text string, replyTo int) *Response {
+	return newResponse(sendTextAction{text: text, replyTo: replyTo})
+}
+
+// add добавляет действия в конец ответа.
+func (r *Response) add(actions ...action) *Response {
+	r.actions = append(r.actions, actions...)
+	return r
+}
+
+// repliesTo показывает, отправляет ли ответ сообщение в ответ на messageID.
+func (r *Response) repliesTo(messageID int) bool {
+	for _, a := range r.actions {
+		if send, ok := a.(sendTextAction); ok && send.replyTo == messageID {
+			return true
+		}
+	}
+	return false
+}
+
+// answersCallback показывает, отвечает ли ответ на нажатие inline кнопки сам.
+func (r *Response) answersCallback() bool {
+	for _, a := range r.actions {
+		if _, ok := a.(answerCallbackAction); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// actionTarget сообщение, в ответ на которое выполняются действия.
+type actionTarget struct {
+	chatID int
+	// messageID сообщение с командой или с нажатой inline кнопкой.
+	messageID  int
+	callbackID string
+	// lastSentID последнее сообщение, отправленное действиями ответа.
+	lastSentID int
+}
+
+// action действие бота: отправка, изменение, удаление или закрепление сообщения.
+type action interface {
+	do(p *Processor, target *actionTarget) error
+}
+
+// doActions выполняет действия ответа по порядку, останавливаясь на первой ошибке.
+func (p *Processor) doActions(response *Response, target *actionTarget) error {
+	for _, a := range response.actions {
+		if err := a.do(p, target); err != nil {
+			return e.Wrap("can't do response action", err)
+		}
+	}
+	return nil
+}
+
+// sendTextAction отправляет текстовое сообщение, слишком длинный текст разбивается на несколько.
+// chatID - чат для отправки, 0 - текущий чат.
+type sendTextAction struct {
+	chatID    int
+	text      string
+	parseMode telegram.ParseMode
+	replyTo   int
+	markup    *telegram.InlineKeyboardMarkup
+}
+
+func (a sendTextAction) do(p *Processor, target *actionTarget) error {
+	chatID := a.chatID
+	if chatID == 0 {
+		chatID = target.chatID
+	}
+	sent, err := p.sendLongMessage(chatID, a.text, a.parseMode, a.replyTo, a.markup)
+	if err != nil {
+		return err
+	}
+	if sent != nil && chatID == target.chatID {
+		target.lastSentID = sent.ID
+	}
+	return nil
+}
+
+// sendPhotoAction отправляет картинку с подписью: загружаемую или по ссылке (telegram.NewInputFileID).
+type sendPhotoAction struct {
+	photo     telegram.InputFile
+	caption   string
+	parseMode telegram.ParseMode
+}
+
+func (a sendPhotoAction) do(p *Processor, target *actionTarget) error {
+	return p.tg.SendPhoto(target.chatID, a.photo, a.caption, a.parseMode)
+}
+
+// sendDocumentAction отправляет файл с подписью.
+type sendDocumentAction struct {
+	document telegram.InputFile
+	caption  string
+}
+
+func (a sendDocumentAction) do(p *Processor, target *actionTarget) error {
+	return p.tg.SendDocument(target.chatID, a.document, a.caption)
+}
+
+// editTextAction изменяет текст и клавиатуру сообщения, messageID 0 - сообщение с нажатой кнопкой.
+type editTextAction struct {
+	messageID int
+	text      string
+	parseMode telegram.ParseMode
+	markup    *telegram.InlineKeyboardMarkup
+}
+
+func (a editTextAction) do(p *Processor, target *actionTarget) error {
+	return p.tg.EditMessageText(target.chatID, messageOrTarget(a.messageID, target.messageID), a.text, a.parseMode, a.markup)
+}
+
+// editMarkupAction изменяет только клавиатуру сообщения, nil убирает её.
+// messageID 0 - сообщение с нажатой кнопкой.
+type editMarkupAction struct {
+	messageID int
+	markup    *telegram.InlineKeyboardMarkup
+}
+
+func (a editMarkupAction) do(p *Processor, target *actionTarget) error {
+	return p.tg.EditMessageReplyMarkup(target.chatID, messageOrTarget(a.messageID, target.messageID), a.markup)
+}
+
+// deleteMessageAction удаляет сообщение, messageID 0 - сообщение с командой или кнопкой.
+type deleteMessageAction struct {
+	messageID int
+}
+
+func (a deleteMessageAction) do(p *Processor, target *actionTarget) error {
+	return p.tg.DeleteMessage(target.chatID, messageOrTarget(a.messageID, target.messageID))
+}
+
+// pinMessageAction закрепляет сообщение, messageID 0 - последнее отправленное ответом текстовое сообщение.
+type pinMessageAction struct {
+	messageID int
+	notify    bool
+}
+
+func (a pinMessageAction) do(p *Processor, target *actionTarget) error {
+	messageID := messageOrTarget(a.messageID, target.lastSentID)
+	if messageID == 0 {
+		return errors.New("no message to pin")
+	}
+	return p.tg.PinChatMessage(target.chatID, messageID, !a.notify)
+}
+
+// answerCallbackAction отвечает на нажатие inline кнопки, text показывается пользователю уведомлением.
+type answerCallbackAction struct {
+	text string
+}
+
+func (a answerCallbackAction) do(p *Processor, target *actionTarget) error {
+	if target.callbackID == "" {
+		return nil
+	}
+	return p.tg.AnswerCallbackQuery(target.callbackID, a.text)
+}
+
+// messageOrTarget возвращает messageID, а если он не задан - def.
+func messageOrTarget(messageID, def int) int {
+	if messageID == 0 {
+		return def
+	}
+	return messageID
+}
@@ -0,0 +1,216 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/lib/utils"
+	"tg_ics_useful_bot/storage"
+	"time"
+	"unicode"
+)
+
+// Способы сравнения сообщения с шаблоном правила автоответа.
+const (
+	// autoReplyRegex шаблон - регулярное выражение, которое ищется во всём сообщении.
+	autoReplyRegex = "regex"
+	// autoReplyRhyme шаблон - окончание последнего слова сообщения без учёта регистра.
+	autoReplyRhyme = "rhyme"
+)
+
+// autoReplyCooldownPrefix префикс команды в таблице cooldowns для ограничений автоответов.
+const autoReplyCooldownPrefix = "autoreply:"
+
+// autoReplyRule правило автоответа: на сообщение, подходящее под шаблон, бот отвечает reply.
+type autoReplyRule struct {
+	// name имя правила, по нему хранится cooldown: id для правил чата, слово для правил по умолчанию.
+	name    string
+	kind    string
+	pattern string
+	re      *regexp.Regexp
+	// reply текст ответа, у правил по умолчанию - ключ сообщения в каталоге.
+	reply     string
+	localized bool
+	// probability шанс ответа в процентах.
+	probability int
+	cooldown    time.Duration
+	// count считает сработавшее правило в статистике участника, nil - не считает.
+	count func(stats *storage.DBUserStat)
+}
+
+// defaultAutoReplies правила, которые действуют в каждом чате после правил чата.
+// Они отвечают рифмой на "да" и "нет" и считают такие сообщения в статистике.
+var defaultAutoReplies = []*autoReplyRule{
+	{
+		name:        "yes",
+		kind:        autoReplyRegex,
+		re:          regexp.MustCompile(`(?i)(^|\s)д+[аa]+\p{P}*[^\p{L}\p{N}_]*$`),
+		reply:       msgYesReply,
+		localized:   true,
+		probability: 100,
+		count:       func(stats *storage.DBUserStat) { stats.YesCount++ },
+	},
+	{
+		name:        "no",
+		kind:        autoReplyRegex,
+		re:          regexp.MustCompile(`(?i)(^|\s)н+[еe]+т+\p{P}*[^\p{L}\p{N}_]*$`),
+		reply:       msgNoReply,
+		localized:   true,
+		probability: 100,
+		count:       func(stats *storage.DBUserStat) { stats.NoCount++ },
+	},
+}
+
+// newAutoReplyRule собирает правило из настроек чата, проверяя шаблон.
+func newAutoReplyRule(r *storage.DBAutoReply) (*autoReplyRule, error) {
+	rule := &autoReplyRule{
+		name:        strconv.Itoa(r.ID),
+		kind:        r.Kind,
+		pattern:     r.Pattern,
+		reply:       r.Reply,
+		probability: r.Probability,
+		cooldown:    time.Duration(r.Cooldown) * time.Second,
+	}
+	switch r.Kind {
+	case autoReplyRegex:
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		rule.re = re
+	case autoReplyRhyme:
+		rule.pattern = strings.ToLower(r.Pattern)
+		if rule.pattern == "" || strings.IndexFunc(rule.pattern, isNotLetter) != -1 {
+			return nil, fmt.Errorf("rhyme %q must be letters only", r.Pattern)
+		}
+	default:
+		return nil, fmt.Errorf("unknown auto reply kind %q", r.Kind)
+	}
+	return rule, nil
+}
+
+// match показывает, подходит ли сообщение под шаблон правила.
+func (r *autoReplyRule) match(text string) bool {
+	if r.kind == autoReplyRhyme {
+		word := lastWord(text)
+		return word != "" && strings.HasSuffix(word, r.pattern)
+	}
+	return r.re.MatchString(text)
+}
+
+// text возвращает текст ответа на языке чата.
+func (r *autoReplyRule) text(req *Request) string {
+	if r.localized {
+		return req.Locale.Text(r.reply)
+	}
+	return r.reply
+}
+
+// isNotLetter показывает, что символ не буква.
+func isNotLetter(r rune) bool {
+	return !unicode.IsLetter(r)
+}
+
+// lastWord возвращает последнее слово сообщения в нижнем регистре без знаков препинания вокруг.
+func lastWord(text string) string {
+	words := strings.FieldsFunc(text, isNotLetter)
+	if len(words) == 0 {
+		return ""
+	}
+	return strings.ToLower(words[len(words)-1])
+}
+
+// autoReplyRules возвращает правила чата, а за ними правила по умолчанию.
+// Правила с испорченным шаблоном пропускаются.
+func (p *Processor) autoReplyRules(chatID int) ([]*autoReplyRule, error) {
+	dbRules, err := p.storage.ChatAutoReplies(context.Background(), chatID)
+	if err != nil {
+		return nil, e.Wrap(fmt.Sprintf("can't get auto replies in chat #%d", chatID), err)
+	}
+	rules := make([]*autoReplyRule, 0, len(dbRules)+len(defaultAutoReplies))
+	for _, r := range dbRules {
+		rule, err := newAutoReplyRule(r)
+		if err != nil {
+			log.Printf("skip auto reply #%d in chat #%d: %v", r.ID, chatID, err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return append(rules, defaultAutoReplies...), nil
+}
+
+// countAutoReplies считает в статистике сработавшие правила по умолчанию, даже если ответило другое правило.
+// Возвращает true, если статистика изменилась.
+func countAutoReplies(text string, stats *storage.DBUserStat) bool {
+	counted := false
+	for _, r := range defaultAutoReplies {
+		if r.count != nil && r.match(text) {
+			r.count(stats)
+			counted = true
+		}
+	}
+	return counted
+}
+
+// pickAutoReply возвращает первое подходящее правило, которое выпало по вероятности и не ограничено
+// cooldown, или nil. Ограничение сработавшего правила сразу сохраняется.
+func (p *Processor) pickAutoReply(chatID int, text string, rules []*autoReplyRule, now time.Time) (*autoReplyRule, error) {
+	for _, r := range rules {
+		if !r.match(text) || rand.Intn(100) >= r.probability {
+			continue
+		}
+		if r.cooldown <= 0 {
+			return r, nil
+		}
+
+		command := autoReplyCooldownPrefix + r.name
+		cd, err := p.storage.GetCooldown(context.Background(), chatID, 0, command)
+		if err != nil && err != storage.ErrCooldownNotExist {
+			return nil, e.Wrap(fmt.Sprintf("can't check cooldown for %s", command), err)
+		}
+		if err == nil && now.Before(cd.ExpiresAt) {
+			continue
+		}
+		cd = &storage.DBCooldown{ChatID: chatID, Command: command, ExpiresAt: now.Add(r.cooldown)}
+		if err := p.storage.SaveCooldown(context.Background(), cd); err != nil {
+			log.Print(err)
+		}
+		return r, nil
+	}
+	return nil, nil
+}
+
+// autoReplies отвечает на сообщения по правилам автоответов чата и правилам по умолчанию,
+// а ответы на "да" и "нет" считает в статистике. Команды не проверяются, чтобы правило
+// вроде "." не мешало его удалить, ответы в начатых диалогах до автоответов не доходят.
+// Выключается настройкой чата AutoReplies.
+func autoReplies(next Handler) Handler {
+	return func(p *Processor, req *Request) (*Response, error) {
+		if !req.Settings.AutoReplies || utils.IsCommand(req.Text) {
+			return next(p, req)
+		}
+		rules, err := p.autoReplyRules(req.Chat.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if countAutoReplies(req.Text, req.UserStats) {
+			if err := p.storage.UpdateUserStats(context.Background(), req.UserStats); err != nil {
+				log.Print(err)
+			}
+		}
+		rule, err := p.pickAutoReply(req.Chat.ID, req.Text, rules, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if rule == nil {
+			return next(p, req)
+		}
+		return textResponse(rule.text(req), req.MessageID), nil
+	}
+}
@@ -0,0 +1,122 @@
+package telegram
+
+import (
+	"testing"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/storage"
+	"time"
+)
+
+func TestCountAutoReplies(t *testing.T) {
+	tests := []struct {
+		text      string
+		yes, no   int
+		wantCount bool
+	}{
+		{"да", 1, 0, true},
+		{"ддддда", 1, 0, true},
+		{"дддддаааааааа", 1, 0, true},
+		{"дддддаааааааа.", 1, 0, true},
+		{"     дддддаааааааа .", 1, 0, true},
+		{"    дааа  .", 1, 0, true},
+		{"    дааааа.  .", 1, 0, true},
+		{"Да!", 1, 0, true},
+
+		{"     да всем здарова пидоры .", 0, 0, false},
+		{"ддддддддд", 0, 0, false},
+		{"аааааа", 0, 0, false},
+		{"ф да залупа. .", 0, 0, false},
+		{"   даб даб даб ", 0, 0, false},
+		{" ", 0, 0, false},
+		{"фывыфвфвнннннннннннеееетттттттт", 0, 0, false},
+
+		{"нет", 0, 1, true},
+		{"нет...", 0, 1, true},
+		{"неееет", 0, 1, true},
+		{"неееетттттттт", 0, 1, true},
+		{"нннннннннннеееетттттттт", 0, 1, true},
+		{"  фыфыв в  ы   нннннннннннеееетттттттт.", 0, 1, true},
+	}
+	for _, tt := range tests {
+		stats := &storage.DBUserStat{}
+		counted := countAutoReplies(tt.text, stats)
+		if counted != tt.wantCount || stats.YesCount != tt.yes || stats.NoCount != tt.no {
+			t.Errorf("%q: counted %v, yes %d, no %d; want %v, %d, %d",
+				tt.text, counted, stats.YesCount, stats.NoCount, tt.wantCount, tt.yes, tt.no)
+		}
+	}
+}
+
+func TestAutoReplyRuleMatch(t *testing.T) {
+	tests := []struct {
+		kind, pattern, text string
+		want                bool
+	}{
+		{autoReplyRhyme, "ой", "Ну и зной", true},
+		{autoReplyRhyme, "ОЙ", "ой!!!", true},
+		{autoReplyRhyme, "ой", "ой, ну и жара", false},
+		{autoReplyRhyme, "ой", "🙂 ...", false},
+		{autoReplyRegex, `(?i)привет`, "Всем ПРИВЕТ в этом чате", true},
+		{autoReplyRegex, `^\d+$`, "42 и не больше", false},
+	}
+	for _, tt := range tests {
+		rule, err := newAutoReplyRule(&storage.DBAutoReply{Kind: tt.kind, Pattern: tt.pattern, Probability: 100})
+		if err != nil {
+			t.Fatalf("%s %q: %v", tt.kind, tt.pattern, err)
+		}
+		if got := rule.match(tt.text); got != tt.want {
+			t.Errorf("%s %q on %q: got %v, want %v", tt.kind, tt.pattern, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNewAutoReplyRuleInvalid(t *testing.T) {
+	for _, r := range []*storage.DBAutoReply{
+		{Kind: autoReplyRegex, Pattern: "(да"},
+		{Kind: autoReplyRhyme, Pattern: "ой!"},
+		{Kind: autoReplyRhyme, Pattern: ""},
+		{Kind: "glob", Pattern: "*"},
+	} {
+		if _, err := newAutoReplyRule(r); err == nil {
+			t.Errorf("%s %q: expected error", r.Kind, r.Pattern)
+		}
+	}
+}
+
+func TestAutoRepliesSkipDialogs(t *testing.T) {
+	const chatID, userID = -100, 1
+	s := newFakeStorage()
+	s.users = []*storage.DBUser{{TgID: userID, ChatID: chatID, Username: "user", Active: true}}
+	// правило чата отвечает на любое сообщение
+	s.replies = []*storage.DBAutoReply{{ID: 1, ChatID: chatID, Kind: autoReplyRegex, Pattern: ".", Reply: "эхо", Probability: 100}}
+	p := &Processor{storage: s}
+	handler := chain(execCommand, allMiddlewares...)
+	request := func(text string) *Request {
+		return &Request{
+			Text:      text,
+			Chat:      &telegram.Chat{ID: chatID},
+			User:      &telegram.User{ID: userID, Username: "user"},
+			MessageID: 7,
+		}
+	}
+
+	response, err := handler(p, request("всем привет"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := textResponse("эхо", 7); response == nil || response.actions[0] != want.actions[0] {
+		t.Fatalf("without dialog: got %+v, want auto reply", response)
+	}
+
+	s.dialogs = []*storage.DBDialogState{{ChatID: chatID, TgID: userID, Dialog: addHomeworkDialog.Name, Data: "{}", UpdatedAt: time.Now()}}
+	response, err = handler(p, request("Физика"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := textResponse(ruLocale.Text(msgAddTask), 7); response == nil || response.actions[0] != want.actions[0] {
+		t.Errorf("with dialog: got %+v, want the next dialog question", response)
+	}
+	if len(s.dialogs) != 1 || s.dialogs[0].Step != 1 || s.dialogs[0].Data != `{"subject":"Физика"}` {
+		t.Errorf("dialog state: %+v", s.dialogs)
+	}
+}
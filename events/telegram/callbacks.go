@@ -0,0 +1,54 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/e"
+)
+
+// callbackSeparator разделяет префикс обработчика и данные в callback_data.
+const callbackSeparator = ":"
+
+// maxCallbackDataLength ограничение Telegram на длину callback_data в байтах.
+const maxCallbackDataLength = 64
+
+// CallbackExecutor предоставляет интерфейс с методом Exec
+// для обработки нажатия на inline кнопку.
+type CallbackExecutor interface {
+	Exec(p *Processor, data string, user *telegram.User, chat *telegram.Chat, messageID int) (*Response, error)
+}
+
+// allCallbacks список всех обработчиков inline кнопок по префиксу callback_data.
+var allCallbacks = map[string]CallbackExecutor{
+	homeworkPageCallback: homeworkPageExec(homeworkPageCallback),
+	settingsCallback:     settingsToggleExec(settingsCallback),
+}
+
+// callbackData формирует callback_data для inline кнопки.
+func callbackData(prefix string, args ...string) string {
+	return strings.Join(append([]string{prefix}, args...), callbackSeparator)
+}
+
+// doCallback выбирает обработчик для нажатой inline кнопки и выполняет действия его ответа.
+func (p *Processor) doCallback(data string, chat *telegram.Chat, user *telegram.User, messageID int, callbackID string) error {
+	prefix, args, _ := strings.Cut(data, callbackSeparator)
+
+	cb, ok := allCallbacks[prefix]
+	if !ok {
+		return e.Wrap(fmt.Sprintf("can't get callback from %s", data), ErrUnknownEventType)
+	}
+
+	response, err := cb.Exec(p, args, user, chat, messageID)
+	if err != nil {
+		return e.Wrap(fmt.Sprintf("can't exec callback: %s", data), err)
+	}
+	if !response.answersCallback() {
+		if answerErr := p.tg.AnswerCallbackQuery(callbackID, ""); answerErr != nil {
+			log.Print(answerErr)
+		}
+	}
+
+	return p.doActions(response, &actionTarget{chatID: chat.ID, messageID: messageID, callbackID: callbackID})
+}
@@ -2,12 +2,9 @@ package telegram
 
 import (
 	"context"
-	"errors"
 	"log"
-	"strconv"
-	"strings"
 	"tg_ics_useful_bot/clients/telegram"
-	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/lib/cmdargs"
 	"tg_ics_useful_bot/storage"
 )
 
@@ -16,25 +13,10 @@ import (
 type adminSendMessageExec string
 
 // Exec: /send_message {chat_id} {message}
-func (a adminSendMessageExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+func (a adminSendMessageExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 
-	if !p.isAdmin(user.ID) {
-		return nil, e.Wrap("no admin can't do this cmd (/send_message)", errors.New("can't do this cmd"))
-	}
-
-	strs := strings.Split(inMessage, " ")
-	chatIDStr, message := strs[1], strings.Join(strs[2:], " ")
-	chatID, err := strconv.Atoi(chatIDStr)
-	if err != nil {
-		log.Print(err)
-	}
-	err = p.tg.SendMessage(chatID, message, "", -1)
-	if err != nil {
-		log.Println("can't send message by admin:", err)
-	}
-	mthd := doNothingMethod
-	return &Response{message: message, method: mthd, replyMessageId: -1}, nil
+	return newResponse(sendTextAction{chatID: params.Int("chat_id"), text: params.String("message")}), nil
 }
 
 // adminChangeDickExec предоставляет метод Exec для выполнения команды /change_dick.
@@ -42,38 +24,20 @@ func (a adminSendMessageExec) Exec(p *Processor, inMessage string, user *telegra
 type adminChangeDickExec string
 
 // Exec: /change_dick {chat_id} {user_id} {value}
-func (a adminChangeDickExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
-
-	if !p.isAdmin(user.ID) {
-		return nil, e.Wrap("no admin can't do this cmd (/send_message)", errors.New("can't do this cmd"))
-	}
+func (a adminChangeDickExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 
-	strs := strings.Split(inMessage, " ")
-	chatIDStr, userIDStr, valueStr := strs[1], strs[2], strs[3]
-	err := p.changeDickByAdminCmd(chatIDStr, userIDStr, valueStr)
+	lang := p.locale(chat.ID)
+	err := p.changeDickByAdminCmd(params.Int("chat_id"), params.Int("user_id"), params.Int("value"))
 	if err != nil {
 		return nil, err
 	}
-	message := msgSuccessAdminChangeDickSize
-	mthd := sendMessageMethod
-	return &Response{message: message, method: mthd, replyMessageId: -1}, nil
+	message := lang.Text(msgSuccessAdminChangeDickSize)
+	return textResponse(message, 0), nil
 }
 
 // changeDickByAdminCmd админская ручка, позволяющая изменить пенис любому пользователю.
-func (p *Processor) changeDickByAdminCmd(chatIDStr, userIDStr, valueStr string) error {
-	chatID, err := strconv.Atoi(chatIDStr)
-	if err != nil {
-		return err
-	}
-	userID, err := strconv.Atoi(userIDStr)
-	if err != nil {
-		return err
-	}
-	value, err := strconv.Atoi(valueStr)
-	if err != nil {
-		return err
-	}
+func (p *Processor) changeDickByAdminCmd(chatID, userID, value int) error {
 	dbUser, err := p.storage.GetUser(context.Background(), userID, chatID)
 	if err != nil {
 		return err
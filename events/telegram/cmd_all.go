@@ -3,6 +3,7 @@ package telegram
 import (
 	"log"
 	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
 	"tg_ics_useful_bot/storage"
 )
 
@@ -10,17 +11,16 @@ import (
 type allUsernamesExec string
 
 // Exec: /all - тэгает всех админов в чате.
-func (a allUsernamesExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+func (a allUsernamesExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 
 	message := p.allUsernames(chat.ID)
-	mthd := sendMessageMethod
-	return &Response{message: message, method: mthd, replyMessageId: -1}, nil
+	return textResponse(message, 0), nil
 }
 
 // allUsernames возвращает строку "@username1, @username2...".
 func (p *Processor) allUsernames(chatID int) string {
-	admins, err := p.tg.ChatAdministrators(chatID)
+	admins, err := p.chatAdmins(chatID)
 	if err != nil {
 		log.Printf("can't get admins in chat #%d: %v", chatID, err)
 	}
@@ -30,17 +30,3 @@ func (p *Processor) allUsernames(chatID int) string {
 	}
 	return result[:len(result)-1]
 }
-
-// isChatAdmin определяет является ли пользователь админов в чате.
-func (p *Processor) isChatAdmin(user *telegram.User, chatID int) bool {
-	admins, err := p.tg.ChatAdministrators(chatID)
-	if err != nil {
-		log.Printf("can't get admins in chat #%d: %v", chatID, err)
-	}
-	for _, admin := range admins {
-		if user.ID == admin.ID {
-			return true
-		}
-	}
-	return false
-}
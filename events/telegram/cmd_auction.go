@@ -2,13 +2,12 @@ package telegram
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"math/rand"
-	"strconv"
-	"strings"
 	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
 	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/lib/i18n"
 	"tg_ics_useful_bot/storage"
 	"time"
 )
@@ -32,60 +31,48 @@ type startAuctionExec string
 // и завершаться по истечению этого времени
 
 // Exec: /start_auction - запускает аукцион в чате, в котором указана данная команда.
-func (a startAuctionExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
-
-	//if !p.isAdmin(user.ID) {
-	//	return nil, e.Wrap("no admin can't do this cmd (/start_auction)", errors.New("can't do this cmd"))
-	//}
+func (a startAuctionExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 
+	lang := p.locale(chat.ID)
 	if _, ok := auctions[chat.ID]; ok {
-		return &Response{message: msgAuctionIsStarted, method: sendMessageMethod}, nil
+		return textResponse(lang.Text(msgAuctionIsStarted), 0), nil
 	}
 
 	auctions[chat.ID] = make([]*AuctionPlayer, 0)
-	return &Response{message: fmt.Sprintf(msgStartAuction, MAX_DEPOSIT), method: sendMessageMethod, parseMode: telegram.Markdown}, nil
+	return newResponse(sendTextAction{text: lang.Text(msgStartAuction, MAX_DEPOSIT), parseMode: telegram.Markdown}), nil
 }
 
 // addDeposit предоставляет метод Exec для внесения депозита в ауцион.
 type addDepositExec string
 
 // Exec: /deposit {amount} - вносит депозит в текущий аукцион. Amount - обязательный параметр.
-func (a addDepositExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+func (a addDepositExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 
-	message, err := p.addDeposit(inMessage, user, chat)
+	message, err := p.addDeposit(params.Int("amount"), user, chat)
 	if err != nil {
 		return nil, e.Wrap("can't exec /deposit", err)
 	}
-	mthd := sendMessageMethod
-	return &Response{message: message, method: mthd, replyMessageId: messageID}, nil
+	return textResponse(message, messageID), nil
 }
 
 // addDeposit возвращает сообщание для телеграм чата, после команды /deposit {amount}.
-func (p *Processor) addDeposit(inMessage string, user *telegram.User, chat *telegram.Chat) (string, error) {
+func (p *Processor) addDeposit(deposit int, user *telegram.User, chat *telegram.Chat) (string, error) {
+	lang := p.locale(chat.ID)
 	dbUser, err := p.storage.GetUser(context.Background(), user.ID, chat.ID)
 	if err != nil {
 		return "", err
 	}
 
 	if _, ok := auctions[chat.ID]; !ok {
-		return msgAuctionNotStarted, nil
-	}
-
-	strs := strings.Fields(inMessage)
-	if len(strs) < 2 {
-		return msgErrorDepositCmd, nil
+		return lang.Text(msgAuctionNotStarted), nil
 	}
 
-	deposit, err := strconv.Atoi(strs[1])
-	if err != nil {
-		return msgErrorDepositCmd, nil
-	}
 	player := getPlayer(dbUser)
 
 	if !p.canDeposit(deposit, dbUser, player) {
-		return msgErrorDeposit, nil
+		return lang.Text(msgErrorDeposit), nil
 	}
 
 	err = p.changeDickSize(dbUser, -deposit)
@@ -94,7 +81,7 @@ func (p *Processor) addDeposit(inMessage string, user *telegram.User, chat *tele
 	}
 	player.deposit += deposit
 
-	return fmt.Sprintf(msgSuccessDeposit, deposit), nil
+	return lang.Text(msgSuccessDeposit, deposit), nil
 }
 
 // canDeposit проверяет может ли участник положить столько см пениса в аукцион.
@@ -124,41 +111,37 @@ func getPlayer(user *storage.DBUser) *AuctionPlayer {
 }
 
 // finishAuctionExec предоставляет метод Exec для завершения аукциона в чате.
-// Только для админов бота.
 type finishAuctionExec string
 
-// Exec: /finish_auction - запускает аукцион в чате, в котором указана данная команда.
-func (a finishAuctionExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
-
-	if !p.isAdmin(user.ID) {
-		return nil, e.Wrap("no admin can't do this cmd (/finish_auction)", errors.New("can't do this cmd"))
-	}
+// Exec: /finish_auction - завершает аукцион в чате, в котором указана данная команда.
+func (a finishAuctionExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 
 	message, err := p.finishAuction(chat.ID)
 	if err != nil {
 		return nil, e.Wrap("can't finish duel", err)
 	}
 
-	return &Response{message: message, method: sendMessageMethod, parseMode: telegram.Markdown}, nil
+	return newResponse(sendTextAction{text: message, parseMode: telegram.Markdown}), nil
 }
 
 // finishAuction случайным образом выбирает победителя из всех игроков аукциона.
 func (p *Processor) finishAuction(chatID int) (string, error) {
+	lang := p.locale(chatID)
 	if _, ok := auctions[chatID]; !ok {
-		return msgAuctionNotStarted, nil
+		return lang.Text(msgAuctionNotStarted), nil
 	}
 
 	players := auctions[chatID]
 	if len(players) == 0 {
-		return msgNotEnoughPlayers, nil
+		return lang.Text(msgNotEnoughPlayers), nil
 	}
 
 	winner, reward := getAuctionWinnerAndReward(auctions[chatID])
 	delete(auctions, chatID)
 
 	for i := 5; i > 0; i-- {
-		p.tg.SendMessage(chatID, fmt.Sprintf("До результата аукциона: %d!", i), "", -1)
+		p.tg.SendMessage(chatID, lang.Text(msgAuctionCountdown, i), "", -1)
 		time.Sleep(1 * time.Second)
 	}
 
@@ -166,7 +149,7 @@ func (p *Processor) finishAuction(chatID int) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return fmt.Sprintf(msgWinner, winner.Username, reward), nil
+	return lang.Text(msgWinner, winner.Username, reward), nil
 }
 
 // getAuctionWinnerAndReward случайным образом определяет победителя аукциона.
@@ -199,31 +182,31 @@ func getAuctionWinnerAndReward(players []*AuctionPlayer) (*storage.DBUser, int)
 type auctionExec string
 
 // Exec: /auction - возвращает список всех участников текущего аукциона.
-func (a auctionExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+func (a auctionExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 
+	lang := p.locale(chat.ID)
 	var message string
-	mthd := sendMessageMethod
 	parseMode := telegram.Markdown
 
 	if _, ok := auctions[chat.ID]; !ok {
-		return &Response{message: msgAuctionNotStarted, method: mthd, parseMode: parseMode}, nil
+		return newResponse(sendTextAction{text: lang.Text(msgAuctionNotStarted), parseMode: parseMode}), nil
 	}
 
-	message = getAuctionPlayers(chat.ID)
+	message = getAuctionPlayers(lang, chat.ID)
 
-	return &Response{message: message, method: mthd, parseMode: parseMode}, nil
+	return newResponse(sendTextAction{text: message, parseMode: parseMode}), nil
 }
 
 // getAuctionPlayers возвращает список текущих участников аукциона.
-func getAuctionPlayers(chatID int) string {
+func getAuctionPlayers(lang *i18n.Locale, chatID int) string {
 	players := auctions[chatID]
 
 	if len(players) == 0 {
-		return msgZeroPlayers
+		return lang.Text(msgZeroPlayers)
 	}
 
-	message := "Текущие игроки аукциона:\n\n"
+	message := lang.Text(msgAuctionPlayersHeader)
 	reward := 0
 
 	for _, p := range players {
@@ -236,6 +219,6 @@ func getAuctionPlayers(chatID int) string {
 			message += "=Ð*\n"
 		}
 	}
-	message += fmt.Sprintf("\nТекущий фонд *%d см*!", reward)
+	message += lang.Text(msgAuctionFund, reward)
 	return message
 }
@@ -0,0 +1,120 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/lib/i18n"
+	"tg_ics_useful_bot/storage"
+	"time"
+)
+
+// autoReplyAddArgs аргументы /autoreply add.
+var autoReplyAddArgs = cmdargs.Spec{
+	cmdargs.Choice("kind", autoReplyRegex, autoReplyRhyme),
+	cmdargs.String("pattern"),
+	cmdargs.Int("probability").Default("100"),
+	cmdargs.Duration("cooldown").Optional(),
+	cmdargs.Rest("reply"),
+}
+
+// autoReplyRemoveArgs аргументы /autoreply remove.
+var autoReplyRemoveArgs = cmdargs.Spec{cmdargs.Int("id")}
+
+// autoReplyExec предоставляет метод Exec для выполнения /autoreply.
+type autoReplyExec string
+
+// Exec: /autoreply [add {regex|rhyme} {pattern} [probability] [cooldown] {reply} | remove {id}] -
+// показывает, добавляет или удаляет автоответы чата.
+func (a autoReplyExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+
+	lang := p.locale(chat.ID)
+	cmd := commandByName(string(a))
+	switch params.String("action") {
+	case "add":
+		args, err := autoReplyAddArgs.Parse(params.String("rule"))
+		if err != nil {
+			return argsErrorResponse(lang, cmd, err, messageID), nil
+		}
+		return p.addAutoReply(lang, chat.ID, args, messageID)
+	case "remove":
+		args, err := autoReplyRemoveArgs.Parse(params.String("rule"))
+		if err != nil {
+			return argsErrorResponse(lang, cmd, err, messageID), nil
+		}
+		id := args.Int("id")
+		err = p.storage.DeleteAutoReply(context.Background(), chat.ID, id)
+		if err == storage.ErrAutoReplyNotExist {
+			return textResponse(lang.Text(msgAutoReplyNotFound, id), messageID), nil
+		} else if err != nil {
+			return nil, e.Wrap("can't exec /autoreply", err)
+		}
+		return textResponse(lang.Text(msgAutoReplyRemoved, id), 0), nil
+	}
+
+	if params.Has("rule") {
+		argsErr := &cmdargs.Error{Arg: cmd.Args[0], Value: params.String("rule"), Err: cmdargs.ErrInvalid}
+		return argsErrorResponse(lang, cmd, argsErr, messageID), nil
+	}
+	message, err := p.chatAutoReplies(lang, chat.ID)
+	if err != nil {
+		return nil, e.Wrap("can't exec /autoreply", err)
+	}
+	return textResponse(message, messageID), nil
+}
+
+// addAutoReply проверяет и сохраняет новое правило автоответа чата.
+func (p *Processor) addAutoReply(lang *i18n.Locale, chatID int, args cmdargs.Values, messageID int) (*Response, error) {
+	r := &storage.DBAutoReply{
+		ChatID:      chatID,
+		Kind:        args.String("kind"),
+		Pattern:     args.String("pattern"),
+		Reply:       args.String("reply"),
+		Probability: args.Int("probability"),
+		Cooldown:    int(args.Duration("cooldown").Seconds()),
+	}
+	if r.Probability < 1 || r.Probability > 100 {
+		return textResponse(lang.Text(msgAutoReplyBadProbability), messageID), nil
+	}
+	if _, err := newAutoReplyRule(r); err != nil {
+		return textResponse(lang.Text(msgAutoReplyBadPattern, r.Pattern), messageID), nil
+	}
+
+	id, err := p.storage.AddAutoReply(context.Background(), r)
+	if err != nil {
+		return nil, e.Wrap("can't exec /autoreply", err)
+	}
+	r.ID = id
+	return textResponse(lang.Text(msgAutoReplyAdded)+autoReplyLine(lang, r), 0), nil
+}
+
+// chatAutoReplies возвращает список автоответов чата.
+func (p *Processor) chatAutoReplies(lang *i18n.Locale, chatID int) (string, error) {
+	replies, err := p.storage.ChatAutoReplies(context.Background(), chatID)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(lang.Text(msgAutoRepliesHeader))
+	if len(replies) == 0 {
+		b.WriteString(lang.Text(msgNoAutoReplies))
+	}
+	for _, r := range replies {
+		b.WriteString(autoReplyLine(lang, r))
+	}
+	b.WriteString(lang.Text(msgAutoRepliesFooter))
+	return b.String(), nil
+}
+
+// autoReplyLine возвращает правило одной строкой: номер, шаблон, ответ, шанс и ограничение.
+func autoReplyLine(lang *i18n.Locale, r *storage.DBAutoReply) string {
+	cooldown := lang.Text(msgCooldownNone)
+	if r.Cooldown > 0 {
+		cooldown = lang.Text(msgAutoReplyCooldown, shortDurationText(lang, time.Duration(r.Cooldown)*time.Second))
+	}
+	return lang.Text(msgAutoReplyLine, r.ID, r.Kind, r.Pattern, r.Reply, r.Probability, cooldown)
+}
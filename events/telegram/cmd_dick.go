@@ -6,6 +6,7 @@ import (
 	"log"
 	"math/rand"
 	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
 	"tg_ics_useful_bot/lib/e"
 	"tg_ics_useful_bot/storage"
 	"time"
@@ -15,32 +16,31 @@ import (
 type dickTopExec string
 
 // Exec: /top_dick - пишет топ всех пенисов в чат.
-func (a dickTopExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+func (a dickTopExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 	message, err := p.topDicksCmd(chat.ID)
 	if err != nil {
 		return nil, e.Wrap(fmt.Sprintf("can't get top dics from chat %d: ", chat.ID), err)
 	}
-	mthd := sendMessageMethod
-	return &Response{message: message, method: mthd, replyMessageId: -1, parseMode: telegram.Markdown}, nil
+	return newResponse(sendTextAction{text: message, parseMode: telegram.Markdown}), nil
 }
 
 // dickStartExec предоставляет метод Exec для выполнения /dick.
 type dickStartExec string
 
 // Exec: /dick - игра в пенис.
-func (a dickStartExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+func (a dickStartExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 	message, err := p.gameDickCmd(chat, user, userStats)
 	if err != nil {
 		return nil, e.Wrap("can't get message from gameDickCmd: ", err)
 	}
-	mthd := sendMessageMethod
-	return &Response{message: message, method: mthd, replyMessageId: -1}, nil
+	return textResponse(message, 0), nil
 }
 
 // topDicksCmd возвращает string сообщение со списком всех dick > 0 в чате.
 func (p *Processor) topDicksCmd(chatID int) (msg string, err error) {
+	lang := p.locale(chatID)
 	users, err := p.storage.UsersByChat(context.Background(), chatID)
 	if err != nil {
 		return "", e.Wrap("[ERROR] can't get users: ", err)
@@ -50,9 +50,9 @@ func (p *Processor) topDicksCmd(chatID int) (msg string, err error) {
 	for i, u := range users {
 		if u.DickSize > 0 {
 			if i == 0 {
-				result += fmt.Sprintf("👑 *%s* — _%d см_\n", u.FirstName+" "+u.LastName, u.DickSize)
+				result += lang.Text(msgDickTopLeader, u.FirstName+" "+u.LastName, u.DickSize)
 			} else {
-				result += fmt.Sprintf("%d. %s — %d см\n", i+1, u.FirstName+" "+u.LastName, u.DickSize)
+				result += lang.Text(msgDickTopLine, i+1, u.FirstName+" "+u.LastName, u.DickSize)
 			}
 		}
 	}
@@ -64,6 +64,7 @@ func (p *Processor) topDicksCmd(chatID int) (msg string, err error) {
 // Возвращает сообщение, отправляемое в чат.
 func (p *Processor) gameDickCmd(chat *telegram.Chat, user *telegram.User, userStats *storage.DBUserStat) (msg string, err error) {
 	defer func() { err = e.WrapIfErr("error in gameDickCmd: ", err) }()
+	lang := p.locale(chat.ID)
 
 	dbUser, err := p.storage.GetUser(context.Background(), user.ID, chat.ID)
 	if err != nil {
@@ -82,11 +83,11 @@ func (p *Processor) gameDickCmd(chat *telegram.Chat, user *telegram.User, userSt
 			return "", err
 		}
 		if oldDickSize == 0 {
-			return fmt.Sprintf(msgCreateUser, dbUser.Username) + fmt.Sprintf(msgDickSize, dbUser.DickSize), nil
+			return lang.Text(msgCreateUser, dbUser.Username) + lang.Text(msgDickSize, dbUser.DickSize), nil
 		}
-		return fmt.Sprintf(msgChangeDickSize, dbUser.Username, oldDickSize, dbUser.DickSize), nil
+		return lang.Text(msgChangeDickSize, dbUser.Username, oldDickSize, dbUser.DickSize), nil
 	}
-	return fmt.Sprintf(msgAlreadyPlays, dbUser.Username), nil
+	return lang.Text(msgAlreadyPlays, dbUser.Username), nil
 }
 
 // updateRandomDickAndChangeTime изменяет значение пениса на слуайное число и время его изменения в базе данных.
@@ -127,11 +128,9 @@ func (p *Processor) updateRandomDickAndChangeTime(user *storage.DBUser, userStat
 }
 
 // canChangeDickSize - может ли пользователь изменить пенис сегодня. (остались ли у него попытки)
-// Обновляет попытки каждый день до 0.
+// Обновляет попытки каждый день до 0, день считается в часовом поясе чата.
 func (p *Processor) canChangeDickSize(user *storage.DBUser) (bool, error) {
-	yearLastTry, monthLastTry, dayLastTry := user.ChangeDickAt.Date()
-	year, month, today := time.Now().Date()
-	if (month == monthLastTry && today > dayLastTry) || month > monthLastTry || year > yearLastTry {
+	if isNewDay(user.ChangeDickAt, p.chatNow(user.ChatID)) {
 		user.CurDickChangeCount = 0
 		err := p.storage.UpdateUser(context.Background(), user)
 		if err != nil {
@@ -0,0 +1,205 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/lib/i18n"
+	"tg_ics_useful_bot/storage"
+	"time"
+)
+
+const (
+	digestTimeLayout = "15:04"
+	digestPeriod     = 7 * 24 * time.Hour
+
+	digestOff = "off"
+	digestPin = "pin"
+)
+
+// weekdayAliases сокращения дней недели, которые понимает бот.
+var weekdayAliases = map[string]time.Weekday{
+	"пн": time.Monday, "вт": time.Tuesday, "ср": time.Wednesday, "чт": time.Thursday,
+	"пт": time.Friday, "сб": time.Saturday, "вс": time.Sunday,
+	"mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday, "thu": time.Thursday,
+	"fri": time.Friday, "sat": time.Saturday, "sun": time.Sunday,
+}
+
+// parseWeekday возвращает день недели по его названию на любом языке бота или сокращению.
+func parseWeekday(text string) (time.Weekday, bool) {
+	text = strings.ToLower(text)
+	if day, ok := weekdayAliases[text]; ok {
+		return day, true
+	}
+	for _, l := range locales {
+		for day := range weekdayKeys {
+			if strings.ToLower(weekdayName(l, day)) == text {
+				return day, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// digestExec предоставляет метод Exec для выполнения /digest.
+type digestExec string
+
+// Exec: /digest [day HH:MM [pin] | off] - настраивает еженедельную сводку домашнего задания.
+// Без параметров показывает текущие настройки.
+func (a digestExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+
+	if !params.Has("day") {
+		message, err := p.digestSettings(chat.ID)
+		if err != nil {
+			return nil, err
+		}
+		return textResponse(message, messageID), nil
+	}
+
+	if params.String("day") == digestOff {
+		message, err := p.disableDigest(chat.ID)
+		if err != nil {
+			return nil, e.Wrap("can't disable digest", err)
+		}
+		return textResponse(message, messageID), nil
+	}
+
+	lang := p.locale(chat.ID)
+	cmd := commandByName(string(a))
+	day, ok := parseWeekday(params.String("day"))
+	if !ok {
+		return argsErrorResponse(lang, cmd, cmd.argError("day", params.String("day")), messageID), nil
+	}
+	sendTime, err := time.Parse(digestTimeLayout, params.String("time"))
+	if err != nil {
+		return argsErrorResponse(lang, cmd, cmd.argError("time", params.String("time")), messageID), nil
+	}
+
+	message, err := p.updateDigest(chat.ID, day, sendTime, params.Has("pin"))
+	if err != nil {
+		return nil, e.Wrap("can't update digest", err)
+	}
+	return textResponse(message, messageID), nil
+}
+
+// digestSettings возвращает описание текущих настроек сводки в чате.
+func (p *Processor) digestSettings(chatID int) (string, error) {
+	lang := p.locale(chatID)
+	d, err := p.storage.GetDigest(context.Background(), chatID)
+	if err == storage.ErrDigestNotExist {
+		return lang.Text(msgDigestDisabled), nil
+	} else if err != nil {
+		return "", e.Wrap("can't get digest", err)
+	}
+	return lang.Text(msgDigestSettings, weekdayName(lang, time.Weekday(d.Weekday)), d.SendTime, pinText(lang, d.Pin)), nil
+}
+
+// disableDigest выключает сводку в чате.
+func (p *Processor) disableDigest(chatID int) (string, error) {
+	if err := p.storage.DeleteDigest(context.Background(), chatID); err != nil {
+		return "", err
+	}
+	return p.locale(chatID).Text(msgDigestDisabled), nil
+}
+
+// updateDigest включает или изменяет сводку: день недели, время отправки и закрепление.
+func (p *Processor) updateDigest(chatID int, day time.Weekday, sendTime time.Time, pin bool) (string, error) {
+	lang := p.locale(chatID)
+	d := &storage.DBDigest{
+		ChatID:   chatID,
+		Weekday:  int(day),
+		SendTime: sendTime.Format(digestTimeLayout),
+		Pin:      pin,
+		// не отправляем сводку сразу, если время на этой неделе уже прошло
+		LastSentAt: time.Now(),
+	}
+	if err := p.storage.SaveDigest(context.Background(), d); err != nil {
+		return "", err
+	}
+	return lang.Text(msgDigestSettings, weekdayName(lang, day), d.SendTime, pinText(lang, d.Pin)), nil
+}
+
+// pinText возвращает описание настройки закрепления сводки.
+func pinText(lang *i18n.Locale, pin bool) string {
+	if pin {
+		return lang.Text(msgDigestPinned)
+	}
+	return ""
+}
+
+// sendDigests отправляет сводки во все чаты, у которых наступило время отправки.
+func (p *Processor) sendDigests(now time.Time) error {
+	digests, err := p.storage.AllDigests(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, d := range digests {
+		now := now.In(p.chatLocation(d.ChatID))
+		if !digestDue(d, now) {
+			continue
+		}
+		if err = p.sendDigest(d, now); err != nil {
+			log.Printf("[ERROR] can't send digest to chat #%d: %v", d.ChatID, err)
+		}
+	}
+	return nil
+}
+
+// digestDue показывает, пора ли отправить сводку.
+func digestDue(d *storage.DBDigest, now time.Time) bool {
+	if time.Weekday(d.Weekday) != now.Weekday() {
+		return false
+	}
+	sendTime, err := time.Parse(digestTimeLayout, d.SendTime)
+	if err != nil {
+		return false
+	}
+	scheduled := time.Date(now.Year(), now.Month(), now.Day(), sendTime.Hour(), sendTime.Minute(), 0, 0, now.Location())
+	return !now.Before(scheduled) && d.LastSentAt.Before(scheduled)
+}
+
+// sendDigest отправляет в чат домашнее задание за последнюю неделю и при необходимости закрепляет его.
+func (p *Processor) sendDigest(d *storage.DBDigest, now time.Time) error {
+	d.LastSentAt = now
+	if err := p.storage.SaveDigest(context.Background(), d); err != nil {
+		return err
+	}
+
+	homeworks, err := p.storage.GetHomeworkByPeriod(context.Background(), d.ChatID, now.Add(-digestPeriod), now)
+	if err != nil {
+		return err
+	}
+	if len(homeworks) == 0 {
+		return nil
+	}
+
+	response := textResponse(digestMessage(p.locale(d.ChatID), homeworks, now.Location()), 0)
+	if d.Pin {
+		response.add(pinMessageAction{})
+	}
+	return p.doActions(response, &actionTarget{chatID: d.ChatID})
+}
+
+// digestMessage формирует текст сводки, сгруппированный по предметам.
+// homeworks должны быть отсортированы по предмету.
+func digestMessage(lang *i18n.Locale, homeworks []*storage.DBHomework, loc *time.Location) string {
+	message := lang.Text(msgDigestHeader)
+	subject := ""
+	for i, hm := range homeworks {
+		if i == 0 || hm.Subject != subject {
+			subject = hm.Subject
+			message += fmt.Sprintf("\n📚 %s\n", subject)
+		}
+		deadline := ""
+		if hm.Deadline != nil {
+			deadline = lang.Text(msgDeadline, hm.Deadline.In(loc).Format(deadlineTimeLayout))
+		}
+		message += fmt.Sprintf(" • %s%s\n", hm.Task, deadline)
+	}
+	return message
+}
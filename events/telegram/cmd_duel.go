@@ -5,10 +5,9 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
-	"strings"
 	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
 	"tg_ics_useful_bot/lib/e"
-	"tg_ics_useful_bot/lib/utils"
 	"tg_ics_useful_bot/storage"
 	"time"
 )
@@ -24,50 +23,45 @@ const (
 type getHpExec string
 
 // Exec: /hp - один раз в день пополняет здоровье пользователя.
-func (a getHpExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+func (a getHpExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 
 	message, err := p.getHp(user, chat)
 	if err != nil {
 		return nil, err
 	}
-	mthd := sendMessageMethod
-	return &Response{message: message, method: mthd}, nil
+	return textResponse(message, 0), nil
 }
 
 // duelExec предоставляет Exec метод для выполнения /duel.
 type duelExec string
 
 // Exec: /duel {@username} - игра дуели.
-func (a duelExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+func (a duelExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 
-	message, err := p.gameDuel(chat, user, user.Username)
+	target := user.Username
+	if params.Has("username") {
+		target = params.String("username")
+		log.Printf("[INFO] @%s вызывает на дуель @%s", user.Username, target)
+	}
+	message, err := p.gameDuel(chat, user, target)
 	if err != nil {
 		return nil, e.Wrap("can't do gameDuel: ", err)
 	}
-	if utils.StringContains("@", inMessage) {
-		textSplited := strings.Split(inMessage, "@")
-		target := textSplited[len(textSplited)-1]
-		log.Printf("[INFO] @%s вызывает на дуель @%s", user.Username, target)
-		message, err = p.gameDuel(chat, user, target)
-		if err != nil {
-			return nil, e.Wrap("can't do gameDuel: ", err)
-		}
-	}
-	mthd := sendMessageMethod
-	return &Response{message: message, method: mthd, replyMessageId: -1}, nil
+	return textResponse(message, 0), nil
 }
 
 // getHp пополняет HP пользователя раз в день.
 func (p *Processor) getHp(user *telegram.User, chat *telegram.Chat) (string, error) {
+	lang := p.locale(chat.ID)
 	dbUser, err := p.storage.GetUser(context.Background(), user.ID, chat.ID)
 	if err != nil {
 		return "", err
 	}
 
 	if !p.canGetHp(dbUser) {
-		return fmt.Sprintf(msgCantGetHP, user.Username, p.hpString(dbUser)), nil
+		return lang.Text(msgCantGetHP, user.Username, p.hpString(dbUser)), nil
 	}
 
 	dbUser.HpTakedAt = time.Now()
@@ -81,28 +75,29 @@ func (p *Processor) getHp(user *telegram.User, chat *telegram.Chat) (string, err
 	if err != nil {
 		return "", e.Wrap("can't update hp in 'canChangeDickSize'", err)
 	}
-	return fmt.Sprintf(msgGetHp, dbUser.Username, p.hpString(dbUser)), nil
+	return lang.Text(msgGetHp, dbUser.Username, p.hpString(dbUser)), nil
 }
 
 // gameDuel проводит дуель между двумя участиками чата на оснвое их DickSize и HP.
 func (p *Processor) gameDuel(chat *telegram.Chat, user *telegram.User, targetUsername string) (string, error) {
+	lang := p.locale(chat.ID)
 	u1, err := p.storage.GetUser(context.Background(), user.ID, chat.ID)
 	if err != nil {
 		return "", err
 	}
 	u2, err := p.storage.UserByUsername(context.Background(), targetUsername, chat.ID)
 	if err == storage.ErrUserNotExist {
-		return fmt.Sprintf(msgTargetNotFound, targetUsername), nil
+		return lang.Text(msgTargetNotFound, targetUsername), nil
 	} else if err != nil {
 		return "", err
 	}
 
 	if u1.TgID == u2.TgID || u2.IsBot {
-		return fmt.Sprintf(msgDuelWithYourself, u1.Username), nil
+		return lang.Text(msgDuelWithYourself, u1.Username), nil
 	}
 
 	if !p.canDuel(u1, u2) {
-		return fmt.Sprintf(msgCantCreateDuel, u1.Username, u2.Username), nil
+		return lang.Text(msgCantCreateDuel, u1.Username, u2.Username), nil
 	}
 
 	stats1, err := p.storage.GetUserStats(context.Background(), u1)
@@ -162,8 +157,8 @@ func (p *Processor) gameDuel(chat *telegram.Chat, user *telegram.User, targetUse
 				log.Println("[ERROR] can't update user stats in 'gameDuel'")
 			}
 
-			return fmt.Sprintf(msgAcceptDuel, u1.Username, oldHP1, oldDickSize1, ch1, u2.Username, oldHP2, oldDickSize2, ch2) +
-				fmt.Sprintf(finishMessage, u1.Username, p.hpString(u1), u1.DickSize, reward, u2.Username, p.hpString(u2),
+			return lang.Text(msgAcceptDuel, u1.Username, oldHP1, oldDickSize1, ch1, u2.Username, oldHP2, oldDickSize2, ch2) +
+				lang.Text(finishMessage, u1.Username, p.hpString(u1), u1.DickSize, reward, u2.Username, p.hpString(u2),
 					u2.DickSize, reward), nil
 		} else {
 			stats2.DuelsWinCount++
@@ -199,13 +194,13 @@ func (p *Processor) gameDuel(chat *telegram.Chat, user *telegram.User, targetUse
 				log.Println("[ERROR] can't update user stats in 'gameDuel'")
 			}
 
-			return fmt.Sprintf(msgAcceptDuel, u1.Username, oldHP1, oldDickSize1, ch1, u2.Username, oldHP2, oldDickSize2, ch2) +
-				fmt.Sprintf(finishMessage, u2.Username, p.hpString(u2), u2.DickSize, reward, u1.Username, p.hpString(u1),
+			return lang.Text(msgAcceptDuel, u1.Username, oldHP1, oldDickSize1, ch1, u2.Username, oldHP2, oldDickSize2, ch2) +
+				lang.Text(finishMessage, u2.Username, p.hpString(u2), u2.DickSize, reward, u1.Username, p.hpString(u1),
 					u1.DickSize, reward), nil
 		}
 	} else {
 		duels[targetUsername] = u1
-		return fmt.Sprintf(msgChallengeToDuel, u1.Username, targetUsername), nil
+		return lang.Text(msgChallengeToDuel, u1.Username, targetUsername), nil
 	}
 }
 
@@ -257,12 +252,9 @@ func (p *Processor) canDuel(user1 *storage.DBUser, user2 *storage.DBUser) bool {
 	return (user1.HealthPoints > 0 && user2.HealthPoints > 0) && (user1.DickSize > 0 && user2.DickSize > 0)
 }
 
-// canGetHp возвращает может ли пользватель сегодня пополнить хп.
+// canGetHp возвращает может ли пользватель сегодня (в часовом поясе чата) пополнить хп.
 func (p *Processor) canGetHp(user *storage.DBUser) bool {
-	yearLastTry, monthLastTry, dayLastTry := user.HpTakedAt.Date()
-	year, month, today := time.Now().Date()
-	return ((month == monthLastTry && today > dayLastTry) || (month > monthLastTry || year > yearLastTry)) && (user.HealthPoints < MAX_HEALTH_POINTS)
-
+	return isNewDay(user.HpTakedAt, p.chatNow(user.ChatID)) && (user.HealthPoints < MAX_HEALTH_POINTS)
 }
 
 // duel return true if dick1 wins.
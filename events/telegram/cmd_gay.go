@@ -2,10 +2,10 @@ package telegram
 
 import (
 	"context"
-	"fmt"
 	"math/rand"
 	"sort"
 	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
 	"tg_ics_useful_bot/lib/e"
 	"tg_ics_useful_bot/storage"
 	"time"
@@ -15,33 +15,32 @@ import (
 type gayExec string
 
 // Exec: /gay - определяет случайного пидора в чате среди админов чата.
-func (a gayExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+func (a gayExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 	message, err := p.gameGay(chat.ID)
 	if err != nil {
 		return nil, e.Wrap("can't get message from gameGay: ", err)
 	}
-	mthd := sendMessageMethod
-	return &Response{message: message, method: mthd, replyMessageId: -1}, nil
+	return textResponse(message, 0), nil
 }
 
 // topGaysExec предоставляет метод Exec для вывода топа пидоров.
 type topGaysExec string
 
 // Exec: /top_gay - выводит список участников чата и их кол-во становления пидором дня.
-func (a topGaysExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+func (a topGaysExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 	message, err := p.topGays(chat.ID)
 	if err != nil {
 		return nil, e.Wrap("can't do GayTop: ", err)
 	}
-	mthd := sendMessageMethod
-	return &Response{message: message, method: mthd, replyMessageId: -1}, nil
+	return textResponse(message, 0), nil
 }
 
 // gameGay определяет пидора дня среди администратора и возвращает сообщение для чата.
 func (p *Processor) gameGay(chatID int) (string, error) {
-	admins, err := p.tg.ChatAdministrators(chatID)
+	lang := p.locale(chatID)
+	admins, err := p.chatAdmins(chatID)
 	if err != nil {
 		return "", e.Wrap("can't get chat administrators: ", err)
 	}
@@ -49,19 +48,19 @@ func (p *Processor) gameGay(chatID int) (string, error) {
 	gay, err := p.storage.GetGayOfDay(context.Background(), chatID)
 	if err == storage.ErrUserNotExist {
 		gay, err = p.createNewGayOfDay(chatID, admins)
-		return fmt.Sprintf(msgNewGayOfDay, gay.Username), nil
+		return lang.Text(msgNewGayOfDay, gay.Username), nil
 	} else if err != nil {
 		return "", e.Wrap("can't get gay of day: ", err)
 	}
-	if (gay.CreatedAt.Month() == time.Now().Month() && gay.CreatedAt.Day() < time.Now().Day()) || gay.CreatedAt.Month() < time.Now().Month() {
+	if isNewDay(gay.CreatedAt, p.chatNow(chatID)) {
 		err = p.storage.RemoveGayOfDay(context.Background(), chatID)
 		if err != nil {
 			return "", err
 		}
 		gay, err = p.createNewGayOfDay(chatID, admins)
-		return fmt.Sprintf(msgNewGayOfDay, gay.Username), nil
+		return lang.Text(msgNewGayOfDay, gay.Username), nil
 	}
-	return fmt.Sprintf(msgCurrentGayOfDay, gay.Username), nil
+	return lang.Text(msgCurrentGayOfDay, gay.Username), nil
 }
 
 // createNewGayOfDay создаёт пидора дня.
@@ -103,7 +102,7 @@ func (p *Processor) createNewGayOfDay(chatID int, admins []telegram.User) (*stor
 
 // topGaysExec возвращает список всех админов и сколько раз они были пидорами.
 func (p *Processor) topGays(chatID int) (message string, err error) {
-	admins, err := p.tg.ChatAdministrators(chatID)
+	admins, err := p.chatAdmins(chatID)
 	if err != nil {
 		return "", e.Wrap("[ERROR] can't get chat administrators: ", err)
 	}
@@ -130,10 +129,12 @@ func (p *Processor) topGays(chatID int) (message string, err error) {
 	sort.Slice(dbUsersStats, func(i, j int) bool {
 		return dbUsersStats[i].GayCount > dbUsersStats[j].GayCount
 	})
-	result := "Рейтинг пидоров: \n\n"
+	lang := p.locale(chatID)
+	result := lang.Text(msgGayRatingHeader)
 
 	for i, dbU := range dbUsers {
-		result += fmt.Sprintf("%d. %s %s %d раз \n", i+1, dbU.FirstName, dbU.LastName, dbUsersStats[i].GayCount)
+		count := dbUsersStats[i].GayCount
+		result += lang.Plural(msgGayRatingLine, count, i+1, dbU.FirstName, dbU.LastName, count)
 	}
 	return result, nil
 }
@@ -0,0 +1,53 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/storage"
+)
+
+// greetingExec предоставляет метод Exec для выполнения /welcome и /goodbye.
+type greetingExec string
+
+// Exec: /welcome [текст | reset] и /goodbye [текст | reset] - показывают или меняют приветствие
+// новых участников и прощание с ушедшими.
+func (a greetingExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+
+	settings, err := p.chatSettings(chat.ID)
+	if err != nil {
+		return nil, e.Wrap(fmt.Sprintf("can't exec %s", a), err)
+	}
+	lang := localeByCode(settings.Language)
+
+	text, key, current := &settings.Welcome, msgWelcome, msgWelcomeCurrent
+	if string(a) == GoodbyeCmd {
+		text, key, current = &settings.Goodbye, msgGoodbye, msgGoodbyeCurrent
+	}
+
+	replyTo := messageID
+	if params.Has("text") {
+		*text = params.String("text")
+		if strings.EqualFold(*text, "reset") {
+			*text = ""
+		}
+		if err := p.storage.SaveChatSettings(context.Background(), settings); err != nil {
+			return nil, e.Wrap(fmt.Sprintf("can't exec %s", a), err)
+		}
+		replyTo = 0
+	}
+
+	message := *text
+	if message == "" {
+		message = lang.Text(key)
+	}
+	message = lang.Text(current, message)
+	if !settings.Greetings {
+		message += lang.Text(msgGreetingsDisabled)
+	}
+	return textResponse(message, replyTo), nil
+}
@@ -1,157 +1,271 @@
 package telegram
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log"
 	"strconv"
 	"strings"
 	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/lib/export"
+	"tg_ics_useful_bot/lib/i18n"
 	"tg_ics_useful_bot/storage"
+	"time"
 )
 
-type Homework struct {
-	subject string
-	Task    string
-}
+const (
+	maxRows     = 5
+	maxPageRows = 50
 
-func newHomework(subject, task string) *Homework {
-	return &Homework{subject: subject, Task: task}
-}
+	homeworkPageCallback = "hw"
 
-const (
-	maxRows = 5
+	deadlineLayout     = "02.01.2006"
+	deadlineTimeLayout = "02.01.2006 15:04"
+	noDeadline         = "-"
 )
 
-type UserWithChat struct {
-	ChatID int
-	UserID int
+// addHomeworkDialog диалог добавления домашнего задания: предмет, затем задание.
+var addHomeworkDialog = &Dialog{
+	Name: "add_homework",
+	Steps: []DialogStep{
+		{Key: "subject", Prompt: msgAddSubject},
+		{Key: "task", Prompt: msgAddTask},
+		{Key: "deadline", Prompt: msgAddDeadline, Invalid: msgWrongDeadline, Validate: isDeadline},
+	},
+	Finish: finishAddHomework,
 }
 
-var stateHomework = make(map[UserWithChat]*Homework)
-
+// addHomeworkExec предоставляет метод Exec для выполнения /add.
 type addHomeworkExec string
 
-func (a addHomeworkExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
-	
-	message := p.addHomeworkCmd(inMessage, UserWithChat{ChatID: chat.ID, UserID: user.ID})
-	mthd := sendMessageWithButtonsMethod
-	replyMessageId := messageID
-	return &Response{message: message, method: mthd, replyMessageId: replyMessageId}, nil
-}
-
-func (p *Processor) addHomeworkCmd(text string, userWithChat UserWithChat) string {
-	if strings.HasPrefix(text, "/") {
-		stateHomework[userWithChat] = newHomework("", "")
-		return msgAddSubject
-	} else if hm, ok := stateHomework[userWithChat]; ok && hm.subject == "" {
-		hm.subject = text
-		return msgAddTask
-	} else if hm, ok = stateHomework[userWithChat]; ok && hm.Task == "" {
-		hm.Task = text
-		message := fmt.Sprintf("ДЗ: %s - %s успешно добавлено", hm.subject, hm.Task)
-		err := p.storage.AddHomework(context.Background(), userWithChat.ChatID, hm.subject, hm.Task)
-		if err != nil {
-			message = msgErrorAddHomework
-			log.Printf("can't add homework: %v", err)
+// Exec: /add - начинает диалог добавления домашнего задания.
+func (a addHomeworkExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+
+	message, err := p.startDialog(addHomeworkDialog, chat, user)
+	if err != nil {
+		return nil, err
+	}
+	return textResponse(message, messageID), nil
+}
+
+// finishAddHomework сохраняет домашнее задание после завершения диалога /add.
+func finishAddHomework(p *Processor, chat *telegram.Chat, user *telegram.User, answers map[string]string) (string, error) {
+	subject, task := answers["subject"], answers["task"]
+	deadline, _ := parseDeadline(answers["deadline"], p.chatLocation(chat.ID))
+	err := p.storage.AddHomework(context.Background(), chat.ID, subject, task, deadline)
+	lang := p.locale(chat.ID)
+	if err != nil {
+		log.Printf("can't add homework: %v", err)
+		return lang.Text(msgErrorAddHomework), nil
+	}
+	return lang.Text(msgHomeworkSuccessAdded, subject, task), nil
+}
+
+// isDeadline проверяет ответ на шаге дедлайна: дата, дата со временем или "-".
+func isDeadline(answer string) bool {
+	_, err := parseDeadline(answer, time.UTC)
+	return err == nil
+}
+
+// parseDeadline разбирает дедлайн домашнего задания в часовом поясе loc, "-" означает его отсутствие.
+func parseDeadline(text string, loc *time.Location) (*time.Time, error) {
+	if text == noDeadline || text == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{deadlineTimeLayout, deadlineLayout} {
+		if t, err := time.ParseInLocation(layout, text, loc); err == nil {
+			return &t, nil
 		}
-		delete(stateHomework, userWithChat)
-		return message
 	}
-	return msgSomethingWrong
+	return nil, fmt.Errorf("wrong deadline format: %s", text)
+}
+
+// exportHomeworkExec предоставляет метод Exec для выполнения /export_homework.
+type exportHomeworkExec string
+
+// Exec: /export_homework [ics|csv|md] - отправляет файл со всем домашним заданием чата.
+func (a exportHomeworkExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+
+	lang := p.locale(chat.ID)
+	format, err := export.ParseFormat(params.String("format"))
+	if err != nil {
+		return nil, e.Wrap("can't parse export format", err)
+	}
+
+	homeworks, err := p.storage.GetAllHomework(context.Background(), chat.ID)
+	if err != nil {
+		return nil, e.Wrap("can't get homework for export", err)
+	}
+	if len(homeworks) == 0 {
+		return textResponse(lang.Text(msgHomeworkEmpty), messageID), nil
+	}
+
+	data, err := export.Homework(format, homeworks, time.Now(), p.chatLocation(chat.ID), exportLabels(lang))
+	if err != nil {
+		return nil, e.Wrap("can't export homework", err)
+	}
+	file := telegram.InputFile{Name: export.FileName(format, chat.ID), Data: bytes.NewReader(data)}
+	return newResponse(sendDocumentAction{document: file, caption: lang.Text(msgExportHomework)}), nil
+}
+
+// exportLabels возвращает подписи Markdown экспорта на языке чата.
+func exportLabels(lang *i18n.Locale) export.Labels {
+	return export.Labels{
+		Title:    lang.Text(msgExportTitle),
+		Subject:  lang.Text(msgExportSubject),
+		Task:     lang.Text(msgExportTask),
+		Created:  lang.Text(msgExportCreated),
+		Deadline: lang.Text(msgExportDeadline),
+	}
 }
 
 // getHomeworkExec предоставляет метод Exec для выполнения /get.
 type getHomeworkExec string
 
-// Exec: /get [number] [subject] - возвращает последние записи домашнего задания
-func (a getHomeworkExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
-
-	message := p.getHomework(inMessage, chat.ID)
-	mthd := sendMessageMethod
-	return &Response{message: message, method: mthd, replyMessageId: -1}, nil
-}
-
-// getHomework формирует строку домашнего задания.
-func (p *Processor) getHomework(text string, chatID int) string {
-	val := ""
-	for _, s := range strings.Split(text, " ")[1:] {
-		if s != "" {
-			_, err := strconv.Atoi(s)
-			if err == nil {
-				val = s
-				break
-			}
-			val += s + " "
-		}
+// Exec: /get [number] [subject] - возвращает страницу последних записей домашнего задания.
+// number - количество записей на странице, subject - название предмета.
+func (a getHomeworkExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+
+	page := newHomeworkPage(params.Int("number"), params.String("subject"))
+	message, markup, err := p.getHomework(chat.ID, page)
+	if err != nil {
+		return nil, e.Wrap("can't get homework", err)
+	}
+	return newResponse(sendTextAction{text: message, markup: markup}), nil
+}
+
+// homeworkPage параметры страницы списка домашнего задания.
+type homeworkPage struct {
+	page    int
+	size    int
+	subject string
+}
+
+// newHomeworkPage возвращает первую страницу по аргументам команды /get [number] [subject].
+func newHomeworkPage(size int, subject string) homeworkPage {
+	page := homeworkPage{size: maxRows, subject: subject}
+	if size > 0 {
+		page.size = size
+	}
+	if page.size > maxPageRows {
+		page.size = maxPageRows
 	}
+	return page
+}
 
-	message := ""
+// getHomework формирует страницу домашнего задания и кнопки навигации по страницам.
+func (p *Processor) getHomework(chatID int, page homeworkPage) (string, *telegram.InlineKeyboardMarkup, error) {
+	lang := p.locale(chatID)
+	total, err := p.storage.CountHomework(context.Background(), chatID, page.subject)
+	if err != nil {
+		return "", nil, err
+	}
+	if total == 0 {
+		return lang.Text(msgHomeworkEmpty), nil, nil
+	}
 
-	homeworks := []*storage.DBHomework{}
-	if num, err := strconv.Atoi(val); err == nil {
-		homeworks, err = p.storage.GetHomeworkByChatID(context.Background(), chatID, num)
-		if err != nil {
-			log.Print(err)
-			return ""
-		}
-		message += fmt.Sprintf("Последние %d домашних задания:\n", num)
-	} else if val != "" {
-		val = val[:len(val)-1]
-		homeworks, err = p.storage.GetHomeworkBySubject(context.Background(), chatID, val)
-		if err != nil {
-			log.Print(err)
-			return ""
-		}
-		message += fmt.Sprintf("Всё домашнее задание по предмету %s:\n", val)
-	} else {
-		homeworks, err = p.storage.GetHomeworkByChatID(context.Background(), chatID, maxRows)
-		if err != nil {
-			log.Print(err)
-			return ""
-		}
-		message += fmt.Sprintf("Последние %d добавленных домашних задания:\n", maxRows)
+	pages := (total + page.size - 1) / page.size
+	if page.page >= pages {
+		page.page = pages - 1
+	}
+	if page.page < 0 {
+		page.page = 0
+	}
+
+	homeworks, err := p.storage.GetHomeworkPage(context.Background(), chatID, page.subject, page.size, page.page*page.size)
+	if err != nil {
+		return "", nil, err
 	}
 
+	loc := p.chatLocation(chatID)
+	message := lang.Text(msgHomeworkPage, page.page+1, pages)
+	if page.subject != "" {
+		message = lang.Text(msgHomeworkSubjectPage, page.subject, page.page+1, pages)
+	}
 	for _, hm := range homeworks {
-		message += fmt.Sprintf(" • \"%s\" - \"%s\". [id = %d]\n", hm.Subject, hm.Task, hm.ID)
+		deadline := ""
+		if hm.Deadline != nil {
+			deadline = lang.Text(msgDeadline, hm.Deadline.In(loc).Format(deadlineTimeLayout))
+		}
+		message += fmt.Sprintf(" • \"%s\" - \"%s\"%s. [id = %d]\n", hm.Subject, hm.Task, deadline, hm.ID)
 	}
 
-	return message
+	return message, homeworkPageButtons(page, pages), nil
 }
 
-// deleteHomeworkExec предоставляет метод Exec для выполнения /delete.
-type deleteHomeworkExec string
+// homeworkPageButtons возвращает кнопки "◀ ▶" для перехода между страницами.
+// Если предмет не помещается в callback_data, навигация не добавляется.
+func homeworkPageButtons(page homeworkPage, pages int) *telegram.InlineKeyboardMarkup {
+	buttons := make([]telegram.InlineKeyboardButton, 0, 2)
+	if page.page > 0 {
+		buttons = append(buttons, telegram.InlineKeyboardButton{Text: "◀", CallbackData: page.callbackData(page.page - 1)})
+	}
+	if page.page < pages-1 {
+		buttons = append(buttons, telegram.InlineKeyboardButton{Text: "▶", CallbackData: page.callbackData(page.page + 1)})
+	}
+	if len(buttons) == 0 || len(page.callbackData(pages)) > maxCallbackDataLength {
+		return nil
+	}
+	return &telegram.InlineKeyboardMarkup{Keyboard: [][]telegram.InlineKeyboardButton{buttons}}
+}
 
-// Exec: /delete [id] - удаляет запись о домашнем задании
-func (a deleteHomeworkExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+// callbackData возвращает callback_data кнопки перехода на страницу number.
+func (h homeworkPage) callbackData(number int) string {
+	return callbackData(homeworkPageCallback, strconv.Itoa(number), strconv.Itoa(h.size), h.subject)
+}
 
-	val := ""
-	for _, str := range strings.Split(inMessage, " ")[1:] {
-		if str != "" {
-			val = str
-			break
-		}
+// homeworkPageExec предоставляет метод Exec для перелистывания страниц /get.
+type homeworkPageExec string
+
+// Exec: hw:{page}:{size}:{subject} - изменяет сообщение на запрошенную страницу домашнего задания.
+func (a homeworkPageExec) Exec(p *Processor, data string, user *telegram.User, chat *telegram.Chat,
+	messageID int) (*Response, error) {
+
+	args := strings.SplitN(data, callbackSeparator, 3)
+	if len(args) != 3 {
+		return nil, e.Wrap(fmt.Sprintf("wrong homework page data: %s", data), ErrUnknownEventType)
 	}
-	num, err := strconv.Atoi(val)
-	message := p.deleteHomework(num)
+	number, err := strconv.Atoi(args[0])
 	if err != nil {
-		message = fmt.Sprintf(msgIncorrectValue, val)
+		return nil, e.Wrap("wrong homework page number", err)
+	}
+	size, err := strconv.Atoi(args[1])
+	if err != nil || size <= 0 {
+		size = maxRows
 	}
-	mthd := sendMessageMethod
-	return &Response{message: message, method: mthd, replyMessageId: -1}, nil
+
+	message, markup, err := p.getHomework(chat.ID, homeworkPage{page: number, size: size, subject: args[2]})
+	if err != nil {
+		return nil, e.Wrap("can't get homework page", err)
+	}
+	return newResponse(editTextAction{text: message, markup: markup}), nil
+}
+
+// deleteHomeworkExec предоставляет метод Exec для выполнения /delete.
+type deleteHomeworkExec string
+
+// Exec: /delete {id} - удаляет запись о домашнем задании
+func (a deleteHomeworkExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+
+	message := p.deleteHomework(chat.ID, params.Int("id"))
+	return textResponse(message, 0), nil
 }
 
 // deleteHomework удаляет запись домашнего задания.
-func (p *Processor) deleteHomework(rowID int) string {
+func (p *Processor) deleteHomework(chatID, rowID int) string {
+	lang := p.locale(chatID)
 	err := p.storage.DeleteHomework(context.Background(), rowID)
-	message := fmt.Sprintf(msgSuccessDelete, rowID)
+	message := lang.Text(msgSuccessDelete, rowID)
 	if err != nil {
 		log.Print(err)
-		message = fmt.Sprintf(msgErrorDelete, rowID)
+		message = lang.Text(msgErrorDelete, rowID)
 	}
 	return message
 }
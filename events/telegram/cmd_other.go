@@ -5,6 +5,7 @@ import (
 	"tg_ics_useful_bot/clients/jokesrv"
 	"tg_ics_useful_bot/clients/telegram"
 	"tg_ics_useful_bot/clients/xkcd"
+	"tg_ics_useful_bot/lib/cmdargs"
 	"tg_ics_useful_bot/lib/e"
 	"tg_ics_useful_bot/storage"
 	"time"
@@ -14,44 +15,39 @@ import (
 type xkcdExec string
 
 // Exec: /xkcd - возвращает случайный xkcd комикс.
-func (a xkcdExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+func (a xkcdExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 
 	var comics xkcd.Comics
 	comics, err := xkcd.RandomComics()
 	if err != nil {
 		return nil, e.Wrap("can't get comics from xkcd: ", err)
 	}
-	message := comics.Img
-	mthd := sendPhotoMethod
-	return &Response{message: message, method: mthd, replyMessageId: -1}, nil
+	return newResponse(sendPhotoAction{photo: telegram.NewInputFileID(comics.Img), caption: comics.Title}), nil
 }
 
 // anekdotExec предоставляет Exec метод для выполнения /joke.
 type anekdotExec string
 
 // Exec: /joke - возвращает случайный анекдот от @bobuk.
-func (a anekdotExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+func (a anekdotExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 
 	message, err := jokesrv.Anecdot()
 	if err != nil {
 		return nil, e.Wrap("can't get anecdot: ", err)
 	}
-	mthd := sendMessageMethod
-	return &Response{message: message, method: mthd, replyMessageId: -1}, nil
+	return textResponse(message, 0), nil
 }
 
 // flipExec предоставляет Exec метод длы выполнения /flip.
 type flipExec string
 
 // Exec: /flip - возвращает случайную картинку из двух предоставленных ниже.
-func (a flipExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+func (a flipExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 
-	message := khinkalnyaOrVSU()
-	mthd := sendPhotoMethod
-	return &Response{message: message, method: mthd, replyMessageId: -1}, nil
+	return newResponse(sendPhotoAction{photo: telegram.NewInputFileID(khinkalnyaOrVSU())}), nil
 }
 
 const (
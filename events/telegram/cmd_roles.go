@@ -0,0 +1,107 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/storage"
+)
+
+// rolesExec предоставляет метод Exec для выполнения /roles.
+type rolesExec string
+
+// Exec: /roles - показывает все роли и участников чата, которым они выданы.
+func (a rolesExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+
+	message, err := p.chatRoles(chat.ID)
+	if err != nil {
+		return nil, e.Wrap("can't exec /roles", err)
+	}
+	return textResponse(message, messageID), nil
+}
+
+// chatRoles возвращает список ролей с участниками, которым они выданы в чате.
+func (p *Processor) chatRoles(chatID int) (string, error) {
+	lang := p.locale(chatID)
+	dbRoles, err := p.storage.ChatRoles(context.Background(), chatID)
+	if err != nil {
+		return "", err
+	}
+	users, err := p.storage.UsersByChat(context.Background(), chatID)
+	if err != nil {
+		return "", err
+	}
+	usernames := make(map[int]string, len(users))
+	for _, u := range users {
+		usernames[u.TgID] = u.Username
+	}
+
+	holders := make(map[Role][]string)
+	for _, r := range dbRoles {
+		holders[Role(r.Role)] = append(holders[Role(r.Role)], "@"+usernames[r.TgID])
+	}
+
+	var b strings.Builder
+	b.WriteString(lang.Text(msgRolesHeader))
+	if len(dbRoles) == 0 {
+		b.WriteString(lang.Text(msgNoRoles))
+	}
+	for _, r := range roles {
+		if len(holders[r.role]) > 0 {
+			b.WriteString(lang.Text(msgRoleLine, r.role, lang.Text(r.description), strings.Join(holders[r.role], ", ")))
+		}
+	}
+	b.WriteString(lang.Text(msgRolesFooter))
+	return b.String(), nil
+}
+
+// grantRoleExec предоставляет метод Exec для выполнения /grant.
+type grantRoleExec string
+
+// Exec: /grant {@username} {role} - выдаёт участнику чата роль.
+func (a grantRoleExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+
+	lang := p.locale(chat.ID)
+	username, role := params.String("username"), params.String("role")
+	target, err := p.storage.UserByUsername(context.Background(), username, chat.ID)
+	if err == storage.ErrUserNotExist {
+		return textResponse(lang.Text(msgRoleUserNotFound, username), messageID), nil
+	} else if err != nil {
+		return nil, e.Wrap("can't exec /grant", err)
+	}
+
+	err = p.storage.GrantRole(context.Background(), &storage.DBRole{ChatID: chat.ID, TgID: target.TgID, Role: role})
+	if err != nil {
+		return nil, e.Wrap("can't exec /grant", err)
+	}
+	return textResponse(lang.Text(msgRoleGranted, username, role), 0), nil
+}
+
+// revokeRoleExec предоставляет метод Exec для выполнения /revoke.
+type revokeRoleExec string
+
+// Exec: /revoke {@username} {role} - забирает у участника чата роль.
+func (a revokeRoleExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+
+	lang := p.locale(chat.ID)
+	username, role := params.String("username"), params.String("role")
+	target, err := p.storage.UserByUsername(context.Background(), username, chat.ID)
+	if err == storage.ErrUserNotExist {
+		return textResponse(lang.Text(msgRoleUserNotFound, username), messageID), nil
+	} else if err != nil {
+		return nil, e.Wrap("can't exec /revoke", err)
+	}
+
+	err = p.storage.RevokeRole(context.Background(), &storage.DBRole{ChatID: chat.ID, TgID: target.TgID, Role: role})
+	if err == storage.ErrRoleNotExist {
+		return textResponse(lang.Text(msgRoleNotAssigned, username, role), messageID), nil
+	} else if err != nil {
+		return nil, e.Wrap("can't exec /revoke", err)
+	}
+	return textResponse(lang.Text(msgRoleRevoked, username, role), 0), nil
+}
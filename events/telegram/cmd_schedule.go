@@ -1,65 +1,350 @@
 package telegram
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log"
 	"strings"
+	"tg_ics_useful_bot/clients/ical"
 	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/lib/i18n"
 	"tg_ics_useful_bot/lib/schedule"
 	"tg_ics_useful_bot/storage"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxICSFileSize максимальный размер загружаемого .ics файла.
+	maxICSFileSize = 1 << 20
+
+	// scheduleCacheTTL время, в течение которого расписание чата берётся из кэша.
+	scheduleCacheTTL = 30 * time.Minute
+	// scheduleImageArg аргумент /schedule, с которым расписание присылается картинкой.
+	scheduleImageArg = "image"
+	// lessonTimeLayout формат времени занятия в сообщениях.
+	lessonTimeLayout = "02.01 15:04"
 )
 
 // addCalendarExec предоставляет Exec метод для выполнения /add_calendar.
 type addCalendarExec string
 
-// Exec: /add_calendar {calendar_id}
-func (a addCalendarExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+// Exec: /add_calendar {calendar_id|url} - привязывает к чату Google Calendar по ID
+// или iCalendar по ссылке http(s):// или webcal://.
+func (a addCalendarExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 
-	if !p.isChatAdmin(user, chat.ID) {
-		return &Response{message: msgForbiddenCalendarUpdate, method: sendMessageMethod}, nil
-	}
-	strs := strings.Split(inMessage, " ")
-	calendarID := ""
-	for _, str := range strs {
-		if len(str) > 0 {
-			calendarID = str
+	lang := p.locale(chat.ID)
+	source := params.String("calendar")
+
+	c := &storage.DBCalendar{ChatID: chat.ID, CalendarID: source, Kind: schedule.GoogleKind}
+	message := lang.Text(msgSuccessUpdateCalendarID)
+	if schedule.IsICSLink(source) {
+		data, err := ical.Fetch(source)
+		if err == nil {
+			_, err = ical.Parse(bytes.NewReader(data))
+		}
+		if err != nil {
+			log.Printf("can't add ics calendar %s: %v", source, err)
+			return textResponse(lang.Text(msgErrorUpdateCalendarID, source), messageID), nil
 		}
+		c.Kind, message = schedule.ICSKind, lang.Text(msgSuccessUpdateICSCalendar)
 	}
-	err := p.storage.AddCalendarID(context.Background(), chat.ID, calendarID)
-	var message string
+
+	return p.saveCalendar(c, source, message)
+}
+
+// ExecDocument: /add_calendar в подписи к .ics файлу - привязывает к чату календарь из файла.
+func (a addCalendarExec) ExecDocument(p *Processor, inMessage string, document *telegram.Document,
+	user *telegram.User, chat *telegram.Chat, messageID int) (*Response, error) {
+
+	lang := p.locale(chat.ID)
+	if document.FileSize > maxICSFileSize {
+		return textResponse(lang.Text(msgICSFileTooBig), messageID), nil
+	}
+
+	data, err := p.tg.DownloadFile(document.FileID)
 	if err != nil {
-		message = fmt.Sprintf(msgErrorUpdateCalendarID, calendarID)
-		log.Printf("can't update calender_id: %v", err)
-	} else {
-		message = msgSuccessUpdateCalendarID
+		return nil, e.Wrap("can't download calendar file", err)
+	}
+	if _, err = ical.Parse(bytes.NewReader(data)); err != nil {
+		log.Printf("can't parse ics file %s: %v", document.FileName, err)
+		return textResponse(lang.Text(msgErrorUpdateCalendarID, document.FileName), messageID), nil
+	}
+
+	// у загруженного файла нет ссылки, календарь читается из Data
+	c := &storage.DBCalendar{ChatID: chat.ID, Kind: schedule.ICSKind, Data: string(data)}
+	return p.saveCalendar(c, document.FileName, lang.Text(msgSuccessUpdateICSCalendar))
+}
+
+// saveCalendar сохраняет календарь чата и возвращает ответ с message.
+// name - ссылка, ID или имя файла календаря для сообщения об ошибке.
+func (p *Processor) saveCalendar(c *storage.DBCalendar, name, message string) (*Response, error) {
+	lang := p.locale(c.ChatID)
+	if err := p.storage.SaveCalendar(context.Background(), c); err != nil {
+		message = lang.Text(msgErrorUpdateCalendarID, name)
+		log.Printf("can't update calendar: %v", err)
+	}
+	p.schedules.Invalidate(c.ChatID)
+	return textResponse(message, 0), nil
+}
+
+// chatCalendar возвращает источник расписания, привязанный к чату. Занятия читаются через кэш.
+func (p *Processor) chatCalendar(chatID int) (schedule.CalendarProvider, error) {
+	c, err := p.storage.GetCalendar(context.Background(), chatID)
+	if err != nil {
+		return nil, err
 	}
-	mthd := sendMessageMethod
-	return &Response{message: message, method: mthd, replyMessageId: -1}, nil
+	calendar, err := calendarProvider(c)
+	if err != nil {
+		return nil, err
+	}
+	return p.schedules.Provider(chatID, calendar), nil
+}
+
+// calendarProvider возвращает источник расписания календаря с учётом числителя и знаменателя.
+func calendarProvider(c *storage.DBCalendar) (schedule.CalendarProvider, error) {
+	calendar, err := schedule.NewProvider(c.Kind, c.CalendarID, c.Data)
+	if err != nil {
+		return nil, err
+	}
+	if c.NumeratorWeek != nil {
+		calendar = schedule.WithWeekParity(calendar, *c.NumeratorWeek)
+	}
+	return calendar, nil
+}
+
+// checkScheduleChanges обновляет расписание всех чатов и сообщает в чат о перенесённых,
+// отменённых и добавленных занятиях.
+func (p *Processor) checkScheduleChanges(now time.Time) error {
+	calendars, err := p.storage.AllCalendars(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, c := range calendars {
+		calendar, err := calendarProvider(c)
+		if err != nil {
+			log.Printf("[ERROR] wrong calendar in chat #%d: %v", c.ChatID, err)
+			continue
+		}
+		changes, err := p.schedules.Refresh(c.ChatID, calendar, now.In(p.chatLocation(c.ChatID)))
+		if err != nil {
+			log.Printf("[ERROR] can't refresh schedule of chat #%d: %v", c.ChatID, err)
+			continue
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		if err = p.sendMessage(c.ChatID, scheduleChangesMessage(p.locale(c.ChatID), changes, p.chatLocation(c.ChatID)), "", -1, nil); err != nil {
+			log.Printf("[ERROR] can't send schedule changes to chat #%d: %v", c.ChatID, err)
+		}
+	}
+	return nil
+}
+
+// scheduleChangesMessage формирует сообщение об изменениях в расписании.
+func scheduleChangesMessage(lang *i18n.Locale, changes []schedule.Change, loc *time.Location) string {
+	message := lang.Text(msgScheduleChanged)
+	for _, c := range changes {
+		switch c.Kind {
+		case schedule.LessonAdded:
+			message += lang.Text(msgLessonAdded, c.New.Name, c.New.DateTime.In(loc).Format(lessonTimeLayout))
+		case schedule.LessonCancelled:
+			message += lang.Text(msgLessonCancelled, c.Old.Name, c.Old.DateTime.In(loc).Format(lessonTimeLayout))
+		case schedule.LessonMoved:
+			message += lang.Text(msgLessonMoved, c.Old.Name, c.Old.DateTime.In(loc).Format(lessonTimeLayout),
+				c.New.DateTime.In(loc).Format(lessonTimeLayout))
+		}
+	}
+	return message
 }
 
 // scheduleExec предоставляет Exec метод для выполнения /schedule.
 type scheduleExec string
 
-// Exec: /schedule - возвращает расписание из Google Calender.
-func (a scheduleExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+// Exec: /schedule [image] [today|tomorrow|week|{date}] - возвращает расписание из календаря чата,
+// с image - картинкой-таблицей. Без параметров возвращает расписание на текущую неделю.
+func (a scheduleExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+	lang := p.locale(chat.ID)
 	var message string
 	var parseMode telegram.ParseMode
-	calendarID, err := p.storage.GetCalendarID(context.Background(), chat.ID)
-	if err != nil || calendarID == "" {
-		message = msgCalendarNotExists
-		log.Print("can't get calendarID: ", err)
+	calendar, err := p.chatCalendar(chat.ID)
+	if err != nil {
+		log.Print("can't get calendar: ", err)
+		return textResponse(lang.Text(msgCalendarNotExists), 0), nil
+	}
+
+	from, to, ok := schedulePeriod(params.String("period"), p.chatNow(chat.ID))
+	if !ok {
+		cmd := commandByName(string(a))
+		return argsErrorResponse(lang, cmd, cmd.argError("period", params.String("period")), messageID), nil
+	}
+	if params.Has("image") {
+		return p.scheduleImage(chat.ID, calendar, from, to)
+	}
+
+	message, err = schedule.ScheduleForPeriod(calendar, from, to, dayTitle(lang))
+	parseMode = telegram.Markdown
+	if err != nil {
+		log.Printf("[ERROR] can't send schedule: %v", err)
+		message = lang.Text(msgErrorSendMessage)
+		parseMode = ""
+	} else if message == "" {
+		message = lang.Text(msgNoLessons)
 	} else {
-		message, err = schedule.ScheduleCmd(calendarID)
-		parseMode = telegram.Markdown
-		if err != nil {
-			log.Printf("[ERROR] can't send schedule: %v", err)
-			message = fmt.Sprintf(msgErrorSendMessage, calendarID)
-			parseMode = ""
+		message = p.weekLabel(chat.ID, from) + message
+	}
+	return newResponse(sendTextAction{text: message, parseMode: parseMode}), nil
+}
+
+// scheduleImage возвращает ответ с расписанием в промежутке [from, to) в виде картинки.
+func (p *Processor) scheduleImage(chatID int, calendar schedule.CalendarProvider, from, to time.Time) (*Response, error) {
+	lang := p.locale(chatID)
+	data, err := schedule.ScheduleImage(calendar, from, to, shortDayName(lang))
+	if err == schedule.ErrNoLessons {
+		return textResponse(lang.Text(msgNoLessons), 0), nil
+	} else if err != nil {
+		log.Printf("[ERROR] can't render schedule image: %v", err)
+		return textResponse(lang.Text(msgErrorSendMessage), 0), nil
+	}
+
+	file := telegram.InputFile{Name: fmt.Sprintf("schedule_%s.png", from.Format("2006-01-02")), Data: bytes.NewReader(data)}
+	caption := strings.TrimSpace(p.weekLabel(chatID, from))
+	return newResponse(sendPhotoAction{photo: file, caption: caption}), nil
+}
+
+// schedulePeriod возвращает промежуток времени по аргументу /schedule:
+// today, tomorrow, week, день недели или дата ДД.ММ[.ГГГГ]. Без аргумента - текущая неделя.
+func schedulePeriod(arg string, now time.Time) (time.Time, time.Time, bool) {
+	today := schedule.StartOfDay(now)
+	switch arg = strings.ToLower(arg); arg {
+	case "", "week", "неделя":
+		week := schedule.StartOfWeek(now)
+		return week, week.AddDate(0, 0, 7), true
+	case "today", "сегодня":
+		return today, today.AddDate(0, 0, 1), true
+	case "tomorrow", "завтра":
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 2), true
+	default:
+		if day, ok := parseWeekday(arg); ok {
+			from := today.AddDate(0, 0, (int(day)-int(now.Weekday())+7)%7)
+			return from, from.AddDate(0, 0, 1), true
+		}
+		if date, ok := parseDate(arg, now); ok {
+			return date, date.AddDate(0, 0, 1), true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// parseDate разбирает дату в формате ДД.ММ.ГГГГ или ДД.ММ (текущий год).
+// Несуществующие даты, например 29.02 в невисокосный год, не принимаются.
+func parseDate(text string, now time.Time) (time.Time, bool) {
+	if t, err := time.ParseInLocation(deadlineLayout, text, now.Location()); err == nil {
+		return t, true
+	}
+	if t, err := time.ParseInLocation("02.01", text, now.Location()); err == nil {
+		date := time.Date(now.Year(), t.Month(), t.Day(), 0, 0, 0, 0, now.Location())
+		// time.Date переносит 29.02 на 01.03, такая дата не совпадает с введённой
+		if date.Month() != t.Month() || date.Day() != t.Day() {
+			return time.Time{}, false
 		}
+		return date, true
+	}
+	return time.Time{}, false
+}
+
+// nextLessonExec предоставляет Exec метод для выполнения /next.
+type nextLessonExec string
+
+// Exec: /next - возвращает ближайшее занятие и время до его начала.
+func (a nextLessonExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+	lang := p.locale(chat.ID)
+	calendar, err := p.chatCalendar(chat.ID)
+	if err != nil {
+		log.Print("can't get calendar: ", err)
+		return textResponse(lang.Text(msgCalendarNotExists), 0), nil
+	}
+
+	now := p.chatNow(chat.ID)
+	lesson, err := schedule.NextLesson(calendar, now)
+	if err != nil {
+		log.Printf("[ERROR] can't get next lesson: %v", err)
+		return textResponse(lang.Text(msgErrorSendMessage), 0), nil
+	}
+
+	message := lang.Text(msgNoNextLesson)
+	if lesson != nil {
+		message = lang.Text(msgNextLesson, lesson.Name, lesson.DateTime.Format(lessonTimeLayout),
+			durationText(lang, lesson.DateTime.Sub(now)))
+		if details := lesson.Details(); details != "" {
+			message += "\n" + details
+		}
+	}
+	return textResponse(message, 0), nil
+}
+
+// durationText возвращает промежуток времени в виде "1 д 2 ч 5 мин".
+func durationText(lang *i18n.Locale, d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	days, hours, minutes := minutes/(24*60), minutes%(24*60)/60, minutes%60
+
+	parts := make([]string, 0, 3)
+	if days > 0 {
+		parts = append(parts, lang.Plural(msgDurationDays, days, days))
+	}
+	if hours > 0 {
+		parts = append(parts, lang.Plural(msgDurationHours, hours, hours))
+	}
+	parts = append(parts, lang.Plural(msgDurationMinutes, minutes, minutes))
+	return strings.Join(parts, " ")
+}
+
+// weekdayKeys ключи названий дней недели.
+var weekdayKeys = map[time.Weekday]string{
+	time.Monday:    msgMonday,
+	time.Tuesday:   msgTuesday,
+	time.Wednesday: msgWednesday,
+	time.Thursday:  msgThursday,
+	time.Friday:    msgFriday,
+	time.Saturday:  msgSaturday,
+	time.Sunday:    msgSunday,
+}
+
+// shortWeekdayKeys ключи сокращённых названий дней недели.
+var shortWeekdayKeys = map[time.Weekday]string{
+	time.Monday:    msgMondayShort,
+	time.Tuesday:   msgTuesdayShort,
+	time.Wednesday: msgWednesdayShort,
+	time.Thursday:  msgThursdayShort,
+	time.Friday:    msgFridayShort,
+	time.Saturday:  msgSaturdayShort,
+	time.Sunday:    msgSundayShort,
+}
+
+// weekdayName возвращает название дня недели на языке lang.
+func weekdayName(lang *i18n.Locale, day time.Weekday) string {
+	return lang.Text(weekdayKeys[day])
+}
+
+// dayTitle возвращает названия дней для заголовков текстового расписания: с большой буквы.
+func dayTitle(lang *i18n.Locale) schedule.DayName {
+	return func(day time.Weekday) string {
+		name := weekdayName(lang, day)
+		r, size := utf8.DecodeRuneInString(name)
+		return string(unicode.ToUpper(r)) + name[size:]
+	}
+}
+
+// shortDayName возвращает сокращённые названия дней для картинки с расписанием.
+func shortDayName(lang *i18n.Locale) schedule.DayName {
+	return func(day time.Weekday) string {
+		return lang.Text(shortWeekdayKeys[day])
 	}
-	mthd := sendMessageMethod
-	return &Response{message: message, method: mthd, replyMessageId: -1, parseMode: parseMode}, nil
 }
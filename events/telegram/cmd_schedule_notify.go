@@ -0,0 +1,209 @@
+package telegram
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/lib/i18n"
+	"tg_ics_useful_bot/lib/schedule"
+	"tg_ics_useful_bot/storage"
+	"time"
+)
+
+const (
+	scheduleNotifyRemind = "remind"
+	// maxRemindBefore максимальное время напоминания до занятия в минутах.
+	maxRemindBefore = 24 * 60
+)
+
+// scheduleNotifyExec предоставляет метод Exec для выполнения /schedule_notify.
+type scheduleNotifyExec string
+
+// Exec: /schedule_notify [HH:MM [remind N] | off] - настраивает утреннюю рассылку расписания на день
+// и напоминания за N минут до каждого занятия. Без параметров показывает текущие настройки.
+func (a scheduleNotifyExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+
+	if !params.Has("time") {
+		message, err := p.scheduleNotifySettings(chat.ID)
+		if err != nil {
+			return nil, err
+		}
+		return textResponse(message, messageID), nil
+	}
+
+	if params.String("time") == digestOff {
+		message, err := p.disableScheduleNotify(chat.ID)
+		if err != nil {
+			return nil, e.Wrap("can't disable schedule notify", err)
+		}
+		return textResponse(message, messageID), nil
+	}
+
+	lang := p.locale(chat.ID)
+	cmd := commandByName(string(a))
+	sendTime, err := time.Parse(digestTimeLayout, params.String("time"))
+	if err != nil {
+		return argsErrorResponse(lang, cmd, cmd.argError("time", params.String("time")), messageID), nil
+	}
+	remindBefore := params.Int("minutes")
+	if params.Has("remind") && !params.Has("minutes") {
+		return argsErrorResponse(lang, cmd, cmd.argError("minutes", ""), messageID), nil
+	}
+	if params.Has("minutes") && (remindBefore <= 0 || remindBefore > maxRemindBefore) {
+		return argsErrorResponse(lang, cmd, cmd.argError("minutes", strconv.Itoa(remindBefore)), messageID), nil
+	}
+
+	message, err := p.updateScheduleNotify(chat.ID, sendTime, remindBefore)
+	if err != nil {
+		return nil, e.Wrap("can't update schedule notify", err)
+	}
+	return textResponse(message, messageID), nil
+}
+
+// scheduleNotifySettings возвращает описание текущих настроек рассылки расписания в чате.
+func (p *Processor) scheduleNotifySettings(chatID int) (string, error) {
+	lang := p.locale(chatID)
+	n, err := p.storage.GetScheduleNotify(context.Background(), chatID)
+	if err == storage.ErrScheduleNotifyNotExist {
+		return lang.Text(msgScheduleNotifyDisabled), nil
+	} else if err != nil {
+		return "", e.Wrap("can't get schedule notify", err)
+	}
+	return lang.Text(msgScheduleNotifySettings, n.SendTime, remindText(lang, n.RemindBefore)), nil
+}
+
+// disableScheduleNotify выключает рассылку расписания в чате.
+func (p *Processor) disableScheduleNotify(chatID int) (string, error) {
+	if err := p.storage.DeleteScheduleNotify(context.Background(), chatID); err != nil {
+		return "", err
+	}
+	return p.locale(chatID).Text(msgScheduleNotifyDisabled), nil
+}
+
+// updateScheduleNotify включает или изменяет рассылку расписания в sendTime
+// и напоминания за remindBefore минут до занятий, 0 - без напоминаний.
+// Без привязанного календаря рассылка не включается.
+func (p *Processor) updateScheduleNotify(chatID int, sendTime time.Time, remindBefore int) (string, error) {
+	lang := p.locale(chatID)
+	_, err := p.storage.GetCalendar(context.Background(), chatID)
+	if err == storage.ErrCalendarNotExist {
+		return lang.Text(msgCalendarNotExists), nil
+	} else if err != nil {
+		return "", e.Wrap("can't get calendar", err)
+	}
+
+	now := time.Now()
+	n := &storage.DBScheduleNotify{
+		ChatID:       chatID,
+		SendTime:     sendTime.Format(digestTimeLayout),
+		RemindBefore: remindBefore,
+		// не отправляем расписание сразу, если время сегодня уже прошло
+		LastSentAt:     now,
+		LastRemindedAt: now,
+	}
+	if err = p.storage.SaveScheduleNotify(context.Background(), n); err != nil {
+		return "", err
+	}
+	return lang.Text(msgScheduleNotifySettings, n.SendTime, remindText(lang, n.RemindBefore)), nil
+}
+
+// remindText возвращает описание настройки напоминаний о занятиях.
+func remindText(lang *i18n.Locale, remindBefore int) string {
+	if remindBefore > 0 {
+		return lang.Text(msgScheduleNotifyRemind, remindBefore)
+	}
+	return ""
+}
+
+// sendScheduleNotifies рассылает расписание на день и напоминания о занятиях во все чаты с включённой рассылкой.
+func (p *Processor) sendScheduleNotifies(now time.Time) error {
+	notifies, err := p.storage.AllScheduleNotifies(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, n := range notifies {
+		now := now.In(p.chatLocation(n.ChatID))
+		calendar, err := p.chatCalendar(n.ChatID)
+		if err == storage.ErrCalendarNotExist {
+			// в чате нет календаря, слать нечего
+			continue
+		} else if err != nil {
+			log.Printf("[ERROR] can't get calendar of chat #%d: %v", n.ChatID, err)
+			continue
+		}
+		if scheduleNotifyDue(n, now) {
+			if err = p.sendDaySchedule(n, calendar, now); err != nil {
+				log.Printf("[ERROR] can't send schedule to chat #%d: %v", n.ChatID, err)
+			}
+		}
+		if n.RemindBefore > 0 {
+			if err = p.sendLessonReminders(n, calendar, now); err != nil {
+				log.Printf("[ERROR] can't send lesson reminders to chat #%d: %v", n.ChatID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// scheduleNotifyDue показывает, пора ли отправить расписание на сегодня.
+func scheduleNotifyDue(n *storage.DBScheduleNotify, now time.Time) bool {
+	sendTime, err := time.Parse(digestTimeLayout, n.SendTime)
+	if err != nil {
+		return false
+	}
+	scheduled := time.Date(now.Year(), now.Month(), now.Day(), sendTime.Hour(), sendTime.Minute(), 0, 0, now.Location())
+	return !now.Before(scheduled) && n.LastSentAt.Before(scheduled)
+}
+
+// sendDaySchedule отправляет в чат расписание на сегодня, дни без занятий пропускаются.
+func (p *Processor) sendDaySchedule(n *storage.DBScheduleNotify, calendar schedule.CalendarProvider, now time.Time) error {
+	lang := p.locale(n.ChatID)
+	n.LastSentAt = now
+	if err := p.storage.SaveScheduleNotify(context.Background(), n); err != nil {
+		return err
+	}
+
+	message, err := schedule.ScheduleByDay(now, calendar, dayTitle(lang))
+	if err != nil || message == "" {
+		return err
+	}
+	return p.sendMessage(n.ChatID, lang.Text(msgScheduleNotifyHeader)+message, telegram.Markdown, -1, nil)
+}
+
+// sendLessonReminders напоминает о занятиях, до начала которых осталось RemindBefore минут.
+// Напоминание о каждом занятии отправляется один раз: просматриваются занятия,
+// время напоминания о которых наступило после прошлой проверки.
+func (p *Processor) sendLessonReminders(n *storage.DBScheduleNotify, calendar schedule.CalendarProvider, now time.Time) error {
+	lang := p.locale(n.ChatID)
+	remindBefore := time.Duration(n.RemindBefore) * time.Minute
+	from, to := n.LastRemindedAt.Add(remindBefore), now.Add(remindBefore)
+	if from.Before(now) {
+		// о начавшихся занятиях (например, после простоя бота) не напоминаем
+		from = now
+	}
+
+	n.LastRemindedAt = now
+	if err := p.storage.SaveScheduleNotify(context.Background(), n); err != nil {
+		return err
+	}
+	if !from.Before(to) {
+		return nil
+	}
+
+	lessons, err := calendar.Lessons(from, to)
+	if err != nil {
+		return err
+	}
+	for _, l := range lessons {
+		message := lang.Text(msgLessonReminder, durationText(lang, l.DateTime.Sub(now)), l.Name,
+			l.DateTime.In(now.Location()).Format(digestTimeLayout))
+		if err = p.tg.SendMessage(n.ChatID, message, "", -1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
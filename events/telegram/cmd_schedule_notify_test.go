@@ -0,0 +1,109 @@
+package telegram
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/schedule"
+	"tg_ics_useful_bot/storage"
+	"time"
+)
+
+func TestScheduleNotifyDue(t *testing.T) {
+	loc := time.FixedZone("MSK", 3*60*60)
+	today := time.Date(2024, time.February, 5, 0, 0, 0, 0, loc)
+	at := func(day, hour, minute int) time.Time {
+		return today.AddDate(0, 0, day).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	tests := []struct {
+		sendTime      string
+		lastSent, now time.Time
+		want          bool
+	}{
+		{"07:30", at(-1, 7, 30), at(0, 7, 29), false},
+		{"07:30", at(-1, 7, 30), at(0, 7, 30), true},
+		// бот был выключен утром, расписание отправляется позже
+		{"07:30", at(-1, 7, 30), at(0, 12, 0), true},
+		{"07:30", at(0, 7, 30), at(0, 7, 31), false},
+		// рассылку включили днём, сегодня уже не отправляем
+		{"07:30", at(0, 12, 0), at(0, 12, 1), false},
+		{"7.30", at(-1, 7, 30), at(0, 12, 0), false},
+	}
+	for _, tt := range tests {
+		n := &storage.DBScheduleNotify{SendTime: tt.sendTime, LastSentAt: tt.lastSent}
+		if got := scheduleNotifyDue(n, tt.now); got != tt.want {
+			t.Errorf("send at %s, last sent %v, now %v: got %v, want %v", tt.sendTime, tt.lastSent, tt.now, got, tt.want)
+		}
+	}
+}
+
+// lessonsProvider расписание из заранее заданных занятий.
+type lessonsProvider []schedule.Lesson
+
+func (l lessonsProvider) Lessons(from, to time.Time) ([]schedule.Lesson, error) {
+	var lessons []schedule.Lesson
+	for _, lesson := range l {
+		if !lesson.DateTime.Before(from) && lesson.DateTime.Before(to) {
+			lessons = append(lessons, lesson)
+		}
+	}
+	return lessons, nil
+}
+
+func TestSendLessonReminders(t *testing.T) {
+	var sent []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/sendMessage") {
+			sent = append(sent, r.FormValue("text"))
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	defer server.Close()
+	defer func(transport http.RoundTripper) { http.DefaultTransport = transport }(http.DefaultTransport)
+	http.DefaultTransport = server.Client().Transport
+
+	p := &Processor{tg: telegram.New(server.Listener.Addr().String(), "token", nil), storage: newFakeStorage()}
+	start := time.Date(2024, time.February, 5, 10, 0, 0, 0, time.UTC)
+	calendar := lessonsProvider{{Name: "Физика", DateTime: start}}
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	tests := []struct {
+		lastReminded, now time.Time
+		want              int
+	}{
+		// занятие в 10:00, напоминание за 10 минут: окно [09:59, 10:00) ещё не дошло до него
+		{at(-11), at(-10), 0},
+		{at(-10), at(-9), 1},
+		// о занятии уже напомнили на прошлой проверке
+		{at(-9), at(-8), 0},
+		// бот был выключен, о начавшемся занятии не напоминаем
+		{at(-60), at(5), 0},
+	}
+	for _, tt := range tests {
+		sent = nil
+		n := &storage.DBScheduleNotify{ChatID: -100, RemindBefore: 10, LastRemindedAt: tt.lastReminded}
+		if err := p.sendLessonReminders(n, calendar, tt.now); err != nil {
+			t.Fatalf("sendLessonReminders: %v", err)
+		}
+		if len(sent) != tt.want {
+			t.Errorf("last reminded %v, now %v: sent %q, want %d reminders", tt.lastReminded, tt.now, sent, tt.want)
+		}
+		if !n.LastRemindedAt.Equal(tt.now) {
+			t.Errorf("LastRemindedAt: got %v, want %v", n.LastRemindedAt, tt.now)
+		}
+	}
+}
+
+func TestScheduleNotifyWithoutCalendar(t *testing.T) {
+	s := newFakeStorage()
+	p := &Processor{storage: s}
+	message, err := p.updateScheduleNotify(-100, time.Date(0, 1, 1, 7, 30, 0, 0, time.UTC), 0)
+	if err != nil {
+		t.Fatalf("updateScheduleNotify: %v", err)
+	}
+	if message != ruLocale.Text(msgCalendarNotExists) || len(s.notifies) != 0 {
+		t.Errorf("without calendar: got %q, saved %+v", message, s.notifies)
+	}
+}
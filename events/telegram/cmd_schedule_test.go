@@ -0,0 +1,127 @@
+package telegram
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/schedule"
+	"tg_ics_useful_bot/storage"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	loc := time.FixedZone("MSK", 3*60*60)
+	now := time.Date(2023, time.November, 20, 12, 0, 0, 0, loc)
+	tests := []struct {
+		text string
+		want time.Time
+		ok   bool
+	}{
+		{"05.12", time.Date(2023, time.December, 5, 0, 0, 0, 0, loc), true},
+		{"05.12.2024", time.Date(2024, time.December, 5, 0, 0, 0, 0, loc), true},
+		{"29.02.2024", time.Date(2024, time.February, 29, 0, 0, 0, 0, loc), true},
+		{"29.02", time.Time{}, false},
+		{"29.02.2023", time.Time{}, false},
+		{"31.04", time.Time{}, false},
+		{"завтра", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseDate(tt.text, now)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("parseDate(%q) = %v, %v; want %v, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	leap := time.Date(2024, time.January, 10, 12, 0, 0, 0, loc)
+	if got, ok := parseDate("29.02", leap); !ok || got.Month() != time.February || got.Day() != 29 {
+		t.Errorf("parseDate(29.02) in leap year = %v, %v", got, ok)
+	}
+}
+
+const testICS = `BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+UID:physics
+SUMMARY:Физика
+DTSTART:20240205T090000Z
+DTEND:20240205T103000Z
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestUploadedCalendar(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/getFile"):
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"file_id":"f1","file_path":"documents/schedule.ics"}}`))
+		case strings.HasSuffix(r.URL.Path, "/documents/schedule.ics"):
+			_, _ = w.Write([]byte(testICS))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	// клиент Telegram ходит через http.DefaultTransport, подменяем его на доверяющий тестовому серверу
+	defer func(transport http.RoundTripper) { http.DefaultTransport = transport }(http.DefaultTransport)
+	http.DefaultTransport = server.Client().Transport
+
+	s := newFakeStorage()
+	p := &Processor{
+		tg:        telegram.New(server.Listener.Addr().String(), "token", nil),
+		storage:   s,
+		schedules: schedule.NewCache(time.Minute),
+	}
+	chat := &telegram.Chat{ID: -100}
+	document := &telegram.Document{FileID: "f1", FileName: "schedule.ics", FileSize: len(testICS)}
+	if _, err := addCalendarExec(AddCalendarIDCmd).ExecDocument(p, AddCalendarIDCmd, document, &telegram.User{}, chat, 1); err != nil {
+		t.Fatalf("ExecDocument: %v", err)
+	}
+	if c := s.calendars[chat.ID]; c == nil || c.CalendarID != "" || c.Data != testICS {
+		t.Fatalf("saved calendar: %+v", c)
+	}
+
+	calendar, err := p.chatCalendar(chat.ID)
+	if err != nil {
+		t.Fatalf("chatCalendar: %v", err)
+	}
+	day := time.Date(2024, time.February, 5, 0, 0, 0, 0, time.UTC)
+	lessons, err := calendar.Lessons(day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Lessons: %v", err)
+	}
+	if len(lessons) != 1 || lessons[0].Name != "Физика" {
+		t.Errorf("lessons: %+v", lessons)
+	}
+}
+
+func TestLegacyUploadedCalendar(t *testing.T) {
+	// раньше в calendar_id загруженного файла записывалось его имя
+	c := &storage.DBCalendar{CalendarID: "schedule.ics", Kind: schedule.ICSKind, Data: testICS}
+	calendar, err := calendarProvider(c)
+	if err != nil {
+		t.Fatalf("calendarProvider: %v", err)
+	}
+	day := time.Date(2024, time.February, 5, 0, 0, 0, 0, time.UTC)
+	if lessons, err := calendar.Lessons(day, day.AddDate(0, 0, 1)); err != nil || len(lessons) != 1 {
+		t.Errorf("lessons: %+v, %v", lessons, err)
+	}
+}
+
+func TestWeekdayNames(t *testing.T) {
+	for _, text := range []string{"пятница", "Friday", "пт", "fri"} {
+		if day, ok := parseWeekday(text); !ok || day != time.Friday {
+			t.Errorf("parseWeekday(%q) = %v, %v", text, day, ok)
+		}
+	}
+	if got := dayTitle(ruLocale)(time.Monday); got != "Понедельник" {
+		t.Errorf("ru day title: got %q", got)
+	}
+	if got := shortDayName(enLocale)(time.Sunday); got != "Sun" {
+		t.Errorf("en short day name: got %q", got)
+	}
+	if got := weekParityName(enLocale, schedule.Denominator); got != "even" {
+		t.Errorf("en week parity: got %q", got)
+	}
+}
@@ -0,0 +1,199 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/lib/i18n"
+	"tg_ics_useful_bot/storage"
+)
+
+// settingsCallback префикс callback_data кнопок /settings.
+const settingsCallback = "st"
+
+// chatSetting переключатель в /settings.
+type chatSetting struct {
+	// key идентификатор настройки в callback_data.
+	key string
+	// title ключ названия настройки.
+	title string
+	// value возвращает текущее значение для кнопки, toggle - переключает его.
+	value  func(s *storage.DBChatSettings) string
+	toggle func(s *storage.DBChatSettings)
+}
+
+// allSettings настройки чата в порядке кнопок.
+var allSettings = []chatSetting{
+	{
+		key:    "games",
+		title:  msgSettingGames,
+		value:  func(s *storage.DBChatSettings) string { return onOff(s.Games) },
+		toggle: func(s *storage.DBChatSettings) { s.Games = !s.Games },
+	},
+	{
+		key:    "replies",
+		title:  msgSettingReplies,
+		value:  func(s *storage.DBChatSettings) string { return onOff(s.AutoReplies) },
+		toggle: func(s *storage.DBChatSettings) { s.AutoReplies = !s.AutoReplies },
+	},
+	{
+		key:    "delete",
+		title:  msgSettingDelete,
+		value:  func(s *storage.DBChatSettings) string { return onOff(s.DeleteCommands) },
+		toggle: func(s *storage.DBChatSettings) { s.DeleteCommands = !s.DeleteCommands },
+	},
+	{
+		key:    "greet",
+		title:  msgSettingGreetings,
+		value:  func(s *storage.DBChatSettings) string { return onOff(s.Greetings) },
+		toggle: func(s *storage.DBChatSettings) { s.Greetings = !s.Greetings },
+	},
+	{
+		key:    "lang",
+		title:  msgSettingLanguage,
+		value:  func(s *storage.DBChatSettings) string { return languageName(s.Language) },
+		toggle: func(s *storage.DBChatSettings) { s.Language = nextLanguage(s.Language) },
+	},
+}
+
+// onOff возвращает значок включённой или выключенной настройки.
+func onOff(enabled bool) string {
+	if enabled {
+		return "✅"
+	}
+	return "❌"
+}
+
+// languageName возвращает название языка по коду.
+func languageName(code string) string {
+	for _, l := range locales {
+		if l.Code == code {
+			return l.Name
+		}
+	}
+	return code
+}
+
+// nextLanguage возвращает язык, следующий за code в списке locales.
+func nextLanguage(code string) string {
+	for i, l := range locales {
+		if l.Code == code {
+			return locales[(i+1)%len(locales)].Code
+		}
+	}
+	return locales[0].Code
+}
+
+// defaultChatSettings настройки чата, который ещё ничего не настраивал: всё включено.
+func defaultChatSettings(chatID int) *storage.DBChatSettings {
+	return &storage.DBChatSettings{
+		ChatID:         chatID,
+		Games:          true,
+		AutoReplies:    true,
+		DeleteCommands: true,
+		Language:       defaultLanguage,
+		Greetings:      true,
+	}
+}
+
+// chatSettings возвращает настройки чата или настройки по умолчанию, если чат их не менял.
+func (p *Processor) chatSettings(chatID int) (*storage.DBChatSettings, error) {
+	settings, err := p.storage.GetChatSettings(context.Background(), chatID)
+	if err == storage.ErrChatNotExist {
+		return defaultChatSettings(chatID), nil
+	}
+	return settings, err
+}
+
+// settingsExec предоставляет метод Exec для выполнения /settings.
+type settingsExec string
+
+// Exec: /settings - показывает настройки чата с кнопками для их переключения.
+func (a settingsExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+
+	settings, err := p.chatSettings(chat.ID)
+	if err != nil {
+		return nil, e.Wrap("can't exec /settings", err)
+	}
+	lang := localeByCode(settings.Language)
+	return newResponse(sendTextAction{text: lang.Text(msgSettings), markup: settingsButtons(lang, settings)}), nil
+}
+
+// settingsButtons возвращает кнопки настроек, по одной в ряд.
+func settingsButtons(lang *i18n.Locale, settings *storage.DBChatSettings) *telegram.InlineKeyboardMarkup {
+	keyboard := make([][]telegram.InlineKeyboardButton, 0, len(allSettings))
+	for _, s := range allSettings {
+		keyboard = append(keyboard, []telegram.InlineKeyboardButton{{
+			Text:         fmt.Sprintf("%s: %s", lang.Text(s.title), s.value(settings)),
+			CallbackData: callbackData(settingsCallback, s.key),
+		}})
+	}
+	return &telegram.InlineKeyboardMarkup{Keyboard: keyboard}
+}
+
+// settingsToggleExec предоставляет метод Exec для кнопок /settings.
+type settingsToggleExec string
+
+// Exec: st:{key} - переключает настройку чата, только для админов группы.
+func (a settingsToggleExec) Exec(p *Processor, data string, user *telegram.User, chat *telegram.Chat,
+	messageID int) (*Response, error) {
+
+	if !p.isAdmin(user.ID) && !p.isChatAdmin(user, chat.ID) {
+		return newResponse(answerCallbackAction{text: p.locale(chat.ID).Text(msgSettingsForbidden)}), nil
+	}
+
+	settings, err := p.chatSettings(chat.ID)
+	if err != nil {
+		return nil, e.Wrap("can't get chat settings", err)
+	}
+	key := strings.TrimSpace(data)
+	found := false
+	for _, s := range allSettings {
+		if s.key == key {
+			s.toggle(settings)
+			found = true
+		}
+	}
+	if !found {
+		return nil, e.Wrap(fmt.Sprintf("unknown chat setting: %s", data), ErrUnknownEventType)
+	}
+
+	if err := p.storage.SaveChatSettings(context.Background(), settings); err != nil {
+		return nil, e.Wrap("can't save chat settings", err)
+	}
+	// Кнопки сразу на новом языке, если переключили язык.
+	return newResponse(editMarkupAction{markup: settingsButtons(localeByCode(settings.Language), settings)}), nil
+}
+
+// withChatSettings загружает настройки чата в запрос.
+func withChatSettings(next Handler) Handler {
+	return func(p *Processor, req *Request) (*Response, error) {
+		settings, err := p.chatSettings(req.Chat.ID)
+		if err != nil {
+			return nil, e.Wrap("can't get chat settings", err)
+		}
+		req.Settings = settings
+		req.Locale = localeByCode(settings.Language)
+		return next(p, req)
+	}
+}
+
+// isGame показывает, относится ли команда к играм, которые отключаются в /settings.
+// Команды админов бота не отключаются.
+func (c *Command) isGame() bool {
+	return (c.Category == GamesCategory || c.Category == AuctionCategory) && c.Permission != BotAdmin
+}
+
+// checkChatSettings не выполняет команды, выключенные в настройках чата.
+func checkChatSettings(next Handler) Handler {
+	return func(p *Processor, req *Request) (*Response, error) {
+		if req.Command.isGame() && !req.Settings.Games {
+			return textResponse(req.Locale.Text(msgGamesDisabled), req.MessageID), nil
+		}
+		return next(p, req)
+	}
+}
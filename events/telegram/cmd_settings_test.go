@@ -0,0 +1,43 @@
+package telegram
+
+import "testing"
+
+func TestSettingsToggle(t *testing.T) {
+	settings := defaultChatSettings(-100)
+	keys := make(map[string]bool)
+	for _, s := range allSettings {
+		if keys[s.key] {
+			t.Errorf("duplicate setting key %s", s.key)
+		}
+		keys[s.key] = true
+		if len(callbackData(settingsCallback, s.key)) > maxCallbackDataLength {
+			t.Errorf("setting %s: callback data too long", s.key)
+		}
+
+		before := s.value(settings)
+		s.toggle(settings)
+		if s.value(settings) == before {
+			t.Errorf("setting %s: toggle did not change %q", s.key, before)
+		}
+	}
+	if settings.Games || settings.AutoReplies || settings.DeleteCommands || settings.Greetings || settings.Language == defaultLanguage {
+		t.Errorf("not all settings toggled: %+v", settings)
+	}
+
+	code := defaultLanguage
+	for range locales {
+		code = nextLanguage(code)
+	}
+	if code != defaultLanguage {
+		t.Errorf("locales do not cycle back to %s, got %s", defaultLanguage, code)
+	}
+}
+
+func TestGameCommands(t *testing.T) {
+	if !commandByName(DicStartCmd).isGame() || !commandByName(StartAuctionCmd).isGame() {
+		t.Errorf("games are not recognized")
+	}
+	if commandByName(ChangeDickCmd).isGame() || commandByName(SettingsCmd).isGame() {
+		t.Errorf("bot admin and chat commands must not be disabled with games")
+	}
+}
@@ -2,8 +2,8 @@ package telegram
 
 import (
 	"context"
-	"fmt"
 	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
 	"tg_ics_useful_bot/lib/e"
 	"tg_ics_useful_bot/storage"
 )
@@ -12,32 +12,30 @@ import (
 type myStatsExec string
 
 // Exec: /my_stats - возвращает статистику пользователя в данном чате.
-func (a myStatsExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
-	message := fmt.Sprintf(msgUserStats, userStats.MessageCount, userStats.DickPlusCount,
+func (a myStatsExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+	lang := p.locale(chat.ID)
+	message := lang.Text(msgUserStats, userStats.MessageCount, userStats.DickPlusCount,
 		userStats.DickMinusCount, userStats.YesCount, userStats.NoCount, userStats.DuelsCount,
 		userStats.DuelsWinCount, userStats.DuelsLoseCount, userStats.KillCount, userStats.DieCount)
-	mthd := sendMessageMethod
-	replyMessageId := messageID
-	return &Response{message: message, method: mthd, replyMessageId: replyMessageId}, nil
+	return textResponse(message, messageID), nil
 }
 
 // chatStatsExec предоставляет Exec метод для выполнения /chat_stats.
 type chatStatsExec string
 
 // Exec: /chat_stats - возвращает всю статистику данного чата.
-func (a chatStatsExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+func (a chatStatsExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+	lang := p.locale(chat.ID)
 	userStats, err := p.chatStats(chat.ID)
 	if err != nil {
 		return nil, e.Wrap("can't get chat stats: ", err)
 	}
-	message := fmt.Sprintf(msgUserStats, userStats.MessageCount, userStats.DickPlusCount,
+	message := lang.Text(msgUserStats, userStats.MessageCount, userStats.DickPlusCount,
 		userStats.DickMinusCount, userStats.YesCount, userStats.NoCount, userStats.DuelsCount,
 		userStats.DuelsWinCount, userStats.DuelsLoseCount, userStats.KillCount, userStats.DieCount)
-	replyMessageId := messageID
-	mthd := sendMessageMethod
-	return &Response{message: message, method: mthd, replyMessageId: replyMessageId}, nil
+	return textResponse(message, messageID), nil
 }
 
 // chatStats формирует статистику чата, суммирая все статистики пользователей данного чата.
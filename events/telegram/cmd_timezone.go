@@ -0,0 +1,70 @@
+package telegram
+
+import (
+	"context"
+	"log"
+	"strings"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/lib/schedule"
+	"tg_ics_useful_bot/storage"
+	"time"
+)
+
+// defaultTimezone часовой пояс чата, если он не настроен.
+const defaultTimezone = "Europe/Moscow"
+
+// timezoneExec предоставляет Exec метод для выполнения /timezone.
+type timezoneExec string
+
+// Exec: /timezone [Area/City] - показывает или изменяет часовой пояс чата.
+func (a timezoneExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+
+	lang := p.locale(chat.ID)
+	if !params.Has("Area/City") {
+		loc := p.chatLocation(chat.ID)
+		message := lang.Text(msgChatTimezone, loc.String(), time.Now().In(loc).Format("15:04"))
+		return textResponse(message, messageID), nil
+	}
+
+	name := params.String("Area/City")
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "" || strings.EqualFold(name, "local") {
+		message := lang.Text(msgWrongTimezone, name)
+		return textResponse(message, messageID), nil
+	}
+	if err = p.storage.SetChatTimezone(context.Background(), chat.ID, loc.String()); err != nil {
+		return nil, e.Wrap("can't set chat timezone", err)
+	}
+	message := lang.Text(msgChatTimezone, loc.String(), time.Now().In(loc).Format("15:04"))
+	return textResponse(message, messageID), nil
+}
+
+// chatLocation возвращает часовой пояс чата, по умолчанию Europe/Moscow.
+func (p *Processor) chatLocation(chatID int) *time.Location {
+	name, err := p.storage.GetChatTimezone(context.Background(), chatID)
+	if err != nil && err != storage.ErrChatNotExist {
+		log.Printf("[ERROR] can't get timezone of chat #%d: %v", chatID, err)
+	}
+	if name == "" {
+		name = defaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Printf("[ERROR] can't load timezone %s: %v", name, err)
+		return time.UTC
+	}
+	return loc
+}
+
+// chatNow возвращает текущее время в часовом поясе чата.
+func (p *Processor) chatNow(chatID int) time.Time {
+	return time.Now().In(p.chatLocation(chatID))
+}
+
+// isNewDay показывает, наступил ли в часовом поясе now новый день с момента last.
+func isNewDay(last, now time.Time) bool {
+	return schedule.StartOfDay(last.In(now.Location())).Before(schedule.StartOfDay(now))
+}
@@ -2,30 +2,102 @@ package telegram
 
 import (
 	"strconv"
+	"strings"
 	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
+	"tg_ics_useful_bot/lib/i18n"
+	"tg_ics_useful_bot/lib/utils"
 	"tg_ics_useful_bot/storage"
 )
 
 // helpExec предоставляет метод Exec для выполнения /help.
 type helpExec string
 
-// Exec: /help - возвращает help сообщениею
-func (a helpExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+// Exec: /help [команда] - возвращает список команд по разделам или подробную справку по одной команде.
+func (a helpExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 
-	message := msgHelp
-	mthd := sendMessageMethod
-	return &Response{message: message, method: mthd, replyMessageId: -1, parseMode: telegram.Markdown}, nil
+	lang := p.locale(chat.ID)
+	isBotAdmin := p.isAdmin(user.ID)
+	if params.Has("command") {
+		name := "/" + strings.TrimPrefix(params.String("command"), "/")
+		cmd := commandByName(name)
+		if cmd == nil || (cmd.Permission == BotAdmin && !isBotAdmin) {
+			return textResponse(lang.Text(msgUnknownHelpCommand, name), messageID), nil
+		}
+		return newResponse(sendTextAction{text: commandHelp(lang, cmd), parseMode: telegram.Markdown}), nil
+	}
+	return newResponse(sendTextAction{text: helpMessage(lang, isBotAdmin), parseMode: telegram.Markdown}), nil
+}
+
+// helpMessage возвращает список команд по разделам. Команды админов бота показываются только им.
+func helpMessage(lang *i18n.Locale, withBotAdmin bool) string {
+	var b strings.Builder
+	b.WriteString(lang.Text(msgHelpHeader))
+	for _, category := range categories {
+		lines := make([]string, 0)
+		for _, cmd := range allCommands {
+			if cmd.Category != category || cmd.Permission == BotAdmin {
+				continue
+			}
+			line := utils.EscapeMarkdown(cmd.usage(lang)) + " - " + utils.EscapeMarkdown(lang.Text(cmd.Description))
+			switch cmd.Permission {
+			case ChatAdmin:
+				line += lang.Text(msgHelpChatAdmin)
+			case ChatAdminOnly:
+				line += lang.Text(msgHelpChatAdminOnly)
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		b.WriteString("\n*" + lang.Text(string(category)) + "*\n" + strings.Join(lines, "\n") + "\n")
+	}
+
+	if withBotAdmin {
+		b.WriteString(lang.Text(msgHelpBotAdminHeader))
+		for _, cmd := range allCommands {
+			if cmd.Permission == BotAdmin {
+				b.WriteString(utils.EscapeMarkdown(cmd.usage(lang)) + " - " +
+					utils.EscapeMarkdown(lang.Text(cmd.Description)) + "\n")
+			}
+		}
+	}
+	b.WriteString(lang.Text(msgHelpFooter))
+	return b.String()
+}
+
+// commandHelp возвращает подробную справку по команде.
+func commandHelp(lang *i18n.Locale, cmd *Command) string {
+	lines := []string{"`" + cmd.usage(lang) + "`", utils.EscapeMarkdown(lang.Text(cmd.Description))}
+	if cmd.Details != "" {
+		lines = append(lines, lang.Text(cmd.Details))
+	}
+	if len(cmd.Aliases) > 0 {
+		lines = append(lines, lang.Text(msgHelpAliases, utils.EscapeMarkdown(strings.Join(cmd.Aliases, ", "))))
+	}
+	switch cmd.Permission {
+	case ChatAdmin:
+		lines = append(lines, lang.Text(msgHelpChatAdminDetails))
+	case ChatAdminOnly:
+		lines = append(lines, lang.Text(msgHelpChatAdminOnlyDetails))
+	case BotAdmin:
+		lines = append(lines, lang.Text(msgHelpBotAdminDetails))
+	}
+	if cmd.Role != "" {
+		lines = append(lines, lang.Text(msgHelpRole, utils.EscapeMarkdown(string(cmd.Role))))
+	}
+	return strings.Join(lines, "\n")
 }
 
 // chatIDExec предоставляет метод Exec для выполнения /chat_id.
 type chatIDExec string
 
 // Exec: /chat_id - возвращает chat id.
-func (a chatIDExec) Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-	userStats *storage.DBUserStat, messageID int) (*Response, error) {
+func (a chatIDExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
 
 	message := strconv.Itoa(chat.ID)
-	mthd := sendMessageMethod
-	return &Response{message: message, method: mthd, replyMessageId: -1}, nil
+	return textResponse(message, 0), nil
 }
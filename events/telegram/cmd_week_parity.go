@@ -0,0 +1,85 @@
+package telegram
+
+import (
+	"context"
+	"log"
+	"strings"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/lib/i18n"
+	"tg_ics_useful_bot/lib/schedule"
+	"tg_ics_useful_bot/storage"
+	"time"
+)
+
+// weekParityAliases названия недель, которые понимает /week.
+var weekParityAliases = map[string]schedule.WeekParity{
+	"числитель": schedule.Numerator, "числ": schedule.Numerator, "нечёт": schedule.Numerator,
+	"нечет": schedule.Numerator, "odd": schedule.Numerator,
+	"знаменатель": schedule.Denominator, "знам": schedule.Denominator, "чёт": schedule.Denominator,
+	"чет": schedule.Denominator, "even": schedule.Denominator,
+}
+
+// weekParityExec предоставляет Exec метод для выполнения /week.
+type weekParityExec string
+
+// Exec: /week [числитель|знаменатель] - показывает, какая сейчас неделя,
+// или задаёт тип текущей недели. Занятия с пометкой другой недели убираются из расписания.
+func (a weekParityExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+
+	lang := p.locale(chat.ID)
+	now := p.chatNow(chat.ID)
+	if !params.Has("week") {
+		message := p.weekLabel(chat.ID, now)
+		if message == "" {
+			message = lang.Text(msgWeekParityNotSet)
+		}
+		return textResponse(message, messageID), nil
+	}
+
+	week, ok := weekParityAliases[strings.ToLower(params.String("week"))]
+	if !ok {
+		cmd := commandByName(string(a))
+		return argsErrorResponse(lang, cmd, cmd.argError("week", params.String("week")), messageID), nil
+	}
+
+	numeratorWeek := schedule.StartOfWeek(now)
+	if week == schedule.Denominator {
+		numeratorWeek = numeratorWeek.AddDate(0, 0, -7)
+	}
+	err := p.storage.SetNumeratorWeek(context.Background(), chat.ID, numeratorWeek)
+	if err == storage.ErrCalendarNotExist {
+		return textResponse(lang.Text(msgCalendarNotExists), messageID), nil
+	} else if err != nil {
+		return nil, e.Wrap("can't set numerator week", err)
+	}
+	p.schedules.Invalidate(chat.ID)
+
+	return textResponse(p.weekLabel(chat.ID, now), messageID), nil
+}
+
+// weekLabel возвращает строку с типом недели, в которую входит t, пустую строку если недели в чате не различаются.
+func (p *Processor) weekLabel(chatID int, t time.Time) string {
+	lang := p.locale(chatID)
+	c, err := p.storage.GetCalendar(context.Background(), chatID)
+	if err != nil {
+		if err != storage.ErrCalendarNotExist {
+			log.Printf("[ERROR] can't get calendar of chat #%d: %v", chatID, err)
+		}
+		return ""
+	}
+	if c.NumeratorWeek == nil {
+		return ""
+	}
+	return lang.Text(msgWeekParity, weekParityName(lang, schedule.ParityOf(t, *c.NumeratorWeek)))
+}
+
+// weekParityName возвращает название недели на языке lang: числитель или знаменатель.
+func weekParityName(lang *i18n.Locale, week schedule.WeekParity) string {
+	if week == schedule.Denominator {
+		return lang.Text(msgWeekDenominator)
+	}
+	return lang.Text(msgWeekNumerator)
+}
@@ -1,7 +1,12 @@
 package telegram
 
-const (
-	suffix = "@ics_useful_bot"
+import (
+	"errors"
+	"strings"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
+	"tg_ics_useful_bot/lib/i18n"
+	"time"
 )
 
 const (
@@ -16,25 +21,40 @@ const (
 
 	AddCalendarIDCmd = "/add_calendar"
 
-	ScheduleCmd = "/schedule"
-	AnecdotCmd  = "/joke"
-	XkcdCmd     = "/xkcd"
-	FlipCmd     = "/flip"
+	ScheduleCmd       = "/schedule"
+	NextLessonCmd     = "/next"
+	ScheduleNotifyCmd = "/schedule_notify"
+	WeekParityCmd     = "/week"
+	AnecdotCmd        = "/joke"
+	XkcdCmd           = "/xkcd"
+	FlipCmd           = "/flip"
 
 	AllCmd = "/all"
 
 	AddHomeworkCmd    = "/add"
 	GetHomeworkCmd    = "/get"
 	DeleteHomeworkCmd = "/delete"
-	CancelHomeworkCmd = "/cancel"
+	CancelDialogCmd   = "/cancel"
+	ExportHomeworkCmd = "/export_homework"
+	DigestCmd         = "/digest"
 
 	GetMyStatsCmd   = "/my_stats"
 	GetChatStatsCmd = "/chat_stats"
 
 	GetChatIDCmd = "/chat_id"
+	TimezoneCmd  = "/timezone"
 
 	GetHPCmd = "/hp"
 
+	RolesCmd      = "/roles"
+	GrantRoleCmd  = "/grant"
+	RevokeRoleCmd = "/revoke"
+	CooldownCmd   = "/cooldown"
+	AutoReplyCmd  = "/autoreply"
+	WelcomeCmd    = "/welcome"
+	GoodbyeCmd    = "/goodbye"
+	SettingsCmd   = "/settings"
+
 	// admins commands
 	ChangeDickCmd         = "/change_dick"
 	SendMessageByAdminCmd = "/send_message"
@@ -44,3 +64,524 @@ const (
 	AddDepositCmd    = "/deposit"
 	AuctionCmd       = "/auction"
 )
+
+// Permission определяет, кто может пользоваться командой.
+type Permission int
+
+const (
+	// Everyone команда доступна всем.
+	Everyone Permission = iota
+	// ChatAdmin изменить настройки командой (вызвать её с аргументами) могут только админы группы,
+	// посмотреть - все.
+	ChatAdmin
+	// ChatAdminOnly команда только для админов группы.
+	ChatAdminOnly
+	// BotAdmin команда только для админов бота, в общей справке не показывается.
+	BotAdmin
+)
+
+// Category раздел справки, в котором показывается команда. Значение - ключ названия раздела в каталоге.
+type Category string
+
+const (
+	HomeworkCategory Category = msgCategoryHomework
+	ScheduleCategory Category = msgCategorySchedule
+	GamesCategory    Category = msgCategoryGames
+	AuctionCategory  Category = msgCategoryAuction
+	FunCategory      Category = msgCategoryFun
+	ChatCategory     Category = msgCategoryChat
+)
+
+// categories порядок разделов в справке.
+var categories = []Category{
+	HomeworkCategory, ScheduleCategory, GamesCategory, AuctionCategory, FunCategory, ChatCategory,
+}
+
+// Command описание команды бота, из которого строятся /help и список команд в Telegram.
+type Command struct {
+	// Name команда со слешем, например /help.
+	Name    string
+	Aliases []string
+	// Args аргументы команды, они разбираются до вызова Executor. nil - команда разбирает текст сама.
+	Args cmdargs.Spec
+	// Usage ключ формата аргументов для справки, если он сложнее Args, например [день ЧЧ:ММ [pin] | off].
+	Usage string
+	// Description ключ краткого описания без Markdown разметки, оно же отправляется в setMyCommands.
+	Description string
+	// Details ключ подробного описания для /help {команда}, в Markdown.
+	Details    string
+	Permission Permission
+	// Role роль, обладатели которой могут выполнять команду наравне с админами группы.
+	Role Role
+	// Cooldown как часто можно вызывать команду, если в чате не настроено иначе через /cooldown.
+	Cooldown Cooldown
+	Category Category
+	Executor CmdExecutor
+}
+
+// allCommands список всех возможных команд бота в порядке их показа в справке.
+var allCommands = []*Command{
+	{
+		Name:        AddHomeworkCmd,
+		Aliases:     []string{"/add_homework"},
+		Description: msgCmdAddHomework,
+		Details:     msgCmdAddHomeworkDetails,
+		Category:    HomeworkCategory,
+		Executor:    addHomeworkExec(AddHomeworkCmd),
+	},
+	{
+		Name:        GetHomeworkCmd,
+		Args:        cmdargs.Spec{cmdargs.Int("number").Optional(), cmdargs.Rest("subject").Optional()},
+		Description: msgCmdGetHomework,
+		Details:     msgCmdGetHomeworkDetails,
+		Category:    HomeworkCategory,
+		Executor:    getHomeworkExec(GetHomeworkCmd),
+	},
+	{
+		Name:        DeleteHomeworkCmd,
+		Args:        cmdargs.Spec{cmdargs.Int("id")},
+		Description: msgCmdDeleteHomework,
+		Category:    HomeworkCategory,
+		Executor:    deleteHomeworkExec(DeleteHomeworkCmd),
+	},
+	{
+		Name:        CancelDialogCmd,
+		Description: msgCmdCancelDialog,
+		Category:    HomeworkCategory,
+		Executor:    cancelDialogExec(CancelDialogCmd),
+	},
+	{
+		Name:        ExportHomeworkCmd,
+		Args:        cmdargs.Spec{cmdargs.Choice("format", "ics", "csv", "md").Default("csv")},
+		Description: msgCmdExportHomework,
+		Details:     msgCmdExportHomeworkDetails,
+		Category:    HomeworkCategory,
+		Executor:    exportHomeworkExec(ExportHomeworkCmd),
+	},
+	{
+		Name: DigestCmd,
+		Args: cmdargs.Spec{
+			cmdargs.String("day").Optional(),
+			cmdargs.String("time").Optional(),
+			cmdargs.Choice("pin", digestPin).Optional(),
+		},
+		Usage:       msgCmdDigestUsage,
+		Description: msgCmdDigest,
+		Details:     msgCmdDigestDetails,
+		Permission:  ChatAdmin,
+		Category:    HomeworkCategory,
+		Executor:    digestExec(DigestCmd),
+	},
+
+	{
+		Name: ScheduleCmd,
+		Args: cmdargs.Spec{
+			cmdargs.Choice("image", scheduleImageArg, "картинка").Optional(),
+			cmdargs.String("period").Optional(),
+		},
+		Usage:       msgCmdScheduleUsage,
+		Description: msgCmdSchedule,
+		Details:     msgCmdScheduleDetails,
+		Category:    ScheduleCategory,
+		Executor:    scheduleExec(ScheduleCmd),
+	},
+	{
+		Name:        NextLessonCmd,
+		Description: msgCmdNextLesson,
+		Category:    ScheduleCategory,
+		Executor:    nextLessonExec(NextLessonCmd),
+	},
+	{
+		Name:        WeekParityCmd,
+		Args:        cmdargs.Spec{cmdargs.String("week").Optional()},
+		Usage:       msgCmdWeekParityUsage,
+		Description: msgCmdWeekParity,
+		Details:     msgCmdWeekParityDetails,
+		Permission:  ChatAdmin,
+		Category:    ScheduleCategory,
+		Executor:    weekParityExec(WeekParityCmd),
+	},
+	{
+		Name: ScheduleNotifyCmd,
+		Args: cmdargs.Spec{
+			cmdargs.String("time").Optional(),
+			cmdargs.Choice("remind", scheduleNotifyRemind).Optional(),
+			cmdargs.Int("minutes").Optional(),
+		},
+		Usage:       msgCmdScheduleNotifyUsage,
+		Description: msgCmdScheduleNotify,
+		Details:     msgCmdScheduleNotifyDetails,
+		Permission:  ChatAdmin,
+		Category:    ScheduleCategory,
+		Executor:    scheduleNotifyExec(ScheduleNotifyCmd),
+	},
+	{
+		Name:        AddCalendarIDCmd,
+		Args:        cmdargs.Spec{cmdargs.String("calendar")},
+		Usage:       msgCmdAddCalendarIDUsage,
+		Description: msgCmdAddCalendarID,
+		Details:     msgCmdAddCalendarIDDetails,
+		Permission:  ChatAdmin,
+		Category:    ScheduleCategory,
+		Executor:    addCalendarExec(AddCalendarIDCmd),
+	},
+
+	{
+		Name:        DicStartCmd,
+		Description: msgCmdDicStart,
+		Category:    GamesCategory,
+		Executor:    dickStartExec(DicStartCmd),
+	},
+	{
+		Name:        DickTopCmd,
+		Description: msgCmdDickTop,
+		Category:    GamesCategory,
+		Executor:    dickTopExec(DickTopCmd),
+	},
+	{
+		Name:        DickDuelCmd,
+		Args:        cmdargs.Spec{cmdargs.Mention("username").Optional()},
+		Description: msgCmdDickDuel,
+		Category:    GamesCategory,
+		Executor:    duelExec(DickDuelCmd),
+	},
+	{
+		Name:        GetHPCmd,
+		Description: msgCmdGetHP,
+		Category:    GamesCategory,
+		Executor:    getHpExec(GetHPCmd),
+	},
+	{
+		Name:        GayStartCmd,
+		Description: msgCmdGayStart,
+		Details:     msgCmdGayStartDetails,
+		Category:    GamesCategory,
+		Executor:    gayExec(GayStartCmd),
+	},
+	{
+		Name:        GayTopCmd,
+		Description: msgCmdGayTop,
+		Category:    GamesCategory,
+		Executor:    topGaysExec(GayTopCmd),
+	},
+
+	{
+		Name:        StartAuctionCmd,
+		Description: msgCmdStartAuction,
+		Permission:  ChatAdminOnly,
+		Role:        AuctioneerRole,
+		Category:    AuctionCategory,
+		Executor:    startAuctionExec(StartAuctionCmd),
+	},
+	{
+		Name:        AddDepositCmd,
+		Args:        cmdargs.Spec{cmdargs.Int("amount")},
+		Description: msgCmdAddDeposit,
+		Details:     msgCmdAddDepositDetails,
+		Category:    AuctionCategory,
+		Executor:    addDepositExec(AddDepositCmd),
+	},
+	{
+		Name:        AuctionCmd,
+		Description: msgCmdAuction,
+		Category:    AuctionCategory,
+		Executor:    auctionExec(AuctionCmd),
+	},
+	{
+		Name:        FinishAuctionCmd,
+		Description: msgCmdFinishAuction,
+		Permission:  BotAdmin,
+		Category:    AuctionCategory,
+		Executor:    finishAuctionExec(FinishAuctionCmd),
+	},
+
+	{
+		Name:        FlipCmd,
+		Description: msgCmdFlip,
+		Cooldown:    Cooldown{PerUser: 10 * time.Second},
+		Category:    FunCategory,
+		Executor:    flipExec(FlipCmd),
+	},
+	{
+		Name:        XkcdCmd,
+		Description: msgCmdXkcd,
+		Cooldown:    Cooldown{PerUser: 30 * time.Second, PerChat: 10 * time.Second},
+		Category:    FunCategory,
+		Executor:    xkcdExec(XkcdCmd),
+	},
+	{
+		Name:        AnecdotCmd,
+		Description: msgCmdAnecdot,
+		Cooldown:    Cooldown{PerUser: 30 * time.Second, PerChat: 10 * time.Second},
+		Category:    FunCategory,
+		Executor:    anekdotExec(AnecdotCmd),
+	},
+
+	{
+		Name:        HelpCmd,
+		Aliases:     []string{"/start"},
+		Args:        cmdargs.Spec{cmdargs.String("command").Optional()},
+		Description: msgCmdHelp,
+		Category:    ChatCategory,
+		Executor:    helpExec(HelpCmd),
+	},
+	{
+		Name:        AllCmd,
+		Description: msgCmdAll,
+		Cooldown:    Cooldown{PerChat: 5 * time.Minute},
+		Category:    ChatCategory,
+		Executor:    allUsernamesExec(AllCmd),
+	},
+	{
+		Name:        GetMyStatsCmd,
+		Description: msgCmdGetMyStats,
+		Category:    ChatCategory,
+		Executor:    myStatsExec(GetMyStatsCmd),
+	},
+	{
+		Name:        GetChatStatsCmd,
+		Description: msgCmdGetChatStats,
+		Category:    ChatCategory,
+		Executor:    chatStatsExec(GetChatStatsCmd),
+	},
+	{
+		Name:        GetChatIDCmd,
+		Description: msgCmdGetChatID,
+		Category:    ChatCategory,
+		Executor:    chatIDExec(GetChatIDCmd),
+	},
+	{
+		Name:        TimezoneCmd,
+		Args:        cmdargs.Spec{cmdargs.String("Area/City").Optional()},
+		Description: msgCmdTimezone,
+		Details:     msgCmdTimezoneDetails,
+		Permission:  ChatAdmin,
+		Category:    ChatCategory,
+		Executor:    timezoneExec(TimezoneCmd),
+	},
+	{
+		Name:        SettingsCmd,
+		Description: msgCmdSettings,
+		Details:     msgCmdSettingsDetails,
+		Permission:  ChatAdmin,
+		Category:    ChatCategory,
+		Executor:    settingsExec(SettingsCmd),
+	},
+	{
+		Name: CooldownCmd,
+		Args: cmdargs.Spec{
+			cmdargs.String("command").Optional(),
+			cmdargs.Choice("scope", "user", "chat", "reset").Optional(),
+			cmdargs.Duration("duration").Optional(),
+		},
+		Description: msgCmdCooldown,
+		Details:     msgCmdCooldownDetails,
+		Permission:  ChatAdmin,
+		Category:    ChatCategory,
+		Executor:    cooldownExec(CooldownCmd),
+	},
+	{
+		Name: AutoReplyCmd,
+		Args: cmdargs.Spec{
+			cmdargs.Choice("action", "add", "list", "remove").Optional(),
+			cmdargs.Rest("rule").Optional(),
+		},
+		Usage:       msgCmdAutoReplyUsage,
+		Description: msgCmdAutoReply,
+		Details:     msgCmdAutoReplyDetails,
+		Permission:  ChatAdmin,
+		Category:    ChatCategory,
+		Executor:    autoReplyExec(AutoReplyCmd),
+	},
+	{
+		Name:        WelcomeCmd,
+		Args:        cmdargs.Spec{cmdargs.Rest("text").Optional()},
+		Usage:       msgCmdWelcomeUsage,
+		Description: msgCmdWelcome,
+		Details:     msgCmdWelcomeDetails,
+		Permission:  ChatAdmin,
+		Category:    ChatCategory,
+		Executor:    greetingExec(WelcomeCmd),
+	},
+	{
+		Name:        GoodbyeCmd,
+		Args:        cmdargs.Spec{cmdargs.Rest("text").Optional()},
+		Usage:       msgCmdGoodbyeUsage,
+		Description: msgCmdGoodbye,
+		Details:     msgCmdGoodbyeDetails,
+		Permission:  ChatAdmin,
+		Category:    ChatCategory,
+		Executor:    greetingExec(GoodbyeCmd),
+	},
+	{
+		Name:        RolesCmd,
+		Description: msgCmdRoles,
+		Category:    ChatCategory,
+		Executor:    rolesExec(RolesCmd),
+	},
+	{
+		Name:        GrantRoleCmd,
+		Args:        cmdargs.Spec{cmdargs.Mention("username"), cmdargs.Choice("role", roleNames()...)},
+		Description: msgCmdGrantRole,
+		Details:     msgCmdGrantRoleDetails,
+		Permission:  ChatAdminOnly,
+		Category:    ChatCategory,
+		Executor:    grantRoleExec(GrantRoleCmd),
+	},
+	{
+		Name:        RevokeRoleCmd,
+		Args:        cmdargs.Spec{cmdargs.Mention("username"), cmdargs.Choice("role", roleNames()...)},
+		Description: msgCmdRevokeRole,
+		Permission:  ChatAdminOnly,
+		Category:    ChatCategory,
+		Executor:    revokeRoleExec(RevokeRoleCmd),
+	},
+	{
+		Name:        ChangeDickCmd,
+		Args:        cmdargs.Spec{cmdargs.Int("chat_id"), cmdargs.Int("user_id"), cmdargs.Int("value")},
+		Description: msgCmdChangeDick,
+		Permission:  BotAdmin,
+		Category:    GamesCategory,
+		Executor:    adminChangeDickExec(ChangeDickCmd),
+	},
+	{
+		Name:        SendMessageByAdminCmd,
+		Args:        cmdargs.Spec{cmdargs.Int("chat_id"), cmdargs.Rest("message")},
+		Description: msgCmdSendMessageByAdmin,
+		Permission:  BotAdmin,
+		Category:    ChatCategory,
+		Executor:    adminSendMessageExec(SendMessageByAdminCmd),
+	},
+}
+
+// commandByName возвращает команду по названию или псевдониму (со слешем), nil если её нет.
+func commandByName(name string) *Command {
+	name = strings.ToLower(name)
+	for _, cmd := range allCommands {
+		if cmd.Name == name {
+			return cmd
+		}
+		for _, alias := range cmd.Aliases {
+			if alias == name {
+				return cmd
+			}
+		}
+	}
+	return nil
+}
+
+// usage возвращает команду с форматом аргументов.
+func (c *Command) usage(lang *i18n.Locale) string {
+	if c.Usage != "" {
+		return c.Name + " " + lang.Text(c.Usage)
+	}
+	return strings.TrimSpace(c.Name + " " + c.Args.Usage())
+}
+
+// parseArgs разбирает аргументы из текста сообщения с командой, если команда их описывает.
+func (c *Command) parseArgs(text string) (cmdargs.Values, error) {
+	if c.Args == nil {
+		return cmdargs.Values{}, nil
+	}
+	_, args, _ := strings.Cut(text, " ")
+	return c.Args.Parse(args)
+}
+
+// argError возвращает ошибку аргумента name: ErrMissing, если value пустое, иначе ErrInvalid.
+// Нужна для значений, которые подходят по типу, но не подходят команде.
+func (c *Command) argError(name, value string) error {
+	err := &cmdargs.Error{Arg: cmdargs.String(name), Value: value, Err: cmdargs.ErrInvalid}
+	for _, a := range c.Args {
+		if a.Name() == name {
+			err.Arg = a
+		}
+	}
+	if value == "" {
+		err.Err = cmdargs.ErrMissing
+	}
+	return err
+}
+
+// argsErrorResponse отвечает на сообщение replyTo, что аргументы команды неправильные, и показывает её формат.
+func argsErrorResponse(lang *i18n.Locale, cmd *Command, err error, replyTo int) *Response {
+	response := textResponse(argsErrorMessage(lang, cmd, err), replyTo)
+	response.usageError = true
+	return response
+}
+
+// argsErrorMessage возвращает сообщение о неправильных аргументах команды с её форматом.
+func argsErrorMessage(lang *i18n.Locale, cmd *Command, err error) string {
+	reason := ""
+	var argErr *cmdargs.Error
+	if errors.As(err, &argErr) {
+		switch {
+		case errors.Is(err, cmdargs.ErrMissing):
+			reason = lang.Text(msgMissingArg, argErr.Arg.Name()) + "\n"
+		case errors.Is(err, cmdargs.ErrInvalid):
+			if expected := expectedArg(lang, argErr.Arg); expected != "" {
+				reason = lang.Text(msgInvalidArg, argErr.Value, argErr.Arg.Name(), expected) + "\n"
+			} else {
+				// строку отклонила сама команда, правильный формат покажет usage
+				reason = lang.Text(msgInvalidValue, argErr.Value, argErr.Arg.Name()) + "\n"
+			}
+		case errors.Is(err, cmdargs.ErrTooMany):
+			reason = lang.Text(msgTooManyArgs, argErr.Value) + "\n"
+		}
+	}
+	return reason + lang.Text(msgCommandUsage, cmd.usage(lang))
+}
+
+// expectedArg возвращает описание значения, которое ждёт аргумент.
+// Для строк описания нет: подходит любая, а отклоняет её сама команда.
+func expectedArg(lang *i18n.Locale, arg cmdargs.Arg) string {
+	if arg.Kind() == cmdargs.ChoiceKind {
+		return lang.Text(msgArgChoice, strings.Join(arg.Choices(), ", "))
+	}
+	key, ok := argKinds[arg.Kind()]
+	if !ok {
+		return ""
+	}
+	return lang.Text(key)
+}
+
+// argKinds ключи описаний типов аргументов для сообщений об ошибках.
+var argKinds = map[cmdargs.Kind]string{
+	cmdargs.IntKind:      msgArgKindInt,
+	cmdargs.DurationKind: msgArgKindDuration,
+	cmdargs.UsernameKind: msgArgKindUsername,
+	cmdargs.MentionKind:  msgArgKindMention,
+}
+
+// SetCommands отправляет в Telegram список команд для подсказок в поле ввода: по умолчанию на языке
+// по умолчанию, а пользователям Telegram на других языках бота - на их языке.
+// Команды админов бота в список не попадают.
+func (p *Processor) SetCommands() error {
+	if err := p.tg.SetMyCommands(botCommands(localeByCode(defaultLanguage)), ""); err != nil {
+		return err
+	}
+	for _, l := range locales {
+		// варианты языка вроде ru-clean Telegram не знает
+		if l.Code == defaultLanguage || strings.Contains(l.Code, "-") {
+			continue
+		}
+		if err := p.tg.SetMyCommands(botCommands(l), l.Code); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// botCommands возвращает команды с описаниями на языке lang для setMyCommands.
+func botCommands(lang *i18n.Locale) []telegram.BotCommand {
+	commands := make([]telegram.BotCommand, 0, len(allCommands))
+	for _, cmd := range allCommands {
+		if cmd.Permission == BotAdmin {
+			continue
+		}
+		commands = append(commands, telegram.BotCommand{
+			Command:     strings.TrimPrefix(cmd.Name, "/"),
+			Description: lang.Text(cmd.Description),
+		})
+	}
+	return commands
+}
@@ -0,0 +1,156 @@
+package telegram
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/schedule"
+	"tg_ics_useful_bot/lib/utils"
+	"tg_ics_useful_bot/storage"
+	"time"
+	"unicode/utf8"
+)
+
+// botCommandPattern ограничения Telegram на название команды в setMyCommands.
+var botCommandPattern = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+func TestCommandsRegistry(t *testing.T) {
+	seen := make(map[string]bool)
+	known := make(map[Category]bool)
+	for _, c := range categories {
+		known[c] = true
+	}
+
+	for _, cmd := range allCommands {
+		for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+			if seen[name] {
+				t.Errorf("duplicate command name %s", name)
+			}
+			seen[name] = true
+			if !strings.HasPrefix(name, "/") || !botCommandPattern.MatchString(strings.TrimPrefix(name, "/")) {
+				t.Errorf("invalid command name %q", name)
+			}
+		}
+		for _, l := range locales {
+			if n := utf8.RuneCountInString(l.Text(cmd.Description)); n == 0 || n > 256 {
+				t.Errorf("%s: %s description length %d out of range", cmd.Name, l.Code, n)
+			}
+		}
+		if !known[cmd.Category] {
+			t.Errorf("%s: unknown category %q", cmd.Name, cmd.Category)
+		}
+		if cmd.Executor == nil {
+			t.Errorf("%s: no executor", cmd.Name)
+		}
+		if commandByName(cmd.Name) != cmd {
+			t.Errorf("%s: not found by name", cmd.Name)
+		}
+	}
+}
+
+func TestHelpMessage(t *testing.T) {
+	help := helpMessage(ruLocale, false)
+	for _, cmd := range allCommands {
+		name := utils.EscapeMarkdown(cmd.Name)
+		listed := strings.Contains(help, name+" ") || strings.Contains(help, name+"\n")
+		if cmd.Permission == BotAdmin && listed {
+			t.Errorf("bot admin command %s is listed in help", cmd.Name)
+		}
+		if cmd.Permission != BotAdmin && !listed {
+			t.Errorf("command %s is missing in help", cmd.Name)
+		}
+	}
+	if !strings.Contains(helpMessage(ruLocale, true), utils.EscapeMarkdown(ChangeDickCmd)) {
+		t.Errorf("bot admin command %s is missing in admin help", ChangeDickCmd)
+	}
+	if strings.Contains(help, "/my_stats") {
+		t.Errorf("underscore in command name is not escaped")
+	}
+}
+
+func TestAddressedToMe(t *testing.T) {
+	p := &Processor{username: "ics_useful_bot"}
+	tests := []struct {
+		cmd  string
+		want bool
+	}{
+		{"/help", true},
+		{"/help@ics_useful_bot", true},
+		{"/help@ICS_Useful_Bot", true},
+		{"/help@other_bot", false},
+	}
+	for _, tt := range tests {
+		if got := p.addressedToMe(tt.cmd); got != tt.want {
+			t.Errorf("addressedToMe(%q) = %v, want %v", tt.cmd, got, tt.want)
+		}
+		if tt.want && p.getCmd(tt.cmd) == nil {
+			t.Errorf("getCmd(%q) = nil", tt.cmd)
+		}
+	}
+}
+
+func TestArgsErrorMessage(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"/change_dick 1", "Не указан аргумент user_id\nФормат: /change_dick {chat_id} {user_id} {value}"},
+		{"/send_message", "Не указан аргумент chat_id\nФормат: /send_message {chat_id} {message}"},
+		{"/deposit много", "\"много\" не подходит для amount: нужно целое число\nФормат: /deposit {amount}"},
+		{"/export_homework pdf", "\"pdf\" не подходит для format: нужно одно из: ics, csv, md\nФормат: /export_homework [ics|csv|md]"},
+		{"/delete 1 2", "Лишние аргументы: 2\nФормат: /delete {id}"},
+	}
+	for _, tt := range tests {
+		cmd := commandByName(strings.Fields(tt.text)[0])
+		_, err := cmd.parseArgs(tt.text)
+		if err == nil {
+			t.Errorf("parseArgs(%q): no error", tt.text)
+			continue
+		}
+		if got := argsErrorMessage(ruLocale, cmd, err); got != tt.want {
+			t.Errorf("argsErrorMessage(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	const chatID = -100
+	s := newFakeStorage()
+	s.calendars[chatID] = &storage.DBCalendar{ChatID: chatID, Kind: schedule.ICSKind, Data: testICS}
+	p := &Processor{storage: s, schedules: schedule.NewCache(time.Minute)}
+	for _, text := range []string{
+		"/digest пт1 18:00",
+		"/digest пт",
+		"/digest пт 25:00",
+		"/schedule_notify 7.30",
+		"/schedule_notify 07:30 remind",
+		"/schedule_notify 07:30 remind 0",
+		"/week нечётная",
+		"/add_calendar",
+		"/schedule image послезавтра",
+	} {
+		name, _, _ := strings.Cut(text, " ")
+		response, err := execCommand(p, &Request{
+			Text:      text,
+			Chat:      &telegram.Chat{ID: chatID},
+			User:      &telegram.User{ID: 1, Username: "admin"},
+			MessageID: 7,
+			Locale:    ruLocale,
+			Command:   commandByName(name),
+		})
+		if err != nil {
+			t.Errorf("%s: %v", text, err)
+			continue
+		}
+		if !response.usageError {
+			t.Errorf("%s: got %+v, want usage error", text, response)
+		}
+	}
+
+	week := commandByName(WeekParityCmd)
+	want := "\"нечётная\" не подходит для week\nФормат: /week [числитель|знаменатель]"
+	if got := argsErrorMessage(ruLocale, week, week.argError("week", "нечётная")); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
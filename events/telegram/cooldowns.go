@@ -0,0 +1,233 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/lib/i18n"
+	"tg_ics_useful_bot/storage"
+	"time"
+)
+
+// Cooldown ограничение частоты вызова команды, нулевое значение - без ограничения.
+type Cooldown struct {
+	// PerUser как часто команду может вызывать один участник чата.
+	PerUser time.Duration
+	// PerChat как часто команду может вызывать кто угодно в чате.
+	PerChat time.Duration
+}
+
+// cooldownScope ограничение для одного пользователя или всего чата (tgID 0).
+type cooldownScope struct {
+	tgID     int
+	duration time.Duration
+}
+
+// scopes возвращает ограничения, которые нужно проверить и обновить при вызове команды пользователем.
+func (c Cooldown) scopes(userID int) []cooldownScope {
+	scopes := make([]cooldownScope, 0, 2)
+	if c.PerUser > 0 {
+		scopes = append(scopes, cooldownScope{tgID: userID, duration: c.PerUser})
+	}
+	if c.PerChat > 0 {
+		scopes = append(scopes, cooldownScope{tgID: 0, duration: c.PerChat})
+	}
+	return scopes
+}
+
+// cooldownFor возвращает ограничение команды в чате: настроенное через /cooldown или по умолчанию.
+func (p *Processor) cooldownFor(cmd *Command, chatID int) (Cooldown, error) {
+	overrides, err := p.storage.ChatCommandCooldowns(context.Background(), chatID)
+	if err != nil {
+		return Cooldown{}, err
+	}
+	return commandCooldown(cmd, overrides), nil
+}
+
+// commandCooldown возвращает ограничение команды с учётом настроек чата overrides.
+func commandCooldown(cmd *Command, overrides []*storage.DBCommandCooldown) Cooldown {
+	for _, o := range overrides {
+		if o.Command == cmd.Name {
+			return Cooldown{
+				PerUser: time.Duration(o.PerUser) * time.Second,
+				PerChat: time.Duration(o.PerChat) * time.Second,
+			}
+		}
+	}
+	return cmd.Cooldown
+}
+
+// checkCooldown не даёт вызывать команду чаще, чем позволяет её Cooldown.
+// Админы группы и бота не ограничены. Вызов с неправильными аргументами не считается.
+func checkCooldown(next Handler) Handler {
+	return func(p *Processor, req *Request) (*Response, error) {
+		cooldown, err := p.cooldownFor(req.Command, req.Chat.ID)
+		if err != nil {
+			return nil, e.Wrap(fmt.Sprintf("can't get cooldown for %s", req.Command.Name), err)
+		}
+		scopes := cooldown.scopes(req.User.ID)
+		if len(scopes) == 0 || p.isAdmin(req.User.ID) || p.isChatAdmin(req.User, req.Chat.ID) {
+			return next(p, req)
+		}
+
+		now := time.Now()
+		for _, s := range scopes {
+			cd, err := p.storage.GetCooldown(context.Background(), req.Chat.ID, s.tgID, req.Command.Name)
+			if err == storage.ErrCooldownNotExist {
+				continue
+			} else if err != nil {
+				return nil, e.Wrap(fmt.Sprintf("can't check cooldown for %s", req.Command.Name), err)
+			}
+			if now.Before(cd.ExpiresAt) {
+				return p.cooldownReply(req.Locale, req.User, cd, now)
+			}
+		}
+
+		response, err := next(p, req)
+		if err != nil {
+			return nil, err
+		}
+		if response.usageError {
+			return response, nil
+		}
+		for _, s := range scopes {
+			cd := &storage.DBCooldown{ChatID: req.Chat.ID, TgID: s.tgID, Command: req.Command.Name, ExpiresAt: now.Add(s.duration)}
+			if err := p.storage.SaveCooldown(context.Background(), cd); err != nil {
+				log.Print(err)
+			}
+		}
+		return response, nil
+	}
+}
+
+// cooldownReply отвечает, через сколько можно повторить команду. Отвечает один раз за ограничение,
+// следующие вызовы команды просто удаляются, чтобы бот сам не спамил.
+func (p *Processor) cooldownReply(lang *i18n.Locale, user *telegram.User, cd *storage.DBCooldown, now time.Time) (*Response, error) {
+	if cd.Warned {
+		return newResponse(), nil
+	}
+	cd.Warned = true
+	if err := p.storage.SaveCooldown(context.Background(), cd); err != nil {
+		return nil, e.Wrap("can't save cooldown warning", err)
+	}
+	wait := int(math.Ceil(cd.ExpiresAt.Sub(now).Seconds()))
+	return textResponse(lang.Plural(msgCooldownWait, wait, user.Username, wait), 0), nil
+}
+
+// deleteExpiredCooldowns удаляет из базы истёкшие ограничения.
+func (p *Processor) deleteExpiredCooldowns(now time.Time) error {
+	return p.storage.DeleteExpiredCooldowns(context.Background(), now)
+}
+
+// cooldownText возвращает ограничение в виде "раз в 30 сек на участника, раз в 5 мин на чат".
+func cooldownText(lang *i18n.Locale, c Cooldown) string {
+	parts := make([]string, 0, 2)
+	if c.PerUser > 0 {
+		parts = append(parts, lang.Text(msgCooldownPerUser, shortDurationText(lang, c.PerUser)))
+	}
+	if c.PerChat > 0 {
+		parts = append(parts, lang.Text(msgCooldownPerChat, shortDurationText(lang, c.PerChat)))
+	}
+	if len(parts) == 0 {
+		return lang.Text(msgCooldownNone)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// shortDurationText как durationText, но промежутки не кратные минуте показываются в секундах.
+func shortDurationText(lang *i18n.Locale, d time.Duration) string {
+	if d%time.Minute != 0 {
+		seconds := int(d.Seconds())
+		return lang.Plural(msgDurationSeconds, seconds, seconds)
+	}
+	return durationText(lang, d)
+}
+
+// cooldownExec предоставляет метод Exec для выполнения /cooldown.
+type cooldownExec string
+
+// Exec: /cooldown [команда [user|chat длительность | reset]] - показывает или настраивает,
+// как часто можно вызывать команды в чате.
+func (a cooldownExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+
+	lang := p.locale(chat.ID)
+	if !params.Has("command") {
+		message, err := p.chatCooldowns(chat.ID)
+		if err != nil {
+			return nil, e.Wrap("can't exec /cooldown", err)
+		}
+		return textResponse(message, messageID), nil
+	}
+
+	name := "/" + strings.TrimPrefix(params.String("command"), "/")
+	cmd := commandByName(name)
+	if cmd == nil {
+		return textResponse(lang.Text(msgUnknownHelpCommand, name), messageID), nil
+	}
+	cooldown, err := p.cooldownFor(cmd, chat.ID)
+	if err != nil {
+		return nil, e.Wrap("can't exec /cooldown", err)
+	}
+
+	switch params.String("scope") {
+	case "":
+		return textResponse(lang.Text(msgCommandCooldown, cmd.Name, cooldownText(lang, cooldown)), messageID), nil
+	case "reset":
+		if err := p.storage.DeleteCommandCooldown(context.Background(), chat.ID, cmd.Name); err != nil {
+			return nil, e.Wrap("can't exec /cooldown", err)
+		}
+		return textResponse(lang.Text(msgCommandCooldown, cmd.Name, cooldownText(lang, cmd.Cooldown)), 0), nil
+	}
+
+	if !params.Has("duration") {
+		argsErr := &cmdargs.Error{Arg: cmdargs.Duration("duration"), Err: cmdargs.ErrMissing}
+		return argsErrorResponse(lang, commandByName(string(a)), argsErr, messageID), nil
+	}
+	duration := params.Duration("duration")
+	if duration < 0 {
+		argsErr := &cmdargs.Error{Arg: cmdargs.Duration("duration"), Value: duration.String(), Err: cmdargs.ErrInvalid}
+		return argsErrorResponse(lang, commandByName(string(a)), argsErr, messageID), nil
+	}
+	if params.String("scope") == "user" {
+		cooldown.PerUser = duration
+	} else {
+		cooldown.PerChat = duration
+	}
+
+	err = p.storage.SaveCommandCooldown(context.Background(), &storage.DBCommandCooldown{
+		ChatID:  chat.ID,
+		Command: cmd.Name,
+		PerUser: int(cooldown.PerUser.Seconds()),
+		PerChat: int(cooldown.PerChat.Seconds()),
+	})
+	if err != nil {
+		return nil, e.Wrap("can't exec /cooldown", err)
+	}
+	return textResponse(lang.Text(msgCommandCooldown, cmd.Name, cooldownText(lang, cooldown)), 0), nil
+}
+
+// chatCooldowns возвращает список команд, которые в чате можно вызывать не чаще заданного.
+func (p *Processor) chatCooldowns(chatID int) (string, error) {
+	lang := p.locale(chatID)
+	overrides, err := p.storage.ChatCommandCooldowns(context.Background(), chatID)
+	if err != nil {
+		return "", err
+	}
+	lines := make([]string, 0)
+	for _, cmd := range allCommands {
+		cooldown := commandCooldown(cmd, overrides)
+		if len(cooldown.scopes(0)) > 0 {
+			lines = append(lines, lang.Text(msgCommandCooldown, cmd.Name, cooldownText(lang, cooldown)))
+		}
+	}
+	if len(lines) == 0 {
+		return lang.Text(msgNoCooldowns), nil
+	}
+	return lang.Text(msgCooldownsHeader) + strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,112 @@
+package telegram
+
+import (
+	"testing"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
+	"tg_ics_useful_bot/storage"
+	"time"
+)
+
+func TestCommandCooldown(t *testing.T) {
+	xkcd := commandByName(XkcdCmd)
+	if got := commandCooldown(xkcd, nil); got != xkcd.Cooldown {
+		t.Errorf("without overrides: got %+v, want default %+v", got, xkcd.Cooldown)
+	}
+
+	overrides := []*storage.DBCommandCooldown{
+		{Command: FlipCmd, PerUser: 5},
+		{Command: XkcdCmd, PerUser: 0, PerChat: 90},
+	}
+	want := Cooldown{PerChat: 90 * time.Second}
+	if got := commandCooldown(xkcd, overrides); got != want {
+		t.Errorf("with override: got %+v, want %+v", got, want)
+	}
+
+	scopes := want.scopes(42)
+	if len(scopes) != 1 || scopes[0].tgID != 0 || scopes[0].duration != 90*time.Second {
+		t.Errorf("scopes: got %+v, want only chat scope", scopes)
+	}
+}
+
+func TestCooldownText(t *testing.T) {
+	tests := []struct {
+		cooldown Cooldown
+		want     string
+	}{
+		{Cooldown{}, "без ограничений"},
+		{Cooldown{PerUser: 30 * time.Second}, "раз в 30 сек на участника"},
+		{Cooldown{PerUser: 90 * time.Second, PerChat: 5 * time.Minute}, "раз в 90 сек на участника, раз в 5 мин на чат"},
+	}
+	for _, tt := range tests {
+		if got := cooldownText(ruLocale, tt.cooldown); got != tt.want {
+			t.Errorf("cooldownText(%+v): got %q, want %q", tt.cooldown, got, tt.want)
+		}
+	}
+}
+
+// countExec считает вызовы команды в тестах.
+type countExec struct{ calls *int }
+
+func (c countExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+	*c.calls++
+	return textResponse("ok", 0), nil
+}
+
+func TestCheckCooldownUsageError(t *testing.T) {
+	calls := 0
+	cmd := &Command{
+		Name:     "/roll",
+		Args:     cmdargs.Spec{cmdargs.Int("sides")},
+		Cooldown: Cooldown{PerUser: time.Minute},
+		Executor: countExec{calls: &calls},
+	}
+	s := newFakeStorage()
+	p := &Processor{tg: telegram.New("", "token", nil), storage: s, admins: newAdminCache(time.Hour)}
+	p.admins.Get(-100, func(chatID int) ([]telegram.User, error) { return nil, nil })
+	handler := checkCooldown(execCommand)
+	request := func(text string) *Request {
+		return &Request{
+			Text:    text,
+			Chat:    &telegram.Chat{ID: -100},
+			User:    &telegram.User{ID: 1, Username: "user"},
+			Locale:  ruLocale,
+			Command: cmd,
+		}
+	}
+
+	response, err := handler(p, request("/roll six"))
+	if err != nil || !response.usageError {
+		t.Fatalf("/roll six: got %+v, %v; want usage error", response, err)
+	}
+	if len(s.cooldowns) != 0 {
+		t.Errorf("cooldown saved after usage error: %+v", s.cooldowns[0])
+	}
+
+	if _, err := handler(p, request("/roll 6")); err != nil || calls != 1 {
+		t.Fatalf("/roll 6: calls %d, err %v", calls, err)
+	}
+	if _, err := handler(p, request("/roll 6")); err != nil || calls != 1 {
+		t.Errorf("/roll 6 within cooldown: calls %d, err %v; want 1 call", calls, err)
+	}
+}
+
+func TestCooldownNegativeDuration(t *testing.T) {
+	s := newFakeStorage()
+	p := &Processor{storage: s}
+	cmd := commandByName(CooldownCmd)
+	for _, text := range []string{"/cooldown /xkcd user -1m", "/cooldown /xkcd chat -30s"} {
+		params, err := cmd.parseArgs(text)
+		if err != nil {
+			t.Fatalf("%q: %v", text, err)
+		}
+		response, err := cooldownExec(CooldownCmd).Exec(p, text, params, &telegram.User{}, &telegram.Chat{ID: -100}, nil, 1)
+		if err != nil || !response.usageError {
+			t.Errorf("%q: got %+v, %v; want usage error", text, response, err)
+		}
+	}
+	if len(s.commandCooldowns) != 0 {
+		t.Errorf("negative cooldown saved: %+v", s.commandCooldowns[0])
+	}
+}
@@ -0,0 +1,151 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/storage"
+	"time"
+)
+
+// defaultDialogTimeout время, через которое незавершённый диалог сбрасывается.
+const defaultDialogTimeout = 10 * time.Minute
+
+// DialogStep шаг многошагового диалога: вопрос пользователю и проверка ответа.
+type DialogStep struct {
+	// Key ключ, под которым ответ сохраняется в результатах диалога.
+	Key string
+	// Prompt ключ вопроса, который бот задаёт перед шагом.
+	Prompt string
+	// Validate проверяет ответ, nil - подходит любой непустой ответ.
+	Validate func(answer string) bool
+	// Invalid ключ сообщения, если ответ не прошёл проверку.
+	Invalid string
+}
+
+// Dialog описание многошагового диалога, который команда может начать с пользователем.
+type Dialog struct {
+	Name    string
+	Steps   []DialogStep
+	Timeout time.Duration
+	// Finish вызывается после последнего шага и возвращает итоговое сообщение.
+	Finish func(p *Processor, chat *telegram.Chat, user *telegram.User, answers map[string]string) (string, error)
+}
+
+// allDialogs список всех диалогов бота по имени.
+var allDialogs = map[string]*Dialog{
+	addHomeworkDialog.Name: addHomeworkDialog,
+}
+
+// timeout возвращает время жизни диалога.
+func (d *Dialog) timeout() time.Duration {
+	if d.Timeout <= 0 {
+		return defaultDialogTimeout
+	}
+	return d.Timeout
+}
+
+// startDialog начинает диалог с пользователем и возвращает первый вопрос.
+// Незавершённый диалог пользователя в этом чате заменяется новым.
+func (p *Processor) startDialog(d *Dialog, chat *telegram.Chat, user *telegram.User) (string, error) {
+	state := &storage.DBDialogState{
+		ChatID:    chat.ID,
+		TgID:      user.ID,
+		Dialog:    d.Name,
+		Data:      "{}",
+		UpdatedAt: time.Now(),
+	}
+	if err := p.storage.SaveDialogState(context.Background(), state); err != nil {
+		return "", e.Wrap("can't start dialog "+d.Name, err)
+	}
+	return p.locale(chat.ID).Text(d.Steps[0].Prompt), nil
+}
+
+// continueDialog передаёт сообщение в незавершённый диалог пользователя.
+// Возвращает false, если диалога нет или он истёк и сообщение нужно обрабатывать дальше.
+func (p *Processor) continueDialog(text string, chat *telegram.Chat, user *telegram.User) (string, bool, error) {
+	state, err := p.storage.GetDialogState(context.Background(), chat.ID, user.ID)
+	if err == storage.ErrDialogNotExist {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, e.Wrap("can't get dialog state", err)
+	}
+
+	d, ok := allDialogs[state.Dialog]
+	if !ok || state.Step >= len(d.Steps) {
+		log.Printf("[ERROR] unknown dialog '%s' step %d, drop it", state.Dialog, state.Step)
+		return "", false, p.storage.DeleteDialogState(context.Background(), chat.ID, user.ID)
+	}
+
+	// брошенный диалог молча сбрасывается, а сообщение обрабатывается как обычное
+	if time.Since(state.UpdatedAt) > d.timeout() {
+		if err = p.storage.DeleteDialogState(context.Background(), chat.ID, user.ID); err != nil {
+			return "", false, e.Wrap("can't delete expired dialog", err)
+		}
+		return "", false, nil
+	}
+
+	lang := p.locale(chat.ID)
+
+	step := d.Steps[state.Step]
+	answer := strings.TrimSpace(text)
+	if answer == "" || (step.Validate != nil && !step.Validate(answer)) {
+		if step.Invalid != "" {
+			return lang.Text(step.Invalid) + "\n" + lang.Text(step.Prompt), true, nil
+		}
+		return lang.Text(step.Prompt), true, nil
+	}
+
+	answers := make(map[string]string)
+	if err = json.Unmarshal([]byte(state.Data), &answers); err != nil {
+		return "", false, e.Wrap("can't decode dialog answers", err)
+	}
+	answers[step.Key] = answer
+	state.Step++
+
+	if state.Step < len(d.Steps) {
+		data, err := json.Marshal(answers)
+		if err != nil {
+			return "", false, e.Wrap("can't encode dialog answers", err)
+		}
+		state.Data, state.UpdatedAt = string(data), time.Now()
+		if err = p.storage.SaveDialogState(context.Background(), state); err != nil {
+			return "", false, e.Wrap("can't save dialog state", err)
+		}
+		return lang.Text(d.Steps[state.Step].Prompt), true, nil
+	}
+
+	if err = p.storage.DeleteDialogState(context.Background(), chat.ID, user.ID); err != nil {
+		return "", false, e.Wrap("can't delete finished dialog", err)
+	}
+	message, err := d.Finish(p, chat, user, answers)
+	if err != nil {
+		return "", false, e.Wrap("can't finish dialog "+d.Name, err)
+	}
+	return message, true, nil
+}
+
+// cancelDialogExec предоставляет метод Exec для выполнения /cancel.
+type cancelDialogExec string
+
+// Exec: /cancel - отменяет незавершённый диалог пользователя.
+func (a cancelDialogExec) Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+	chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error) {
+
+	lang := p.locale(chat.ID)
+	message := lang.Text(msgDialogNothingToCancel)
+	_, err := p.storage.GetDialogState(context.Background(), chat.ID, user.ID)
+	if err == nil {
+		if err = p.storage.DeleteDialogState(context.Background(), chat.ID, user.ID); err != nil {
+			return nil, e.Wrap("can't cancel dialog", err)
+		}
+		message = lang.Text(msgHomeworkCanceled)
+	} else if err != storage.ErrDialogNotExist {
+		return nil, e.Wrap("can't get dialog state", err)
+	}
+	return textResponse(message, messageID), nil
+}
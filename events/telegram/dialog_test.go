@@ -0,0 +1,35 @@
+package telegram
+
+import (
+	"testing"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/storage"
+	"time"
+)
+
+func TestContinueDialogTimeout(t *testing.T) {
+	const chatID, userID = -100, 1
+	s := newFakeStorage()
+	s.users = []*storage.DBUser{{TgID: userID, ChatID: chatID, Username: "user", Active: true}}
+	s.replies = []*storage.DBAutoReply{{ID: 1, ChatID: chatID, Kind: autoReplyRegex, Pattern: ".", Reply: "эхо", Probability: 100}}
+	// пользователь бросил /add несколько часов назад
+	s.dialogs = []*storage.DBDialogState{{ChatID: chatID, TgID: userID, Dialog: addHomeworkDialog.Name, Data: "{}",
+		UpdatedAt: time.Now().Add(-3 * time.Hour)}}
+	p := &Processor{storage: s}
+
+	response, err := chain(execCommand, allMiddlewares...)(p, &Request{
+		Text:      "всем привет",
+		Chat:      &telegram.Chat{ID: chatID},
+		User:      &telegram.User{ID: userID, Username: "user"},
+		MessageID: 7,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := textResponse("эхо", 7); response == nil || response.actions[0] != want.actions[0] {
+		t.Errorf("after timeout: got %+v, want auto reply", response)
+	}
+	if len(s.dialogs) != 0 {
+		t.Errorf("expired dialog is not deleted: %+v", s.dialogs)
+	}
+}
@@ -0,0 +1,102 @@
+package telegram
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"runtime/debug"
+	"tg_ics_useful_bot/lib/i18n"
+	"tg_ics_useful_bot/storage"
+)
+
+// ErrForbidden команду вызвал пользователь без нужных прав.
+var ErrForbidden = errors.New("forbidden")
+
+// panicError паника при обработке события вместе со стеком, где она произошла.
+type panicError struct {
+	value any
+	stack []byte
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.value)
+}
+
+// safeCall выполняет f, превращая панику в *panicError.
+func safeCall(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &panicError{value: r, stack: debug.Stack()}
+		}
+	}()
+	return f()
+}
+
+// errorReplies ответы пользователю по классам ошибок, проверяются по порядку.
+// Для остальных ошибок отвечается msgErrorInternal с кодом ошибки.
+var errorReplies = []struct {
+	match   func(err error) bool
+	message string
+}{
+	{match: func(err error) bool { return errors.Is(err, ErrForbidden) }, message: msgErrorForbidden},
+	{match: func(err error) bool { return errors.Is(err, storage.ErrUserNotExist) }, message: msgErrorUserNotFound},
+	{match: isNetworkError, message: msgErrorUnavailable},
+}
+
+// isNetworkError показывает, что ошибка - недоступность Telegram или внешнего сервиса (календаря, xkcd).
+func isNetworkError(err error) bool {
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// errorMessage возвращает понятное пользователю сообщение об ошибке.
+func errorMessage(lang *i18n.Locale, err error, errorID string) string {
+	for _, r := range errorReplies {
+		if r.match(err) {
+			return lang.Text(r.message)
+		}
+	}
+	return lang.Text(msgErrorInternal, errorID)
+}
+
+// newErrorID возвращает короткий код ошибки, по которому её можно найти в логах.
+func newErrorID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "00000000"
+	}
+	return hex.EncodeToString(b)
+}
+
+// reportError возвращает код ошибки для лога, не сообщая о ней пользователю. Для паник в лог пишется стек.
+func reportError(err error) string {
+	errorID := newErrorID()
+	var pe *panicError
+	if errors.As(err, &pe) {
+		log.Printf("[PANIC] #%s %v\n%s", errorID, pe.value, pe.stack)
+	}
+	return errorID
+}
+
+// replyError сообщает пользователю об ошибке обработки события: уведомлением на нажатие кнопки,
+// если callbackID задан, иначе сообщением в чат. Для паник в лог пишется стек.
+// Возвращает код ошибки, который нужно указать в логе вместе с самой ошибкой.
+func (p *Processor) replyError(err error, chatID int, callbackID string) string {
+	errorID := reportError(err)
+	message := errorMessage(p.locale(chatID), err, errorID)
+	if callbackID != "" {
+		if answerErr := p.tg.AnswerCallbackQuery(callbackID, message); answerErr == nil {
+			return errorID
+		}
+	}
+	if sendErr := p.tg.SendMessage(chatID, message, "", 0); sendErr != nil {
+		log.Printf("[ERROR] #%s can't send error reply: %v", errorID, sendErr)
+	}
+	return errorID
+}
@@ -0,0 +1,97 @@
+package telegram
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/events"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/storage"
+)
+
+func TestSafeCall(t *testing.T) {
+	err := safeCall(func() error {
+		var s []string
+		_ = s[1]
+		return nil
+	})
+	var pe *panicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("safeCall: got %v, want panicError", err)
+	}
+	if !strings.Contains(string(pe.stack), "TestSafeCall") {
+		t.Errorf("stack does not contain the panicking function:\n%s", pe.stack)
+	}
+
+	want := errors.New("boom")
+	if err = safeCall(func() error { return want }); err != want {
+		t.Errorf("safeCall: got %v, want %v", err, want)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{e.Wrap("no admin", ErrForbidden), "Эта команда только для админов бота"},
+		{e.Wrap("not find user stats", storage.ErrUserNotExist), "Не нашёл вас в этом чате, попробуйте ещё раз"},
+		{e.Wrap("can't fetch", &url.Error{Op: "Get", URL: "https://xkcd.com", Err: errors.New("timeout")}), "Сервис временно недоступен, попробуйте позже"},
+		{&panicError{value: "index out of range"}, "Что-то пошло не так 😵 Код ошибки: deadbeef"},
+	}
+	for _, tt := range tests {
+		if got := errorMessage(ruLocale, tt.err, "deadbeef"); got != tt.want {
+			t.Errorf("errorMessage(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestProcessMessageErrorReply(t *testing.T) {
+	sent := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/sendMessage") {
+			sent++
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	defer server.Close()
+	defer func(transport http.RoundTripper) { http.DefaultTransport = transport }(http.DefaultTransport)
+	http.DefaultTransport = server.Client().Transport
+
+	p := &Processor{
+		tg:       telegram.New(server.Listener.Addr().String(), "token", nil),
+		storage:  newFakeStorage(),
+		username: "test_bot",
+		handler: func(p *Processor, req *Request) (*Response, error) {
+			return nil, errors.New("database is locked")
+		},
+	}
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"всем привет", 0},
+		{"/help@other_bot", 0},
+		{"/unknown", 0},
+		{"/help", 1},
+		{"/help@test_bot", 1},
+	}
+	for _, tt := range tests {
+		sent = 0
+		event := events.Event{
+			Type: events.Message,
+			Text: tt.text,
+			Meta: Meta{MessageID: 1, TgID: 1, ChatID: -100, ChatType: "supergroup"},
+		}
+		if err := p.processMessage(event); err == nil {
+			t.Errorf("%q: expected error", tt.text)
+		}
+		if sent != tt.want {
+			t.Errorf("%q: sent %d messages, want %d", tt.text, sent, tt.want)
+		}
+	}
+}
@@ -2,70 +2,26 @@ package telegram
 
 import (
 	"context"
-	"errors"
-	"fmt"
-	"log"
 	"strings"
 	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/cmdargs"
 	"tg_ics_useful_bot/lib/e"
 	"tg_ics_useful_bot/lib/utils"
 	"tg_ics_useful_bot/storage"
 )
 
-type method int
-
-const (
-	UnsupportedMethod method = iota
-	sendMessageMethod
-	sendPhotoMethod
-	sendMessageWithButtonsMethod
-	doNothingMethod
-)
-
 // CmdExecutor предоставляет интерфейс с методом Exec
-// для процедуры выполнения команды пользователя.
+// для процедуры выполнения команды пользователя. params - аргументы, разобранные по Command.Args.
 type CmdExecutor interface {
-	Exec(p *Processor, inMessage string, user *telegram.User, chat *telegram.Chat,
-		userStats *storage.DBUserStat, messageID int) (*Response, error)
+	Exec(p *Processor, inMessage string, params cmdargs.Values, user *telegram.User,
+		chat *telegram.Chat, userStats *storage.DBUserStat, messageID int) (*Response, error)
 }
 
-// Response структура содержащая поля для отправки сообщения пользователю.
-type Response struct {
-	message        string
-	method         method
-	parseMode      telegram.ParseMode
-	replyMessageId int
-}
-
-// allCommands список всех возможных команд бота.
-var allCommands = map[string]CmdExecutor{
-	AllCmd + suffix:                allUsernamesExec(AllCmd + suffix),
-	GayTopCmd + suffix:             topGaysExec(GayTopCmd + suffix),
-	GayStartCmd + suffix:           gayExec(GayStartCmd + suffix),
-	DickTopCmd + suffix:            dickTopExec(DickTopCmd + suffix),
-	DicStartCmd + suffix:           dickStartExec(DicStartCmd + suffix),
-	GetHPCmd + suffix:              getHpExec(GetHPCmd + suffix),
-	DickDuelCmd + suffix:           duelExec(DickDuelCmd + suffix),
-	HelpCmd + suffix:               helpExec(HelpCmd + suffix),
-	GetMyStatsCmd + suffix:         myStatsExec(GetMyStatsCmd + suffix),
-	GetChatStatsCmd + suffix:       chatStatsExec(GetChatStatsCmd + suffix),
-	ChangeDickCmd + suffix:         adminChangeDickExec(ChangeDickCmd + suffix),
-	SendMessageByAdminCmd + suffix: adminSendMessageExec(SendMessageByAdminCmd + suffix),
-	AddCalendarIDCmd + suffix:      addCalendarExec(AddCalendarIDCmd + suffix),
-	ScheduleCmd + suffix:           scheduleExec(ScheduleCmd + suffix),
-	XkcdCmd + suffix:               xkcdExec(XkcdCmd + suffix),
-	AnecdotCmd + suffix:            anekdotExec(AnecdotCmd + suffix),
-	FlipCmd + suffix:               flipExec(FlipCmd + suffix),
-	GetChatIDCmd + suffix:          chatIDExec(GetChatIDCmd + suffix),
-
-	AddHomeworkCmd + suffix:    addHomeworkExec(AddHomeworkCmd + suffix),
-	GetHomeworkCmd + suffix:    getHomeworkExec(GetHomeworkCmd + suffix),
-	DeleteHomeworkCmd + suffix: deleteHomeworkExec(DeleteHomeworkCmd + suffix),
-
-	StartAuctionCmd + suffix:  startAuctionExec(StartAuctionCmd + suffix),
-	FinishAuctionCmd + suffix: finishAuctionExec(FinishAuctionCmd + suffix),
-	AddDepositCmd + suffix:    addDepositExec(AddDepositCmd + suffix),
-	AuctionCmd + suffix:       auctionExec(AuctionCmd + suffix),
+// DocumentExecutor реализуется командами, которые принимают файл,
+// отправленный вместе с командой в подписи.
+type DocumentExecutor interface {
+	ExecDocument(p *Processor, inMessage string, document *telegram.Document, user *telegram.User,
+		chat *telegram.Chat, messageID int) (*Response, error)
 }
 
 const (
@@ -73,114 +29,73 @@ const (
 	DEFAULT_HP_USER       = 3
 )
 
-// doCmd выбирает необходимую логику для выолнения команды.
-func (p *Processor) doCmd(text string, chat *telegram.Chat, user *telegram.User, messageID int) error {
-	dbUser, err := p.storage.GetUser(context.Background(), user.ID, chat.ID) // TODO: добавить cache для dbUser
-	if err == storage.ErrUserNotExist {
-		dbUser, err = p.createNewUserInDB(chat.ID, user)
-		if err != nil {
-			return err
-		}
-	} else if err != nil {
-		return err
-	}
-
-	dbUser, err = p.userChangeInfo(user, dbUser)
-	if err != nil {
-		return e.Wrap("can't update user info in 'doCmd'", err)
-	}
-
-	userStats, err := p.storage.GetUserStats(context.Background(), dbUser)
-	if err == storage.ErrUserNotExist {
-		return e.Wrap("not find user stats", err)
-	} else if err != nil {
-		return e.Wrap("not user stats", err)
+// doCmd пропускает сообщение через цепочку allMiddlewares и выполняет ответ.
+// document - файл, отправленный вместе с командой, nil если его нет.
+func (p *Processor) doCmd(text string, chat *telegram.Chat, user *telegram.User, messageID int,
+	document *telegram.Document) error {
+	req := &Request{
+		Text:      strings.TrimSpace(text),
+		Chat:      chat,
+		User:      user,
+		MessageID: messageID,
+		Document:  document,
 	}
-
-	userStats.MessageCount++
-	err = p.storage.UpdateUserStats(context.Background(), userStats)
-	if err != nil {
-		log.Print(err)
-	}
-
-	text, parseMode := strings.TrimSpace(text), telegram.ParseMode("")
-
-	switch utils.CheckYesOrNo(text) {
-	case utils.IsYesCommand:
-
-		userStats.YesCount++
-		err = p.storage.UpdateUserStats(context.Background(), userStats)
-		if err != nil {
-			log.Print(err)
-		}
-		return p.tg.SendMessage(chat.ID, "Пизда", parseMode, messageID)
-	case utils.IsNoCommand:
-
-		userStats.NoCount++
-		err = p.storage.UpdateUserStats(context.Background(), userStats)
-		if err != nil {
-			log.Print(err)
-		}
-		return p.tg.SendMessage(chat.ID, "Пидора ответ", parseMode, messageID)
-	}
-
-	userWithChat := UserWithChat{chat.ID, user.ID}
-
-	if _, ok := stateHomework[userWithChat]; ok {
-		msg := p.addHomeworkCmd(text, userWithChat)
-		replyToMessageID := messageID
-		return p.tg.SendMessage(chat.ID, msg, parseMode, replyToMessageID)
+	response, err := p.handler(p, req)
+	if err != nil || response == nil {
+		return err
 	}
+	return p.doActions(response, &actionTarget{chatID: chat.ID, messageID: messageID})
+}
 
-	if utils.IsCommand(text) {
-		log.Printf("[INFO] got new command '%s' from '%s' in '%s'", text, user.Username, chat.Title)
-
-		strCmd := strings.Split(text, " ")[0]
-		cmd := p.getCmd(strCmd)
-		if cmd == nil {
-			return e.Wrap(fmt.Sprintf("can't get command from %s", strCmd), err)
-		}
-
-		response, err := cmd.Exec(p, text, user, chat, userStats, messageID)
-		if err != nil {
-			return e.Wrap(fmt.Sprintf("can't select command from message: %s", text), err)
-		}
+// sendMessage отправляет сообщение, разбивая слишком длинный текст на несколько сообщений.
+// Клавиатура прикрепляется к последнему из них.
+func (p *Processor) sendMessage(chatID int, text string, parseMode telegram.ParseMode, replyToMessageID int,
+	markup *telegram.InlineKeyboardMarkup) error {
+	_, err := p.sendLongMessage(chatID, text, parseMode, replyToMessageID, markup)
+	return err
+}
 
-		msg, mthd, parseMode, replyToMessageID := response.message, response.method, response.parseMode, response.replyMessageId
-		if replyToMessageID <= 0 {
-			err = p.tg.DeleteMessage(chat.ID, messageID)
-			if err != nil {
-				return err
-			}
+// sendLongMessage как sendMessage, но возвращает последнее отправленное сообщение.
+func (p *Processor) sendLongMessage(chatID int, text string, parseMode telegram.ParseMode, replyToMessageID int,
+	markup *telegram.InlineKeyboardMarkup) (*telegram.IncomingMessage, error) {
+	var sent *telegram.IncomingMessage
+	parts := utils.SplitText(text, telegram.MaxMessageLength)
+	for i, part := range parts {
+		var partMarkup *telegram.InlineKeyboardMarkup
+		if i == len(parts)-1 {
+			partMarkup = markup
 		}
-
-		switch mthd {
-		case UnsupportedMethod:
-			return e.Wrap("unsupported method:", errors.New("unknown method"))
-		case sendMessageMethod:
-			return p.tg.SendMessage(chat.ID, msg, parseMode, replyToMessageID)
-		case sendPhotoMethod:
-			return p.tg.SendPhoto(chat.ID, msg)
-		case sendMessageWithButtonsMethod:
-			return p.tg.SendMessage(chat.ID, msg, parseMode, replyToMessageID)
-		case doNothingMethod:
-			log.Printf("Message: \"%s\" - do nothing", text)
+		var err error
+		if sent, err = p.tg.SendMessageWithButtons(chatID, part, parseMode, replyToMessageID, partMarkup); err != nil {
+			return nil, err
 		}
 	}
+	return sent, nil
+}
 
-	return nil
+// getCmd возвращает команду, если она существует: /cmd или /cmd@имя_бота.
+func (p *Processor) getCmd(strCmd string) *Command {
+	name, _, _ := strings.Cut(strCmd, "@")
+	return commandByName(name)
 }
 
-// getCmd возвращает executor для команды, если она существует.
-func (p *Processor) getCmd(strCmd string) CmdExecutor {
-	if !strings.Contains(strCmd, "@") {
-		strCmd += suffix
+// messageCommand возвращает команду этому боту из текста сообщения, nil - сообщение не команда этому боту.
+func (p *Processor) messageCommand(text string) *Command {
+	text = strings.TrimSpace(text)
+	if !utils.IsCommand(text) {
+		return nil
 	}
-	cmd, ok := allCommands[strCmd]
-	if !ok {
+	strCmd := strings.Split(text, " ")[0]
+	if !p.addressedToMe(strCmd) {
 		return nil
 	}
-	return cmd
+	return p.getCmd(strCmd)
+}
+
+// addressedToMe показывает, адресована ли команда этому боту: без имени бота или с его именем.
+func (p *Processor) addressedToMe(strCmd string) bool {
+	_, bot, addressed := strings.Cut(strCmd, "@")
+	return !addressed || strings.EqualFold(bot, p.username)
 }
 
 // createNewUserInDB создаёт пользователя в базе данных, если он там ещё не существует.
@@ -0,0 +1,45 @@
+package telegram
+
+import (
+	"errors"
+	"log"
+	"time"
+)
+
+// job фоновая задача бота, которая выполняется раз в interval.
+type job struct {
+	name     string
+	interval time.Duration
+	run      func(p *Processor, now time.Time) error
+}
+
+// allJobs список всех фоновых задач бота.
+var allJobs = []job{
+	{name: "homework digest", interval: time.Minute, run: (*Processor).sendDigests},
+	{name: "schedule notify", interval: time.Minute, run: (*Processor).sendScheduleNotifies},
+	{name: "schedule changes", interval: 15 * time.Minute, run: (*Processor).checkScheduleChanges},
+	{name: "expired cooldowns", interval: time.Hour, run: (*Processor).deleteExpiredCooldowns},
+}
+
+// RunJobs запускает все фоновые задачи бота в отдельных горутинах.
+func (p *Processor) RunJobs() {
+	for _, j := range allJobs {
+		go p.runJob(j)
+	}
+}
+
+// runJob выполняет задачу по тикеру, ошибки и паники только логируются.
+func (p *Processor) runJob(j job) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		err := safeCall(func() error { return j.run(p, now) })
+		var pe *panicError
+		if errors.As(err, &pe) {
+			log.Printf("[PANIC] job '%s': %v\n%s", j.name, pe.value, pe.stack)
+		} else if err != nil {
+			log.Printf("[ERROR] job '%s': %v", j.name, err)
+		}
+	}
+}
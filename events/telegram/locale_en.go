@@ -0,0 +1,319 @@
+package telegram
+
+import "tg_ics_useful_bot/lib/i18n"
+
+// enLocale английский перевод сообщений бота.
+var enLocale = &i18n.Locale{
+	Code: "en",
+	Name: "English",
+	Rule: i18n.EnglishPlural,
+	Messages: i18n.Messages{
+		// HELP
+		msgHelpHeader:               {"*Available commands:*\n"},
+		msgHelpFooter:               {"\nMore about a command: /help {command}"},
+		msgHelpChatAdmin:            {" (_set up by group admins_)"},
+		msgHelpChatAdminDetails:     {"_Only group admins can change the settings_"},
+		msgHelpChatAdminOnly:        {" (_group admins only_)"},
+		msgHelpChatAdminOnlyDetails: {"_Group admins only_"},
+		msgHelpRole:                 {"Also available to members with the %s role"},
+		msgHelpBotAdminHeader:       {"\n*Bot admin commands:*\n"},
+		msgHelpBotAdminDetails:      {"_Bot admins only_"},
+		msgHelpAliases:              {"Also known as: %s"},
+		msgUnknownHelpCommand:       {"Unknown command %s\nCommand list: /help"},
+
+		msgCommandUsage:    {"Usage: %s"},
+		msgMissingArg:      {"Missing argument %s"},
+		msgInvalidArg:      {"\"%s\" is not valid for %s: expected %s"},
+		msgInvalidValue:    {"\"%s\" is not valid for %s"},
+		msgTooManyArgs:     {"Unexpected arguments: %s"},
+		msgArgChoice:       {"one of: %s"},
+		msgArgKindInt:      {"an integer"},
+		msgArgKindDuration: {"a duration, e.g. 10m or 1h30m"},
+		msgArgKindUsername: {"a username"},
+		msgArgKindMention:  {"a @username mention"},
+
+		// ERRORS
+		msgErrorInternal:  {"Something went wrong 😵 Error code: %s"},
+		msgErrorForbidden: {"This command is for bot admins only"},
+
+		msgForbiddenChange:   {"Only group admins can change %s settings"},
+		msgForbiddenCommand:  {"%s is for group admins only"},
+		msgForbiddenRole:     {" and members with the %s role"},
+		msgErrorUserNotFound: {"Couldn't find you in this chat, please try again"},
+		msgErrorUnavailable:  {"The service is temporarily unavailable, please try later"},
+
+		msgCreateUser:     {"@%s has just discovered their dick 🤣\n"},
+		msgAlreadyPlays:   {"@%s, you are out of tries for today 🚨\n"},
+		msgDickSize:       {"Their dick is now %d cm 🍌"},
+		msgDickIncrease:   {"@%s, your dick grew by %d cm 😍\n"},
+		msgDickDecrease:   {"@%s, your dick shrank by %d cm 😭\n"},
+		msgChangeDickSize: {"@%s, %d ➜ %d cm 🍌\n"},
+		msgDickTopLeader:  {"👑 *%s* — _%d cm_\n"},
+		msgDickTopLine:    {"%d. %s — %d cm\n"},
+
+		msgTargetNotFound: {"@%s this user has no dick 🍆"},
+		msgVictoryInDuel:  {"@%s won the duel against @%s\n"},
+		msgUserHasBanned:  {"@%s is banned for %d second 🚫\n", "@%s is banned for %d seconds 🚫\n"},
+
+		msgChanceDuel: {"@%s has %d cm and a %.2f%% chance to win\n@%s has %d cm and a %.2f%% chance to win\n"},
+
+		msgNewGayOfDay:     {"New clown of the day - @%s"},
+		msgCurrentGayOfDay: {"Current clown of the day - @%s"},
+		msgGayRatingHeader: {"Clown rating: \n\n"},
+		msgGayRatingLine:   {"%d. %s %s %d time \n", "%d. %s %s %d times \n"},
+
+		msgCalendarNotExists:        {"No calendar is linked to your group\nTo link a Google Calendar or an .ics calendar use /add_calendar"},
+		msgErrorSendMessage:         {"Couldn't get the schedule from the calendar"},
+		msgChatTimezone:             {"Chat time zone: %s (now %s)"},
+		msgWrongTimezone:            {"Unknown time zone \"%s\", example: Europe/London"},
+		msgNoLessons:                {"No classes 🎉"},
+		msgNoNextLesson:             {"No classes in the next two weeks 🎉"},
+		msgNextLesson:               {"Next class: %s\n%s, in %s"},
+		msgDurationDays:             {"%d day", "%d days"},
+		msgDurationHours:            {"%d h"},
+		msgDurationMinutes:          {"%d min"},
+		msgDurationSeconds:          {"%d sec"},
+		msgScheduleChanged:          {"📅 Schedule changes:\n"},
+		msgLessonAdded:              {"➕ %s, %s - new class\n"},
+		msgLessonCancelled:          {"❌ %s, %s cancelled\n"},
+		msgLessonMoved:              {"🔁 %s: %s ➜ %s\n"},
+		msgScheduleNotifyHeader:     {"☀️ Today's schedule:\n"},
+		msgScheduleNotifySettings:   {"The daily schedule is sent at %s%s"},
+		msgScheduleNotifyRemind:     {", reminder %d min before each class"},
+		msgScheduleNotifyDisabled:   {"Schedule notifications are off"},
+		msgLessonReminder:           {"⏰ In %s: %s (%s)"},
+		msgWeekParity:               {"Week: %s\n"},
+		msgWeekParityNotSet:         {"Odd and even weeks are the same in the schedule\nSet the current week type: /week odd"},
+		msgErrorUpdateCalendarID:    {"Couldn't add the calendar: \"%s\""},
+		msgICSFileTooBig:            {"The calendar file is too big"},
+		msgSuccessUpdateICSCalendar: {"The schedule from the .ics calendar is now linked to your chat"},
+		msgSuccessUpdateCalendarID:  {"A new schedule is now linked to your chat\nDon't forget to share your calendar with:\ncalendar-manager@flash-spark-404006.iam.gserviceaccount.com\n"},
+
+		msgSuccessAdminChangeDickSize: {"Done"},
+		msgErrorAdminChangeDickSize:   {"Couldn't change this user's dick size"},
+
+		msgHomeworkCanceled:       {"Houston, we have a cancellation!"},
+		msgHomeworkWithoutSubject: {"Please put the subject after the command:\n/add_homework #InfoSecurity Lab 7..."},
+		msgHomeworkWithoutData:    {"Please put the task after the command and the subject:\n/add_homework #PhysicalEducation Run 100 km over the weekend"},
+		msgHomeworkSuccessAdded:   {"Homework: %s - %s\n Added"},
+
+		msgDialogNothingToCancel: {"Nothing to cancel"},
+		msgYesReply:              {"Yes-yes, say no more 😏"},
+		msgNoReply:               {"No means no 🙅"},
+
+		msgUserStats: {"Messages in this chat: %d\nDick growths: %d\nDick shrinks: %d\n\"Yes\" replies: %d\n\"No\" replies: %d\n" +
+			"Total duels: %d\nDuels won: %d\nDuels lost: %d\nKills in duels: %d\nDeaths in duels: %d\n"},
+
+		// DUEL
+		msgDuelWithYourself: {"@%s challenged themselves and lost 🍆"},
+
+		msgChallengeToDuel: {"@%s challenges @%s to a duel"},
+
+		msgAcceptDuel: {"@%s %s %d cm 🍌 %.2f%%\n⚔️\n@%s %s %d cm 🍌 %.2f%%\n"},
+
+		msgFinishDuel: {"\n\n🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️\n\n🏆 @%s %s %d cm ➕ %d \n🤕@%s %s %d cm ➖ %d"},
+
+		msgPlayerDie: {"\n\nεつ▄█▀█ ●\n\n🏆 @%s %s %d cm ➕ %d \n🤕@%s %s %d cm ➖ %d"},
+
+		msgCantCreateDuel: {"Can't start a duel between @%s and @%s\nThe duelists don't have enough HP"},
+
+		// HP
+		msgCantGetHP: {"@%s %s - your HP."},
+		msgGetHp:     {"@%s restored HP 🥰\nCurrent health  %s"},
+
+		// HOMEWORK
+		msgAddSubject:       {"Enter the subject"},
+		msgAddTask:          {"Enter the task"},
+		msgSuccessDelete:    {"Entry #%d deleted"},
+		msgErrorDelete:      {"Couldn't delete entry #%d"},
+		msgErrorAddHomework: {"Couldn't add the task"},
+
+		msgAddDeadline:    {"Enter the deadline as DD.MM.YYYY [HH:MM] or \"-\" if there is none"},
+		msgWrongDeadline:  {"Couldn't parse the date"},
+		msgExportHomework: {"Chat homework (only tasks with a deadline go to ics)"},
+
+		msgExportTitle:    {"Homework"},
+		msgExportSubject:  {"Subject"},
+		msgExportTask:     {"Task"},
+		msgExportCreated:  {"Added"},
+		msgExportDeadline: {"Deadline"},
+
+		msgDigestHeader:   {"🗓 Homework for the week:\n"},
+		msgDigestSettings: {"Homework digest: %s, %s%s"},
+		msgDigestPinned:   {", pinned"},
+		msgDigestDisabled: {"The weekly homework digest is off"},
+
+		msgHomeworkEmpty:       {"No homework yet"},
+		msgHomeworkPage:        {"Homework (page %d of %d):\n"},
+		msgHomeworkSubjectPage: {"Homework in %s (page %d of %d):\n"},
+
+		// auction
+		msgStartAuction: {`Dear friends!
+Please welcome: the *auction* is starting!
+
+To take part, put a piece of your dick at stake and raise your chances to win the auction!
+Maximum bet: %d cm.
+
+_Command to join:_
+/deposit _{amount}_ - amount is required! And it can't be larger than your dick!
+
+*GOOD LUCK!!!*`},
+		msgAuctionIsStarted:     {"An auction is already running in this chat!"},
+		msgErrorDeposit:         {"That much of your dick won't fit into the auction..."},
+		msgAuctionNotStarted:    {"No auction is running yet."},
+		msgSuccessDeposit:       {"You put %d cm of your dick into the auction!"},
+		msgNotEnoughPlayers:     {"Alas, nobody took part in the auction..."},
+		msgZeroPlayers:          {"Nobody takes part in the auction yet\nCommand to join:\n/deposit {amount} - amount is required! And it can't be larger than your dick!"},
+		msgAuctionCountdown:     {"Auction results in: %d!"},
+		msgAuctionPlayersHeader: {"Current auction players:\n\n"},
+		msgAuctionFund:          {"\nCurrent fund *%d cm*!"},
+
+		msgWinner: {"@%s wins the auction!\nAnd adds %d cm to their dick!"},
+
+		// ROLES
+		msgRolesHeader:    {"Roles in this chat:\n"},
+		msgRoleLine:       {"%s - %s: %s\n"},
+		msgNoRoles:        {"No roles granted yet\n"},
+		msgRolesFooter:    {"\nGrant a role: /grant @username role"},
+		msgRoleGranted:    {"@%s is now %s"},
+		msgRoleRevoked:    {"@%s is no longer %s"},
+		msgRoleAuctioneer: {"starts auctions"},
+
+		msgRoleUserNotFound: {"@%s hasn't written anything in this chat yet"},
+		msgRoleNotAssigned:  {"@%s is not %s"},
+
+		// COOLDOWNS
+		msgCooldownWait:    {"@%s, try again in %d second ⏳", "@%s, try again in %d seconds ⏳"},
+		msgCooldownPerUser: {"once per %s per member"},
+		msgCooldownPerChat: {"once per %s per chat"},
+		msgCooldownNone:    {"no limits"},
+		msgCommandCooldown: {"%s - %s"},
+		msgCooldownsHeader: {"Command limits in this chat:\n"},
+		msgNoCooldowns:     {"Commands can be used without limits in this chat"},
+
+		// MEMBERS
+		msgWelcome:           {"Hi, {name}! Welcome to «{chat}» 👋\nWhat the bot can do: /help"},
+		msgGoodbye:           {"{name} has left the chat. Bye! 👋"},
+		msgWelcomeCurrent:    {"Welcome message for new members ({name} - the member, {chat} - the chat title):\n\n%s"},
+		msgGoodbyeCurrent:    {"Goodbye message for members who left ({name} - the member, {chat} - the chat title):\n\n%s"},
+		msgGreetingsDisabled: {"\n\nGreetings are turned off in /settings"},
+
+		// AUTO REPLIES
+		msgAutoRepliesHeader:       {"Auto replies in this chat:\n"},
+		msgAutoReplyLine:           {"#%d %s «%s» → «%s», chance %d%%, %s\n"},
+		msgAutoReplyCooldown:       {"at most once per %s"},
+		msgNoAutoReplies:           {"No custom auto replies yet\n"},
+		msgAutoRepliesFooter:       {"\nReplies to \"да\" and \"нет\" always work, to turn off all auto replies: /settings"},
+		msgAutoReplyAdded:          {"Auto reply added:\n"},
+		msgAutoReplyRemoved:        {"Auto reply #%d removed"},
+		msgAutoReplyNotFound:       {"There is no auto reply #%d in this chat"},
+		msgAutoReplyBadPattern:     {"Bad pattern «%s»: regex needs a regular expression, rhyme needs letters only"},
+		msgAutoReplyBadProbability: {"The chance must be a number from 1 to 100"},
+
+		// SETTINGS
+		msgSettings:          {"Chat settings ⚙️\nTap a button to toggle a setting"},
+		msgSettingsForbidden: {"Only group admins can change the settings"},
+		msgGamesDisabled:     {"Games are off in this chat, turn them on: /settings"},
+		msgSettingGames:      {"Games"},
+		msgSettingReplies:    {"Auto replies"},
+		msgSettingDelete:     {"Delete commands"},
+		msgSettingLanguage:   {"Language"},
+		msgSettingGreetings:  {"Greetings"},
+
+		// DATES
+		msgMonday:    {"Monday"},
+		msgTuesday:   {"Tuesday"},
+		msgWednesday: {"Wednesday"},
+		msgThursday:  {"Thursday"},
+		msgFriday:    {"Friday"},
+		msgSaturday:  {"Saturday"},
+		msgSunday:    {"Sunday"},
+
+		msgMondayShort:    {"Mon"},
+		msgTuesdayShort:   {"Tue"},
+		msgWednesdayShort: {"Wed"},
+		msgThursdayShort:  {"Thu"},
+		msgFridayShort:    {"Fri"},
+		msgSaturdayShort:  {"Sat"},
+		msgSundayShort:    {"Sun"},
+
+		msgWeekNumerator:   {"odd"},
+		msgWeekDenominator: {"even"},
+
+		msgDeadline: {" (due %s)"},
+
+		// COMMANDS
+		msgCategoryHomework: {"📖 Homework"},
+		msgCategorySchedule: {"📅 Schedule"},
+		msgCategoryGames:    {"🍌 Games"},
+		msgCategoryAuction:  {"💰 Auction"},
+		msgCategoryFun:      {"🎲 Fun"},
+		msgCategoryChat:     {"⚙️ Chat"},
+
+		msgCmdAddHomework:           {"add homework 📖"},
+		msgCmdAddHomeworkDetails:    {"The bot will ask for the subject, the task and the deadline one by one. Cancel: /cancel"},
+		msgCmdGetHomework:           {"latest homework with page buttons"},
+		msgCmdGetHomeworkDetails:    {"number - entries per page (5 by default), subject - subject name"},
+		msgCmdDeleteHomework:        {"delete an entry by id"},
+		msgCmdCancelDialog:          {"cancel adding homework or another started dialog"},
+		msgCmdExportHomework:        {"export all homework as a file"},
+		msgCmdExportHomeworkDetails: {"csv by default. Only homework with a deadline goes to ics"},
+		msgCmdDigestUsage:           {"[day HH:MM [pin] | off]"},
+		msgCmdDigest:                {"weekly homework digest"},
+		msgCmdDigestDetails:         {"Without parameters shows the current settings, pin - pin the digest.\nExample: /digest fri 18:00 pin"},
+		msgCmdScheduleUsage:         {"[image] [today|tomorrow|week|DD.MM|day]"},
+		msgCmdSchedule:              {"class schedule, with image - as a picture"},
+		msgCmdScheduleDetails:       {"For the current week by default. Works only if a calendar is linked to the group"},
+		msgCmdNextLesson:            {"next class and how long until it starts"},
+		msgCmdWeekParityUsage:       {"[odd|even]"},
+		msgCmdWeekParity:            {"whether the current week is odd or even"},
+		msgCmdWeekParityDetails:     {"With a parameter sets the type of the current week, classes marked with the other week (e.g. «(знам)» in the name) are hidden"},
+		msgCmdScheduleNotifyUsage:   {"[HH:MM [remind N] | off]"},
+		msgCmdScheduleNotify:        {"send the day's schedule every morning and remind about classes"},
+		msgCmdScheduleNotifyDetails: {"remind N - remind about each class N minutes before, days without classes are skipped.\nExample: /schedule\\_notify 07:30 remind 10"},
+		msgCmdAddCalendarIDUsage:    {"{calendar-id|link}"},
+		msgCmdAddCalendarID:         {"link a schedule from Google Calendar or an .ics calendar by URL"},
+		msgCmdAddCalendarIDDetails:  {"You can also send an .ics file with the command in the caption.\n*IMPORTANT* - _for Google Calendar, don't forget to share the calendar with: calendar-manager@flash-spark-404006.iam.gserviceaccount.com_"},
+		msgCmdDicStart:              {"find out everything about your 🍌"},
+		msgCmdDickTop:               {"stats of all 🍆"},
+		msgCmdDickDuel:              {"challenge to a duel or accept a challenge ⚔️"},
+		msgCmdGetHP:                 {"restore health for duels, once a day ❤️"},
+		msgCmdGayStart:              {"find out who is lucky today 🤡"},
+		msgCmdGayStartDetails:       {"Chosen among the chat admins"},
+		msgCmdGayTop:                {"stats of the unlucky ones in the chat 🔞"},
+		msgCmdStartAuction:          {"start an auction"},
+		msgCmdAddDeposit:            {"place a bet in the running auction"},
+		msgCmdAddDepositDetails:     {"amount - how many cm to bet, no more than the maximum bet and your size"},
+		msgCmdAuction:               {"participants of the current auction"},
+		msgCmdFinishAuction:         {"finish the auction"},
+		msgCmdFlip:                  {"flip a coin 🪙"},
+		msgCmdXkcd:                  {"random xkcd comic 😂"},
+		msgCmdAnecdot:               {"random joke from @bobuk"},
+		msgCmdHelp:                  {"commands help"},
+		msgCmdAll:                   {"call all chat admins"},
+		msgCmdGetMyStats:            {"your stats in this chat"},
+		msgCmdGetChatStats:          {"chat stats"},
+		msgCmdGetChatID:             {"id of this chat"},
+		msgCmdTimezone:              {"chat time zone for the schedule and games"},
+		msgCmdTimezoneDetails:       {"Europe/Moscow by default"},
+		msgCmdSettings:              {"chat settings: games, auto replies, command deletion, greetings, language"},
+		msgCmdSettingsDetails:       {"Only group admins can toggle the settings"},
+		msgCmdCooldown:              {"how often commands can be used in the chat"},
+		msgCmdCooldownDetails:       {"user - limit per member, chat - for the whole chat, 0 - no limit, reset - back to default.\nExample: /cooldown /xkcd user 1m\nGroup admins are not limited"},
+		msgCmdAutoReplyUsage:        {"[add {regex|rhyme} {pattern} [probability] [cooldown] {reply} | remove {id}]"},
+		msgCmdAutoReply:             {"custom auto replies of the chat"},
+		msgCmdAutoReplyDetails:      {"regex - reply to messages matching the regular expression, rhyme - to messages whose last word ends with pattern. probability - reply chance in percent (100 by default), cooldown - how often the rule may reply. Quote a pattern or reply with spaces.\nExample: /autoreply add rhyme ой 50 10m \"Ой, всё\"\nReplies to \"да\" and \"нет\" always work, turn off all auto replies: /settings"},
+		msgCmdWelcomeUsage:          {"[text | reset]"},
+		msgCmdWelcome:               {"greeting for new members"},
+		msgCmdWelcomeDetails:        {"{name} is replaced with the member, {chat} - with the chat title, reset - default text. Turn off: /settings"},
+		msgCmdGoodbyeUsage:          {"[text | reset]"},
+		msgCmdGoodbye:               {"farewell to members who left"},
+		msgCmdGoodbyeDetails:        {"{name} is replaced with the member, {chat} - with the chat title, reset - default text. Turn off: /settings"},
+		msgCmdRoles:                 {"roles of chat members"},
+		msgCmdGrantRole:             {"grant a role to a member"},
+		msgCmdGrantRoleDetails:      {"A role gives access to commands otherwise available only to group admins. Roles: /roles"},
+		msgCmdRevokeRole:            {"revoke a role from a member"},
+		msgCmdChangeDick:            {"change a user's size"},
+		msgCmdSendMessageByAdmin:    {"send a message as the bot"},
+	},
+}
@@ -0,0 +1,319 @@
+package telegram
+
+import "tg_ics_useful_bot/lib/i18n"
+
+// ruLocale оригинальные сообщения бота.
+var ruLocale = &i18n.Locale{
+	Code: "ru",
+	Name: "Русский",
+	Rule: i18n.RussianPlural,
+	Messages: i18n.Messages{
+		// HELP
+		msgHelpHeader:               {"*Доступные команды:*\n"},
+		msgHelpFooter:               {"\nПодробнее о команде: /help {команда}"},
+		msgHelpChatAdmin:            {" (_настраивают админы группы_)"},
+		msgHelpChatAdminDetails:     {"_Изменять настройки могут только админы группы_"},
+		msgHelpChatAdminOnly:        {" (_только админы группы_)"},
+		msgHelpChatAdminOnlyDetails: {"_Только для админов группы_"},
+		msgHelpRole:                 {"Также доступна участникам с ролью %s"},
+		msgHelpBotAdminHeader:       {"\n*Команды админов бота:*\n"},
+		msgHelpBotAdminDetails:      {"_Только для админов бота_"},
+		msgHelpAliases:              {"Другие названия: %s"},
+		msgUnknownHelpCommand:       {"Неизвестная команда %s\nСписок команд: /help"},
+
+		msgCommandUsage:    {"Формат: %s"},
+		msgMissingArg:      {"Не указан аргумент %s"},
+		msgInvalidArg:      {"\"%s\" не подходит для %s: нужно %s"},
+		msgInvalidValue:    {"\"%s\" не подходит для %s"},
+		msgTooManyArgs:     {"Лишние аргументы: %s"},
+		msgArgChoice:       {"одно из: %s"},
+		msgArgKindInt:      {"целое число"},
+		msgArgKindDuration: {"длительность, например 10m или 1h30m"},
+		msgArgKindUsername: {"имя пользователя"},
+		msgArgKindMention:  {"упоминание @username"},
+
+		// ERRORS
+		msgErrorInternal:  {"Что-то пошло не так 😵 Код ошибки: %s"},
+		msgErrorForbidden: {"Эта команда только для админов бота"},
+
+		msgForbiddenChange:   {"Изменить настройки %s может только администратор группы"},
+		msgForbiddenCommand:  {"Команда %s только для администраторов группы"},
+		msgForbiddenRole:     {" и участников с ролью %s"},
+		msgErrorUserNotFound: {"Не нашёл вас в этом чате, попробуйте ещё раз"},
+		msgErrorUnavailable:  {"Сервис временно недоступен, попробуйте позже"},
+
+		msgCreateUser:     {"@%s, только что обнаружил(а) свой пенис 🤣\n"},
+		msgAlreadyPlays:   {"@%s, сегодня все твои попытки закончились 🚨\n"},
+		msgDickSize:       {"Теперь размер его пениса: %d см 🍌"},
+		msgDickIncrease:   {"@%s, твой пенис увеличился на %d см 😍\n"},
+		msgDickDecrease:   {"@%s, твой пенис уменьшился на %d см 😭\n"},
+		msgChangeDickSize: {"@%s, %d ➜ %d см 🍌\n"},
+		msgDickTopLeader:  {"👑 *%s* — _%d см_\n"},
+		msgDickTopLine:    {"%d. %s — %d см\n"},
+
+		msgTargetNotFound: {"@%s этот пользователь не имеет писюна 🍆"},
+		msgVictoryInDuel:  {"@%s победил в дуели @%s\n"},
+		msgUserHasBanned:  {"@%s получает бан на %d секунду 🚫\n", "@%s получает бан на %d секунды 🚫\n", "@%s получает бан на %d секунд 🚫\n"},
+
+		msgChanceDuel: {"@%s имеет пенис %d см и шансы на победу %.2f%%\n@%s имеет пенис %d см и шансы на победу %.2f%%\n"},
+
+		msgNewGayOfDay:     {"Новый пидор дня - @%s"},
+		msgCurrentGayOfDay: {"Текущий пидор дня - @%s"},
+		msgGayRatingHeader: {"Рейтинг пидоров: \n\n"},
+		msgGayRatingLine:   {"%d. %s %s %d раз \n"},
+
+		msgCalendarNotExists:        {"К вашей группе не привязан календарь\nЧтобы привязать Google Calendar или .ics календарь воспользуйтесь командой /add_calendar"},
+		msgErrorSendMessage:         {"Не удалось получить расписание из календаря"},
+		msgChatTimezone:             {"Часовой пояс чата: %s (сейчас %s)"},
+		msgWrongTimezone:            {"Неизвестный часовой пояс \"%s\", пример: Europe/Moscow"},
+		msgNoLessons:                {"Занятий нет 🎉"},
+		msgNoNextLesson:             {"В ближайшие две недели занятий нет 🎉"},
+		msgNextLesson:               {"Следующее занятие: %s\n%s, через %s"},
+		msgDurationDays:             {"%d д"},
+		msgDurationHours:            {"%d ч"},
+		msgDurationMinutes:          {"%d мин"},
+		msgDurationSeconds:          {"%d сек"},
+		msgScheduleChanged:          {"📅 Изменения в расписании:\n"},
+		msgLessonAdded:              {"➕ %s, %s - новое занятие\n"},
+		msgLessonCancelled:          {"❌ %s, %s отменено\n"},
+		msgLessonMoved:              {"🔁 %s: %s ➜ %s\n"},
+		msgScheduleNotifyHeader:     {"☀️ Расписание на сегодня:\n"},
+		msgScheduleNotifySettings:   {"Расписание на день присылается в %s%s"},
+		msgScheduleNotifyRemind:     {", напоминание за %d мин до занятия"},
+		msgScheduleNotifyDisabled:   {"Рассылка расписания выключена"},
+		msgLessonReminder:           {"⏰ Через %s: %s (%s)"},
+		msgWeekParity:               {"Неделя: %s\n"},
+		msgWeekParityNotSet:         {"Числитель и знаменатель в расписании не различаются\nЗадать тип текущей недели: /week числитель"},
+		msgErrorUpdateCalendarID:    {"Не удалось добавить календарь: \"%s\""},
+		msgICSFileTooBig:            {"Файл календаря слишком большой"},
+		msgSuccessUpdateICSCalendar: {"Теперь к вашему чату привязано расписание из .ics календаря"},
+		msgSuccessUpdateCalendarID:  {"Теперь к вашему чату привязано новое расписание\nНе забудь открыть доступ к своему календарю пользователю:\ncalendar-manager@flash-spark-404006.iam.gserviceaccount.com\n"},
+
+		msgSuccessAdminChangeDickSize: {"Успешно"},
+		msgErrorAdminChangeDickSize:   {"Не удалось поменять значение пениса данного пользователя"},
+
+		msgHomeworkCanceled:       {"Галя, у нас отмена!"},
+		msgHomeworkWithoutSubject: {"Пожалуйста после команды укажите название предмета в формате:\n/add_homework #ЗащитаИнформации Лабораторная 7..."},
+		msgHomeworkWithoutData:    {"Пожалуйста после команды и названия предмета укажите само задание в формате:\n/add_homework #ФизическаяКультура Задали пробежать 100 км на выходных"},
+		msgHomeworkSuccessAdded:   {"ДЗ: %s - %s\n Успешно добавлено"},
+
+		msgDialogNothingToCancel: {"Нечего отменять"},
+		msgYesReply:              {"Пизда"},
+		msgNoReply:               {"Пидора ответ"},
+
+		msgUserStats: {"Количество сообщений в данном чате: %d\nКоличество прибавлений к пенису: %d\nКоличество уменьшений пениса: %d\nПизда моментов: %d\nПидора ответ моментов: %d\n" +
+			"Всего дуелей: %d\nВыиграно дуелей: %d\nПроиграно дуелей: %d\nУбийств в дуелях: %d\nСмертей в дуелях: %d\n"},
+
+		// DUEL
+		msgDuelWithYourself: {"@%s засунул пенис себе в рот 🍆"},
+
+		msgChallengeToDuel: {"@%s вызывает на дуель @%s"},
+
+		msgAcceptDuel: {"@%s %s %d см 🍌 %.2f%%\n⚔️\n@%s %s %d см 🍌 %.2f%%\n"},
+
+		msgFinishDuel: {"\n\n🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️\n\n🏆 @%s %s %d см ➕ %d \n🤕@%s %s %d см ➖ %d"},
+
+		msgPlayerDie: {"\n\nεつ▄█▀█ ●\n\n🏆 @%s %s %d см ➕ %d \n🤕@%s %s %d см ➖ %d"},
+
+		msgCantCreateDuel: {"Невозможно создать дуель между @%s и @%s\nУ дуелянтов недостаточно HP"},
+
+		// HP
+		msgCantGetHP: {"@%s %s - твоё хп."},
+		msgGetHp:     {"@%s пополнил HP 🥰\nТекущее здоровье  %s"},
+
+		// HOMEWORK
+		msgAddSubject:       {"Введите название предмета"},
+		msgAddTask:          {"Введите задание"},
+		msgSuccessDelete:    {"Запись №%d успешно удалена"},
+		msgErrorDelete:      {"Не удалось удалить запись №%d"},
+		msgErrorAddHomework: {"Не удалось добавить задание"},
+
+		msgAddDeadline:    {"Введите дедлайн в формате ДД.ММ.ГГГГ [ЧЧ:ММ] или \"-\", если его нет"},
+		msgWrongDeadline:  {"Не получилось разобрать дату"},
+		msgExportHomework: {"Домашнее задание чата (в ics попадают только задания с дедлайном)"},
+
+		msgExportTitle:    {"Домашнее задание"},
+		msgExportSubject:  {"Предмет"},
+		msgExportTask:     {"Задание"},
+		msgExportCreated:  {"Добавлено"},
+		msgExportDeadline: {"Дедлайн"},
+
+		msgDigestHeader:   {"🗓 Домашнее задание за неделю:\n"},
+		msgDigestSettings: {"Сводка домашнего задания: %s, %s%s"},
+		msgDigestPinned:   {", с закреплением"},
+		msgDigestDisabled: {"Еженедельная сводка домашнего задания выключена"},
+
+		msgHomeworkEmpty:       {"Домашних заданий пока нет"},
+		msgHomeworkPage:        {"Домашнее задание (страница %d из %d):\n"},
+		msgHomeworkSubjectPage: {"Домашнее задание по предмету %s (страница %d из %d):\n"},
+
+		// auction
+		msgStartAuction: {`Итак дорогие друзья!
+Объявляю вашему внимаю, что запускается *аукцион*!
+
+Чтобы учавстовать ставь на кон часть совего пениса и увеличивай шансы победы в аукционе!
+Максимальная ставка: %d см.
+
+_Команда для участия:_
+/deposit _{amount}_ - amount является обязательным параметром! И не должен превышать размеры вашего члена!
+
+*УДАЧИ!!!*`},
+		msgAuctionIsStarted:     {"В данном чате уже запущен аукцион!"},
+		msgErrorDeposit:         {"Столько вашего пениса в аукцион не влезет..."},
+		msgAuctionNotStarted:    {"Аукцион пока что не запущен."},
+		msgSuccessDeposit:       {"Вы успешно внесли в аукцион %d см своего пениса!"},
+		msgNotEnoughPlayers:     {"Увы, в аукционе никто не участвовал..."},
+		msgZeroPlayers:          {"На данный момент никто не учавствует в аукционе\nКоманда для участия:\n/deposit {amount} - amount является обязательным параметром! И не должен превышать размеры вашего члена!"},
+		msgAuctionCountdown:     {"До результата аукциона: %d!"},
+		msgAuctionPlayersHeader: {"Текущие игроки аукциона:\n\n"},
+		msgAuctionFund:          {"\nТекущий фонд *%d см*!"},
+
+		msgWinner: {"@%s побеждает в аукционе!\nИ прибавляет %d см к своему пенису!"},
+
+		// ROLES
+		msgRolesHeader:    {"Роли в этом чате:\n"},
+		msgRoleLine:       {"%s - %s: %s\n"},
+		msgNoRoles:        {"Ролей пока никому не выдано\n"},
+		msgRolesFooter:    {"\nВыдать роль: /grant @username роль"},
+		msgRoleGranted:    {"@%s теперь %s"},
+		msgRoleRevoked:    {"@%s больше не %s"},
+		msgRoleAuctioneer: {"запускает аукционы"},
+
+		msgRoleUserNotFound: {"@%s ещё ничего не писал(а) в этом чате"},
+		msgRoleNotAssigned:  {"@%s и так не %s"},
+
+		// COOLDOWNS
+		msgCooldownWait:    {"@%s, попробуйте снова через %d сек ⏳"},
+		msgCooldownPerUser: {"раз в %s на участника"},
+		msgCooldownPerChat: {"раз в %s на чат"},
+		msgCooldownNone:    {"без ограничений"},
+		msgCommandCooldown: {"%s - %s"},
+		msgCooldownsHeader: {"Ограничения команд в чате:\n"},
+		msgNoCooldowns:     {"В чате можно вызывать команды без ограничений"},
+
+		// MEMBERS
+		msgWelcome:           {"Привет, {name}! Добро пожаловать в «{chat}» 👋\nЧто умеет бот: /help"},
+		msgGoodbye:           {"{name} покинул(а) чат. Пока! 👋"},
+		msgWelcomeCurrent:    {"Приветствие новых участников ({name} - участник, {chat} - название чата):\n\n%s"},
+		msgGoodbyeCurrent:    {"Прощание с ушедшими участниками ({name} - участник, {chat} - название чата):\n\n%s"},
+		msgGreetingsDisabled: {"\n\nПриветствия и прощания сейчас выключены в /settings"},
+
+		// AUTO REPLIES
+		msgAutoRepliesHeader:       {"Автоответы чата:\n"},
+		msgAutoReplyLine:           {"#%d %s «%s» → «%s», шанс %d%%, %s\n"},
+		msgAutoReplyCooldown:       {"не чаще раза в %s"},
+		msgNoAutoReplies:           {"Своих автоответов пока нет\n"},
+		msgAutoRepliesFooter:       {"\nОтветы на \"да\" и \"нет\" работают всегда, выключить все автоответы: /settings"},
+		msgAutoReplyAdded:          {"Автоответ добавлен:\n"},
+		msgAutoReplyRemoved:        {"Автоответ #%d удалён"},
+		msgAutoReplyNotFound:       {"Автоответа #%d нет в этом чате"},
+		msgAutoReplyBadPattern:     {"Неправильный шаблон «%s»: для regex нужно регулярное выражение, для rhyme - только буквы"},
+		msgAutoReplyBadProbability: {"Шанс ответа - число от 1 до 100"},
+
+		// SETTINGS
+		msgSettings:          {"Настройки чата ⚙️\nНажмите на кнопку, чтобы переключить настройку"},
+		msgSettingsForbidden: {"Менять настройки могут только админы группы"},
+		msgGamesDisabled:     {"Игры в этом чате выключены, включить: /settings"},
+		msgSettingGames:      {"Игры"},
+		msgSettingReplies:    {"Автоответы"},
+		msgSettingDelete:     {"Удалять команды"},
+		msgSettingLanguage:   {"Язык"},
+		msgSettingGreetings:  {"Приветствия"},
+
+		// DATES
+		msgMonday:    {"понедельник"},
+		msgTuesday:   {"вторник"},
+		msgWednesday: {"среда"},
+		msgThursday:  {"четверг"},
+		msgFriday:    {"пятница"},
+		msgSaturday:  {"суббота"},
+		msgSunday:    {"воскресенье"},
+
+		msgMondayShort:    {"Пн"},
+		msgTuesdayShort:   {"Вт"},
+		msgWednesdayShort: {"Ср"},
+		msgThursdayShort:  {"Чт"},
+		msgFridayShort:    {"Пт"},
+		msgSaturdayShort:  {"Сб"},
+		msgSundayShort:    {"Вс"},
+
+		msgWeekNumerator:   {"числитель"},
+		msgWeekDenominator: {"знаменатель"},
+
+		msgDeadline: {" (до %s)"},
+
+		// COMMANDS
+		msgCategoryHomework: {"📖 Домашнее задание"},
+		msgCategorySchedule: {"📅 Расписание"},
+		msgCategoryGames:    {"🍌 Игры"},
+		msgCategoryAuction:  {"💰 Аукцион"},
+		msgCategoryFun:      {"🎲 Развлечения"},
+		msgCategoryChat:     {"⚙️ Чат"},
+
+		msgCmdAddHomework:           {"добавить домашнее задание 📖"},
+		msgCmdAddHomeworkDetails:    {"Бот по очереди спросит предмет, задание и дедлайн. Отменить добавление: /cancel"},
+		msgCmdGetHomework:           {"последние домашние задания с кнопками перехода по страницам"},
+		msgCmdGetHomeworkDetails:    {"number - число записей на странице (по умолчанию 5), subject - название предмета"},
+		msgCmdDeleteHomework:        {"удалить запись по id"},
+		msgCmdCancelDialog:          {"отменить добавление домашнего задания или другой начатый диалог"},
+		msgCmdExportHomework:        {"выгрузить всё домашнее задание файлом"},
+		msgCmdExportHomeworkDetails: {"По умолчанию csv. В ics попадают только задания с дедлайном"},
+		msgCmdDigestUsage:           {"[день ЧЧ:ММ [pin] | off]"},
+		msgCmdDigest:                {"еженедельная сводка домашнего задания"},
+		msgCmdDigestDetails:         {"Без параметров показывает текущие настройки, pin - закреплять сводку.\nПример: /digest пт 18:00 pin"},
+		msgCmdScheduleUsage:         {"[image] [today|tomorrow|week|ДД.ММ|день]"},
+		msgCmdSchedule:              {"расписание занятий, с image - картинкой"},
+		msgCmdScheduleDetails:       {"По умолчанию на текущую неделю. Работает, только если к группе привязан календарь"},
+		msgCmdNextLesson:            {"следующее занятие и сколько до него осталось"},
+		msgCmdWeekParityUsage:       {"[числитель|знаменатель]"},
+		msgCmdWeekParity:            {"какая сейчас неделя: числитель или знаменатель"},
+		msgCmdWeekParityDetails:     {"С параметром задаёт тип текущей недели, занятия с пометкой другой недели (например, «(знам)» в названии) не показываются"},
+		msgCmdScheduleNotifyUsage:   {"[ЧЧ:ММ [remind N] | off]"},
+		msgCmdScheduleNotify:        {"присылать расписание на день по утрам и напоминать о занятиях"},
+		msgCmdScheduleNotifyDetails: {"remind N - напоминать о каждом занятии за N минут, дни без занятий пропускаются.\nПример: /schedule\\_notify 07:30 remind 10"},
+		msgCmdAddCalendarIDUsage:    {"{calendar-id|ссылка}"},
+		msgCmdAddCalendarID:         {"привязать расписание из Google Calendar или .ics календаря по ссылке"},
+		msgCmdAddCalendarIDDetails:  {"Можно также отправить .ics файл с командой в подписи.\n*ВАЖНО* - _для Google Calendar не забудьте в настройках календаря открыть доступ пользователю: calendar-manager@flash-spark-404006.iam.gserviceaccount.com_"},
+		msgCmdDicStart:              {"узнай всё про свой 🍌"},
+		msgCmdDickTop:               {"статистика всех 🍆"},
+		msgCmdDickDuel:              {"вызвать на бой или принять вызов ⚔️"},
+		msgCmdGetHP:                 {"пополнить здоровье для дуэлей, раз в день ❤️"},
+		msgCmdGayStart:              {"узнать, у кого сегодня удачный день 🤡"},
+		msgCmdGayStartDetails:       {"Выбирается среди админов чата"},
+		msgCmdGayTop:                {"статистика по бедолагам в чате 🔞"},
+		msgCmdStartAuction:          {"запустить аукцион"},
+		msgCmdAddDeposit:            {"сделать ставку в запущенном аукционе"},
+		msgCmdAddDepositDetails:     {"amount - сколько см поставить, не больше максимальной ставки и размера вашего пениса"},
+		msgCmdAuction:               {"участники текущего аукциона"},
+		msgCmdFinishAuction:         {"подвести итоги аукциона"},
+		msgCmdFlip:                  {"подбросить монетку 🪙"},
+		msgCmdXkcd:                  {"случайный xkcd комикс 😂"},
+		msgCmdAnecdot:               {"случайный анекдот от @bobuk"},
+		msgCmdHelp:                  {"справка по командам"},
+		msgCmdAll:                   {"позвать всех админов чата"},
+		msgCmdGetMyStats:            {"ваша статистика в этом чате"},
+		msgCmdGetChatStats:          {"общая статистика чата"},
+		msgCmdGetChatID:             {"id этого чата"},
+		msgCmdTimezone:              {"часовой пояс чата для расписания и игр"},
+		msgCmdTimezoneDetails:       {"По умолчанию Europe/Moscow"},
+		msgCmdSettings:              {"настройки чата: игры, автоответы, удаление команд, приветствия, язык"},
+		msgCmdSettingsDetails:       {"Переключать настройки кнопками могут только админы группы"},
+		msgCmdCooldown:              {"как часто можно вызывать команды в чате"},
+		msgCmdCooldownDetails:       {"user - ограничение на участника, chat - на весь чат, 0 - без ограничения, reset - как по умолчанию.\nПример: /cooldown /xkcd user 1m\nАдмины группы не ограничены"},
+		msgCmdAutoReplyUsage:        {"[add {regex|rhyme} {pattern} [probability] [cooldown] {reply} | remove {id}]"},
+		msgCmdAutoReply:             {"свои автоответы чата"},
+		msgCmdAutoReplyDetails:      {"regex - ответ на сообщения с подходящим регулярным выражением, rhyme - на сообщения, последнее слово которых кончается на pattern. probability - шанс ответа в процентах (по умолчанию 100), cooldown - как часто правило может отвечать. Шаблон и ответ с пробелами берутся в кавычки.\nПример: /autoreply add rhyme ой 50 10m \"Ой, всё\"\nОтветы на \"да\" и \"нет\" работают всегда, выключить все автоответы: /settings"},
+		msgCmdWelcomeUsage:          {"[текст | reset]"},
+		msgCmdWelcome:               {"приветствие новых участников"},
+		msgCmdWelcomeDetails:        {"{name} заменяется на участника, {chat} - на название чата, reset - текст по умолчанию. Выключить: /settings"},
+		msgCmdGoodbyeUsage:          {"[текст | reset]"},
+		msgCmdGoodbye:               {"прощание с ушедшими участниками"},
+		msgCmdGoodbyeDetails:        {"{name} заменяется на участника, {chat} - на название чата, reset - текст по умолчанию. Выключить: /settings"},
+		msgCmdRoles:                 {"роли участников чата"},
+		msgCmdGrantRole:             {"выдать участнику роль"},
+		msgCmdGrantRoleDetails:      {"Роль даёт доступ к командам, которые иначе доступны только админам группы. Список ролей: /roles"},
+		msgCmdRevokeRole:            {"забрать у участника роль"},
+		msgCmdChangeDick:            {"изменить размер пениса пользователю"},
+		msgCmdSendMessageByAdmin:    {"отправить сообщение от имени бота"},
+	},
+}
@@ -0,0 +1,51 @@
+package telegram
+
+import "tg_ics_useful_bot/lib/i18n"
+
+// ruCleanLocale сообщения без мата и пошлостей для семейных и учебных чатов.
+// Сообщения, которых здесь нет, берутся из ruLocale.
+var ruCleanLocale = ruLocale.Extend("ru-clean", "Русский без мата", i18n.Messages{
+	msgCreateUser:     {"@%s, только что вырастил(а) свой первый банан 🤣\n"},
+	msgDickSize:       {"Теперь размер банана: %d см 🍌"},
+	msgDickIncrease:   {"@%s, твой банан подрос на %d см 😍\n"},
+	msgDickDecrease:   {"@%s, твой банан уменьшился на %d см 😭\n"},
+	msgTargetNotFound: {"@%s у этого пользователя нет банана 🍌"},
+	msgChanceDuel:     {"@%s имеет банан %d см и шансы на победу %.2f%%\n@%s имеет банан %d см и шансы на победу %.2f%%\n"},
+
+	msgNewGayOfDay:     {"Новый счастливчик дня - @%s"},
+	msgCurrentGayOfDay: {"Текущий счастливчик дня - @%s"},
+	msgGayRatingHeader: {"Рейтинг счастливчиков: \n\n"},
+
+	msgErrorAdminChangeDickSize: {"Не удалось поменять размер банана данного пользователя"},
+
+	msgYesReply: {"Борода"},
+	msgNoReply:  {"Солнце светит, дождика нет"},
+
+	msgUserStats: {"Количество сообщений в данном чате: %d\nСколько раз банан подрос: %d\nСколько раз банан уменьшился: %d\nОтветов на \"да\": %d\nОтветов на \"нет\": %d\n" +
+		"Всего дуелей: %d\nВыиграно дуелей: %d\nПроиграно дуелей: %d\nУбийств в дуелях: %d\nСмертей в дуелях: %d\n"},
+
+	// DUEL
+	msgDuelWithYourself: {"@%s решил(а) сразиться сам(а) с собой и проиграл(а) 🍌"},
+
+	// auction
+	msgStartAuction: {`Итак дорогие друзья!
+Объявляю вашему вниманию, что запускается *аукцион*!
+
+Чтобы участвовать, ставь на кон часть своего банана и увеличивай шансы на победу в аукционе!
+Максимальная ставка: %d см.
+
+_Команда для участия:_
+/deposit _{amount}_ - amount является обязательным параметром! И не должен превышать размер вашего банана!
+
+*УДАЧИ!!!*`},
+	msgErrorDeposit:   {"Столько вашего банана в аукцион не влезет..."},
+	msgSuccessDeposit: {"Вы успешно внесли в аукцион %d см своего банана!"},
+	msgZeroPlayers:    {"На данный момент никто не участвует в аукционе\nКоманда для участия:\n/deposit {amount} - amount является обязательным параметром! И не должен превышать размер вашего банана!"},
+	msgWinner:         {"@%s побеждает в аукционе!\nИ прибавляет %d см к своему банану!"},
+
+	// COMMANDS
+	msgCmdDickTop:           {"статистика всех 🍌"},
+	msgCmdGayTop:            {"статистика счастливчиков дня 🍀"},
+	msgCmdAddDepositDetails: {"amount - сколько см поставить, не больше максимальной ставки и размера вашего банана"},
+	msgCmdChangeDick:        {"изменить размер банана пользователю"},
+})
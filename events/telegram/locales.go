@@ -0,0 +1,32 @@
+package telegram
+
+import (
+	"log"
+	"tg_ics_useful_bot/lib/i18n"
+)
+
+// defaultLanguage язык чата, если он не настроен.
+const defaultLanguage = "ru"
+
+// locales каталоги сообщений в порядке переключения кнопкой в /settings.
+var locales = []*i18n.Locale{ruLocale, ruCleanLocale, enLocale}
+
+// localeByCode возвращает каталог по коду языка или каталог по умолчанию, если такого языка нет.
+func localeByCode(code string) *i18n.Locale {
+	for _, l := range locales {
+		if l.Code == code {
+			return l
+		}
+	}
+	return ruLocale
+}
+
+// locale возвращает каталог сообщений на языке чата.
+func (p *Processor) locale(chatID int) *i18n.Locale {
+	settings, err := p.chatSettings(chatID)
+	if err != nil {
+		log.Printf("can't get chat settings for locale: %v", err)
+		return ruLocale
+	}
+	return localeByCode(settings.Language)
+}
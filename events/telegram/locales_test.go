@@ -0,0 +1,158 @@
+package telegram
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"tg_ics_useful_bot/lib/i18n"
+)
+
+// messageKeys возвращает все ключи сообщений, объявленные в messages.go.
+func messageKeys(t *testing.T) []string {
+	f, err := parser.ParseFile(token.NewFileSet(), "messages.go", nil, 0)
+	if err != nil {
+		t.Fatalf("can't parse messages.go: %v", err)
+	}
+	var keys []string
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			for _, value := range spec.(*ast.ValueSpec).Values {
+				key, err := strconv.Unquote(value.(*ast.BasicLit).Value)
+				if err != nil {
+					t.Fatalf("bad key %s: %v", value.(*ast.BasicLit).Value, err)
+				}
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+var formatVerb = regexp.MustCompile(`%[-+# 0]*[0-9.]*[a-zA-Z%]`)
+
+// formatVerbs возвращает глаголы форматирования сообщения без "%%".
+func formatVerbs(text string) []string {
+	var verbs []string
+	for _, v := range formatVerb.FindAllString(text, -1) {
+		if v != "%%" {
+			verbs = append(verbs, v)
+		}
+	}
+	return verbs
+}
+
+// pluralForms возвращает число форм множественного числа в правиле языка.
+func pluralForms(l *i18n.Locale) int {
+	forms := 0
+	for n := 0; n < 1000; n++ {
+		if f := l.Rule(n) + 1; f > forms {
+			forms = f
+		}
+	}
+	return forms
+}
+
+func TestLocalesComplete(t *testing.T) {
+	keys := messageKeys(t)
+	if len(keys) == 0 {
+		t.Fatal("no message keys in messages.go")
+	}
+	known := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if known[key] {
+			t.Errorf("duplicate key %q", key)
+		}
+		known[key] = true
+	}
+
+	for _, l := range locales {
+		for _, key := range keys {
+			forms, ok := l.Messages[key]
+			if !ok {
+				t.Errorf("%s: missing %q", l.Code, key)
+				continue
+			}
+			if len(forms) != 1 && len(forms) != pluralForms(l) {
+				t.Errorf("%s: %q has %d forms, want 1 or %d", l.Code, key, len(forms), pluralForms(l))
+			}
+			want := formatVerbs(ruLocale.Messages[key][0])
+			for _, form := range forms {
+				if got := formatVerbs(form); !reflect.DeepEqual(got, want) {
+					t.Errorf("%s: %q has format %v, want %v", l.Code, key, got, want)
+				}
+			}
+		}
+		for key := range l.Messages {
+			if !known[key] {
+				t.Errorf("%s: unknown key %q", l.Code, key)
+			}
+		}
+	}
+}
+
+// helpTexts возвращает /help и справку по каждой команде на языке l.
+func helpTexts(l *i18n.Locale) []string {
+	texts := []string{helpMessage(l, true)}
+	for _, cmd := range allCommands {
+		texts = append(texts, commandHelp(l, cmd))
+	}
+	return texts
+}
+
+func TestLocaleHelp(t *testing.T) {
+	// если сообщения нет в каталоге, вместо него выводится ключ
+	rawKey := regexp.MustCompile(`\b(cmd|category)_[a-z_]+\b`)
+	for _, l := range locales {
+		for _, text := range helpTexts(l) {
+			if key := rawKey.FindString(strings.ReplaceAll(text, `\_`, "_")); key != "" {
+				t.Errorf("%s: help contains key %q:\n%s", l.Code, key, text)
+			}
+		}
+		if help := helpMessage(l, false); !strings.Contains(help, l.Text(msgCategoryHomework)) {
+			t.Errorf("%s: no localized category in help:\n%s", l.Code, help)
+		}
+	}
+	if help := helpMessage(enLocale, false); strings.Contains(help, "Домашнее") {
+		t.Errorf("en: russian category in help:\n%s", help)
+	}
+}
+
+func TestCleanLocale(t *testing.T) {
+	swears := []string{"пизд", "пидор", "пенис", "член", "писюн", "хуй", "хуе", "бля", "еба", "ёба"}
+	check := func(name, text string) {
+		lower := strings.ToLower(text)
+		for _, s := range swears {
+			if strings.Contains(lower, s) {
+				t.Errorf("%s contains %q: %q", name, s, text)
+			}
+		}
+	}
+	for key, forms := range ruCleanLocale.Messages {
+		for _, form := range forms {
+			check(strconv.Quote(key), form)
+		}
+	}
+	for _, text := range helpTexts(ruCleanLocale) {
+		check("help", text)
+	}
+}
+
+func TestLocaleByCode(t *testing.T) {
+	for _, l := range locales {
+		if got := localeByCode(l.Code); got != l {
+			t.Errorf("localeByCode(%q) = %q", l.Code, got.Code)
+		}
+	}
+	if got := localeByCode("de"); got.Code != defaultLanguage {
+		t.Errorf("unknown language: got %q, want %q", got.Code, defaultLanguage)
+	}
+}
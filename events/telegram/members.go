@@ -0,0 +1,147 @@
+package telegram
+
+import (
+	"context"
+	"log"
+	"strings"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/events"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/lib/i18n"
+	"tg_ics_useful_bot/storage"
+)
+
+// processChatMember обрабатывает изменение статуса участника чата: сбрасывает кэш админов, когда участник
+// становится админом или перестаёт им быть, запоминает вошедших и отмечает вышедших.
+// Обновления chat_member приходят, только если бот админ в чате, иначе хватает служебных сообщений.
+func (p *Processor) processChatMember(event events.Event) error {
+	meta, err := meta(event)
+	if err != nil {
+		return e.Wrap("can't process chat member", err)
+	}
+
+	if meta.OldMember.IsAdmin() || meta.NewMember.IsAdmin() {
+		p.admins.Invalidate(meta.ChatID)
+	}
+	if meta.ChatType == "private" || p.isMe(meta.user()) {
+		return nil
+	}
+
+	if meta.NewMember.IsMember() {
+		err = p.memberJoined(meta.ChatID, meta.user())
+	} else {
+		err = p.memberLeft(meta.ChatID, meta.user())
+	}
+	if err != nil {
+		return e.Wrap("can't process chat member", err)
+	}
+	return nil
+}
+
+// processMyChatMember убирает данные чата, из которого удалили бота. В личных чатах
+// так приходит блокировка бота пользователем, её не нужно обрабатывать.
+func (p *Processor) processMyChatMember(event events.Event) error {
+	meta, err := meta(event)
+	if err != nil {
+		return e.Wrap("can't process my chat member", err)
+	}
+
+	if meta.ChatType == "private" || meta.NewMember.IsMember() {
+		return nil
+	}
+	log.Printf("[INFO] bot was removed from '%s' (#%d)", meta.ChatTitle, meta.ChatID)
+
+	p.admins.Invalidate(meta.ChatID)
+	p.schedules.Invalidate(meta.ChatID)
+	if err := p.storage.DeactivateChat(context.Background(), meta.ChatID); err != nil {
+		return e.Wrap("can't process my chat member", err)
+	}
+	return nil
+}
+
+// processMembersMessage обрабатывает служебное сообщение о входе или выходе участников:
+// запоминает их и приветствует или прощается, если это не выключено в настройках чата.
+func (p *Processor) processMembersMessage(meta Meta) error {
+	chat := meta.chat()
+	settings, err := p.chatSettings(chat.ID)
+	if err != nil {
+		return e.Wrap("can't process members message", err)
+	}
+	lang := localeByCode(settings.Language)
+
+	for i := range meta.NewChatMembers {
+		user := &meta.NewChatMembers[i]
+		if p.isMe(user) {
+			continue
+		}
+		if err := p.memberJoined(chat.ID, user); err != nil {
+			return e.Wrap("can't process members message", err)
+		}
+		if settings.Greetings {
+			if err := p.sendMessage(chat.ID, greetingText(lang, settings.Welcome, msgWelcome, user, chat), "", 0, nil); err != nil {
+				return e.Wrap("can't send welcome message", err)
+			}
+		}
+	}
+
+	if user := meta.LeftChatMember; user != nil && !p.isMe(user) {
+		if err := p.memberLeft(chat.ID, user); err != nil {
+			return e.Wrap("can't process members message", err)
+		}
+		if settings.Greetings {
+			if err := p.sendMessage(chat.ID, greetingText(lang, settings.Goodbye, msgGoodbye, user, chat), "", 0, nil); err != nil {
+				return e.Wrap("can't send goodbye message", err)
+			}
+		}
+	}
+	return nil
+}
+
+// memberJoined запоминает участника, вошедшего в чат, или возвращает вернувшегося
+// и обновляет его данные из Telegram, например сменившийся username.
+func (p *Processor) memberJoined(chatID int, user *telegram.User) error {
+	dbUser, err := p.storage.GetUser(context.Background(), user.ID, chatID)
+	if err == storage.ErrUserNotExist {
+		_, err = p.createNewUserInDB(chatID, user)
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	if !dbUser.Active {
+		if err := p.storage.SetUserActive(context.Background(), user.ID, chatID, true); err != nil {
+			return err
+		}
+	}
+	_, err = p.userChangeInfo(user, dbUser)
+	return err
+}
+
+// memberLeft отмечает, что участник вышел из чата: он пропадает из рейтингов и игр, но его данные остаются.
+func (p *Processor) memberLeft(chatID int, user *telegram.User) error {
+	log.Printf("[INFO] user #%d '%s' left chat #%d", user.ID, user.Username, chatID)
+	return p.storage.SetUserActive(context.Background(), user.ID, chatID, false)
+}
+
+// isMe показывает, что пользователь - сам бот.
+func (p *Processor) isMe(user *telegram.User) bool {
+	return user.IsBot && strings.EqualFold(user.Username, p.username)
+}
+
+// greetingText возвращает приветствие или прощание: текст чата custom или текст по умолчанию key
+// с подставленными {name} и {chat}.
+func greetingText(lang *i18n.Locale, custom, key string, user *telegram.User, chat *telegram.Chat) string {
+	text := custom
+	if text == "" {
+		text = lang.Text(key)
+	}
+	return strings.NewReplacer("{name}", memberName(user), "{chat}", chat.Title).Replace(text)
+}
+
+// memberName возвращает @username участника или его имя, если username нет.
+func memberName(user *telegram.User) string {
+	if user.Username != "" {
+		return "@" + user.Username
+	}
+	return strings.TrimSpace(user.FirstName + " " + user.LastName)
+}
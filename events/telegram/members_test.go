@@ -0,0 +1,55 @@
+package telegram
+
+import (
+	"testing"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/events"
+)
+
+func TestGreetingText(t *testing.T) {
+	chat := &telegram.Chat{ID: -100, Title: "ИКС-21"}
+	tests := []struct {
+		custom string
+		user   *telegram.User
+		want   string
+	}{
+		{"", &telegram.User{Username: "vasya"}, "Привет, @vasya! Добро пожаловать в «ИКС-21» 👋\nЧто умеет бот: /help"},
+		{"Здравствуй, {name}", &telegram.User{FirstName: "Вася", LastName: "Пупкин"}, "Здравствуй, Вася Пупкин"},
+		{"{chat} рад {name} 100%", &telegram.User{FirstName: "Вася"}, "ИКС-21 рад Вася 100%"},
+	}
+	for _, tt := range tests {
+		if got := greetingText(ruLocale, tt.custom, msgWelcome, tt.user, chat); got != tt.want {
+			t.Errorf("greetingText(%q) = %q, want %q", tt.custom, got, tt.want)
+		}
+	}
+}
+
+func TestMemberEvents(t *testing.T) {
+	joined := telegram.User{ID: 2, Username: "vasya"}
+	upd := telegram.Update{Message: &telegram.IncomingMessage{
+		Chat:           telegram.Chat{ID: -100, Type: "supergroup"},
+		From:           telegram.User{ID: 1},
+		NewChatMembers: []telegram.User{joined},
+	}}
+	ev := event(upd)
+	if ev.Type != events.Message {
+		t.Fatalf("service message type = %v, want Message", ev.Type)
+	}
+	if m := ev.Meta.(Meta); len(m.NewChatMembers) != 1 || m.NewChatMembers[0] != joined {
+		t.Errorf("new chat members = %v", m.NewChatMembers)
+	}
+
+	upd = telegram.Update{MyChatMember: &telegram.ChatMemberUpdated{
+		Chat:          telegram.Chat{ID: -100, Type: "supergroup"},
+		OldChatMember: telegram.ChatMember{Status: "member"},
+		NewChatMember: telegram.ChatMember{Status: "kicked", User: telegram.User{ID: 3, IsBot: true}},
+	}}
+	ev = event(upd)
+	m := ev.Meta.(Meta)
+	if ev.Type != events.MyChatMember || m.ChatID != -100 || m.TgID != 3 || m.NewMember.Status != "kicked" {
+		t.Errorf("my_chat_member event = %v %+v", ev.Type, m)
+	}
+	if m.NewMember.IsMember() {
+		t.Errorf("kicked bot is still a member")
+	}
+}
@@ -1,117 +1,329 @@
 package telegram
 
-const msgHelp = `**Доступные команды:**
+// Ключи сообщений в каталогах locale*.go. Тексты выбираются по языку чата, см. (*Processor).locale.
+// HELP
+const (
+	msgHelpHeader               = "help_header"
+	msgHelpFooter               = "help_footer"
+	msgHelpChatAdmin            = "help_chat_admin"
+	msgHelpChatAdminDetails     = "help_chat_admin_details"
+	msgHelpChatAdminOnly        = "help_chat_admin_only"
+	msgHelpChatAdminOnlyDetails = "help_chat_admin_only_details"
+	msgHelpRole                 = "help_role"
+	msgHelpBotAdminHeader       = "help_bot_admin_header"
+	msgHelpBotAdminDetails      = "help_bot_admin_details"
+	msgHelpAliases              = "help_aliases"
+	msgUnknownHelpCommand       = "unknown_help_command"
+
+	msgCommandUsage    = "command_usage"
+	msgMissingArg      = "missing_arg"
+	msgInvalidArg      = "invalid_arg"
+	msgInvalidValue    = "invalid_value"
+	msgTooManyArgs     = "too_many_args"
+	msgArgChoice       = "arg_choice"
+	msgArgKindInt      = "arg_kind_int"
+	msgArgKindDuration = "arg_kind_duration"
+	msgArgKindUsername = "arg_kind_username"
+	msgArgKindMention  = "arg_kind_mention"
+)
+
+// ERRORS
+const (
+	msgErrorInternal  = "error_internal"
+	msgErrorForbidden = "error_forbidden"
 
-/add - добавить домашнее задание 📖
-/cancel - отменить добавление домашнего задания
-/get [number] [subject] - может вызываться без параметров, тогда выведет последние 5 добавленных записей, либо с одним из параметров, number - число последних записей, subject - название предмета
-/delete id - удалить запись по id
+	msgForbiddenChange   = "forbidden_change"
+	msgForbiddenCommand  = "forbidden_command"
+	msgForbiddenRole     = "forbidden_role"
+	msgErrorUserNotFound = "error_user_not_found"
+	msgErrorUnavailable  = "error_unavailable"
+)
 
-/schedule - получить расписание из Google Calendar (_рабоает только если привязан calendar-id группы_)
-/add\_calendar *[calendar-id]* - привязать расписание из Google Calendar (_возможно только для админов группы_)
-(*ВАЖНО*  - _не забудьте в настройках календаря открыть доступ пользователю: calendar-manager@flash-spark-404006.iam.gserviceaccount.com_)
+const (
+	msgCreateUser     = "create_user"
+	msgAlreadyPlays   = "already_plays"
+	msgDickSize       = "dick_size"
+	msgDickIncrease   = "dick_increase"
+	msgDickDecrease   = "dick_decrease"
+	msgChangeDickSize = "change_dick_size"
+	msgDickTopLeader  = "dick_top_leader"
+	msgDickTopLine    = "dick_top_line"
 
-/dick - узнай всё про свой 🍌
-/duel _@username_ - вызвать на (или принять) бой ⚔️
-/top\_dick - статистика всех 🍆
+	msgTargetNotFound = "target_not_found"
+	msgVictoryInDuel  = "victory_in_duel"
+	msgUserHasBanned  = "user_has_banned"
 
-/gay - узнать у кого сегодня удачный день 🤡 (_работает, только на админов чата_)
-/top\_gay - статистика по бедолагам в чате 🔞
+	msgChanceDuel = "chance_duel"
 
-/start\_auction - заустить аукцион!
-/deposit *[amount]* - если аук. запущен, то данной командой можно в нём поучавствовать, amount - обязательный параметр. 
-/auction - если аукцион запущен, покажет список его участников.
+	msgNewGayOfDay     = "new_gay_of_day"
+	msgCurrentGayOfDay = "current_gay_of_day"
+	msgGayRatingHeader = "gay_rating_header"
+	msgGayRatingLine   = "gay_rating_line"
 
-/flip - подбросить монетку 🪙
-/xkcd - случайный xkcd комикс 😂
-`
+	msgCalendarNotExists        = "calendar_not_exists"
+	msgErrorSendMessage         = "error_send_message"
+	msgChatTimezone             = "chat_timezone"
+	msgWrongTimezone            = "wrong_timezone"
+	msgNoLessons                = "no_lessons"
+	msgNoNextLesson             = "no_next_lesson"
+	msgNextLesson               = "next_lesson"
+	msgDurationDays             = "duration_days"
+	msgDurationHours            = "duration_hours"
+	msgDurationMinutes          = "duration_minutes"
+	msgDurationSeconds          = "duration_seconds"
+	msgScheduleChanged          = "schedule_changed"
+	msgLessonAdded              = "lesson_added"
+	msgLessonCancelled          = "lesson_cancelled"
+	msgLessonMoved              = "lesson_moved"
+	msgScheduleNotifyHeader     = "schedule_notify_header"
+	msgScheduleNotifySettings   = "schedule_notify_settings"
+	msgScheduleNotifyRemind     = "schedule_notify_remind"
+	msgScheduleNotifyDisabled   = "schedule_notify_disabled"
+	msgLessonReminder           = "lesson_reminder"
+	msgWeekParity               = "week_parity"
+	msgWeekParityNotSet         = "week_parity_not_set"
+	msgErrorUpdateCalendarID    = "error_update_calendar_id"
+	msgICSFileTooBig            = "ics_file_too_big"
+	msgSuccessUpdateICSCalendar = "success_update_ics_calendar"
+	msgSuccessUpdateCalendarID  = "success_update_calendar_id"
 
-const (
-	msgCreateUser     = "@%s, только что обнаружил(а) свой пенис 🤣\n"
-	msgAlreadyPlays   = "@%s, сегодня все твои попытки закончились 🚨\n"
-	msgDickSize       = "Теперь размер его пениса: %d см 🍌"
-	msgDickIncrease   = "@%s, твой пенис увеличился на %d см 😍\n"
-	msgDickDecrease   = "@%s, твой пенис уменьшился на %d см 😭\n"
-	msgChangeDickSize = "@%s, %d ➜ %d см 🍌\n"
-
-	msgTargetNotFound = "@%s этот пользователь не имеет писюна 🍆"
-	msgVictoryInDuel  = "@%s победил в дуели @%s\n"
-	msgUserHasBanned  = "@%s получает бан на %d секунд 🚫\n"
-
-	msgChanceDuel = "@%s имеет пенис %d см и шансы на победу %.2f%%\n@%s имеет пенис %d см и шансы на победу %.2f%%\n"
-
-	msgNewGayOfDay     = "Новый пидор дня - @%s"
-	msgCurrentGayOfDay = "Текущий пидор дня - @%s"
-
-	msgCalendarNotExists       = "К вашей группе не привязан Google Calendar\nЧтобы приязвать Google Calendar воспользуйтесь командой /add_calendar"
-	msgForbiddenCalendarUpdate = "Обновить id календаря может только администратор группы"
-	msgErrorSendMessage        = "Не удалось получить расписание из календаря с id: %s"
-	msgErrorUpdateCalendarID   = "Не удалось добавить данное id календаря: \"%s\""
-	msgSuccessUpdateCalendarID = "Теперь к вашему чату привязано новое расписание\nНе забудь открыть доступ к своему календарю пользователю:\ncalendar-manager@flash-spark-404006.iam.gserviceaccount.com\n"
-
-	msgSuccessAdminChangeDickSize = "Успешно"
-	msgErrorAdminChangeDickSize   = "Не удалось поменять значение пениса данного пользователя"
-
-	msgHomeworkCanceled       = "Галя, у нас отмена!"
-	msgHomeworkWithoutSubject = "Пожалуйста после команды укажите название предмета в формате:\n/add_homework #ЗащитаИнформации Лабораторная 7..."
-	msgHomeworkWithoutData    = "Пожалуйста после команды и названия предмета укажите само задание в формате:\n/add_homework #ФизическаяКультура Задали пробежать 100 км на выходных"
-	msgHomeworkSuccessAdded   = "ДЗ: %s - %s\n Успешно добавлено"
-
-	msgUserStats = "Количество сообщений в данном чате: %d\nКоличество прибавлений к пенису: %d\nКоличество уменьшений пениса: %d\nПизда моментов: %d\nПидора ответ моментов: %d\n" +
-		"Всего дуелей: %d\nВыиграно дуелей: %d\nПроиграно дуелей: %d\nУбийств в дуелях: %d\nСмертей в дуелях: %d\n"
+	msgSuccessAdminChangeDickSize = "success_admin_change_dick_size"
+	msgErrorAdminChangeDickSize   = "error_admin_change_dick_size"
+
+	msgHomeworkCanceled       = "homework_canceled"
+	msgHomeworkWithoutSubject = "homework_without_subject"
+	msgHomeworkWithoutData    = "homework_without_data"
+	msgHomeworkSuccessAdded   = "homework_success_added"
+
+	msgDialogNothingToCancel = "dialog_nothing_to_cancel"
+	msgYesReply              = "yes_reply"
+	msgNoReply               = "no_reply"
+
+	msgUserStats = "user_stats"
 )
 
 // DUEL
 const (
-	msgDuelWithYourself = "@%s засунул пенис себе в рот 🍆"
+	msgDuelWithYourself = "duel_with_yourself"
 
-	msgChallengeToDuel = "@%s вызывает на дуель @%s"
+	msgChallengeToDuel = "challenge_to_duel"
 
-	msgAcceptDuel = "@%s %s %d см 🍌 %.2f%%\n⚔️\n@%s %s %d см 🍌 %.2f%%\n"
+	msgAcceptDuel = "accept_duel"
 
-	msgFinishDuel = "\n\n🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️🤼‍♂️\n\n🏆 @%s %s %d см ➕ %d \n🤕@%s %s %d см ➖ %d"
+	msgFinishDuel = "finish_duel"
 
-	msgPlayerDie = "\n\nεつ▄█▀█ ●\n\n🏆 @%s %s %d см ➕ %d \n🤕@%s %s %d см ➖ %d"
+	msgPlayerDie = "player_die"
 
-	msgCantCreateDuel = "Невозможно создать дуель между @%s и @%s\nУ дуелянтов недостаточно HP"
+	msgCantCreateDuel = "cant_create_duel"
 )
 
 // HP
 const (
-	msgCantGetHP = "@%s %s - твоё хп."
-	msgGetHp     = "@%s пополнил HP 🥰\nТекущее здоровье  %s"
+	msgCantGetHP = "cant_get_hp"
+	msgGetHp     = "get_hp"
 )
 
 // HOMEWORK
 const (
-	msgAddSubject = "Введите название предмета"
-	msgAddTask    = "Введите задание"
-	msgSomethingWrong
-	msgSuccessDelete    = "Запись №%d успешно удалена"
-	msgErrorDelete      = "Не удалось удалить запись №%d"
-	msgIncorrectValue   = "%s - некоректное значение id"
-	msgErrorAddHomework = "Не удалось добавить задание"
+	msgAddSubject       = "add_subject"
+	msgAddTask          = "add_task"
+	msgSuccessDelete    = "success_delete"
+	msgErrorDelete      = "error_delete"
+	msgErrorAddHomework = "error_add_homework"
+
+	msgAddDeadline    = "add_deadline"
+	msgWrongDeadline  = "wrong_deadline"
+	msgExportHomework = "export_homework"
+
+	msgExportTitle    = "export_title"
+	msgExportSubject  = "export_subject"
+	msgExportTask     = "export_task"
+	msgExportCreated  = "export_created"
+	msgExportDeadline = "export_deadline"
+
+	msgDigestHeader   = "digest_header"
+	msgDigestSettings = "digest_settings"
+	msgDigestPinned   = "digest_pinned"
+	msgDigestDisabled = "digest_disabled"
+
+	msgHomeworkEmpty       = "homework_empty"
+	msgHomeworkPage        = "homework_page"
+	msgHomeworkSubjectPage = "homework_subject_page"
 )
 
 // auction
 const (
-	msgStartAuction = `Итак дорогие друзья!
-Объявляю вашему внимаю, что запускается *аукцион*!
+	msgStartAuction         = "start_auction"
+	msgAuctionIsStarted     = "auction_is_started"
+	msgErrorDeposit         = "error_deposit"
+	msgAuctionNotStarted    = "auction_not_started"
+	msgSuccessDeposit       = "success_deposit"
+	msgNotEnoughPlayers     = "not_enough_players"
+	msgZeroPlayers          = "zero_players"
+	msgAuctionCountdown     = "auction_countdown"
+	msgAuctionPlayersHeader = "auction_players_header"
+	msgAuctionFund          = "auction_fund"
+
+	msgWinner = "winner"
+)
+
+// ROLES
+const (
+	msgRolesHeader    = "roles_header"
+	msgRoleLine       = "role_line"
+	msgNoRoles        = "no_roles"
+	msgRolesFooter    = "roles_footer"
+	msgRoleGranted    = "role_granted"
+	msgRoleRevoked    = "role_revoked"
+	msgRoleAuctioneer = "role_auctioneer"
 
-Чтобы учавстовать ставь на кон часть совего пениса и увеличивай шансы победы в аукционе!
-Максимальная ставка: %d см.
+	msgRoleUserNotFound = "role_user_not_found"
+	msgRoleNotAssigned  = "role_not_assigned"
+)
+
+// COOLDOWNS
+const (
+	msgCooldownWait    = "cooldown_wait"
+	msgCooldownPerUser = "cooldown_per_user"
+	msgCooldownPerChat = "cooldown_per_chat"
+	msgCooldownNone    = "cooldown_none"
+	msgCommandCooldown = "command_cooldown"
+	msgCooldownsHeader = "cooldowns_header"
+	msgNoCooldowns     = "no_cooldowns"
+)
+
+// AUTO REPLIES
+const (
+	msgAutoRepliesHeader       = "auto_replies_header"
+	msgAutoReplyLine           = "auto_reply_line"
+	msgAutoReplyCooldown       = "auto_reply_cooldown"
+	msgNoAutoReplies           = "no_auto_replies"
+	msgAutoRepliesFooter       = "auto_replies_footer"
+	msgAutoReplyAdded          = "auto_reply_added"
+	msgAutoReplyRemoved        = "auto_reply_removed"
+	msgAutoReplyNotFound       = "auto_reply_not_found"
+	msgAutoReplyBadPattern     = "auto_reply_bad_pattern"
+	msgAutoReplyBadProbability = "auto_reply_bad_probability"
+)
 
-_Команда для участия:_
-/deposit _{amount}_ - amount является обязательным параметром! И не должен превышать размеры вашего члена!
+// MEMBERS
+const (
+	msgWelcome           = "welcome"
+	msgGoodbye           = "goodbye"
+	msgWelcomeCurrent    = "welcome_current"
+	msgGoodbyeCurrent    = "goodbye_current"
+	msgGreetingsDisabled = "greetings_disabled"
+)
 
-*УДАЧИ!!!*`
-	msgAuctionIsStarted  = "В данном чате уже запущен аукцион!"
-	msgErrorDeposit      = "Столько вашего пениса в аукцион не влезет..."
-	msgAuctionNotStarted = "Аукцион пока что не запущен."
-	msgSuccessDeposit    = "Вы успешно внесли в аукцион %d см своего пениса!"
-	msgErrorDepositCmd   = "Чтобы внести депозит в аукцион введите\n/deposit {amount} - amount обязательный аргумент, не превышающий размер вашего пениса"
-	msgNotEnoughPlayers  = "Увы, в аукционе никто не участвовал..."
-	msgZeroPlayers       = "На данный момент никто не учавствует в аукционе\nКоманда для участия:\n/deposit {amount} - amount является обязательным параметром! И не должен превышать размеры вашего члена!"
+// SETTINGS
+const (
+	msgSettings          = "settings"
+	msgSettingsForbidden = "settings_forbidden"
+	msgGamesDisabled     = "games_disabled"
+	msgSettingGames      = "setting_games"
+	msgSettingReplies    = "setting_replies"
+	msgSettingDelete     = "setting_delete"
+	msgSettingGreetings  = "setting_greetings"
+	msgSettingLanguage   = "setting_language"
+)
+
+// DATES
+const (
+	msgMonday    = "monday"
+	msgTuesday   = "tuesday"
+	msgWednesday = "wednesday"
+	msgThursday  = "thursday"
+	msgFriday    = "friday"
+	msgSaturday  = "saturday"
+	msgSunday    = "sunday"
+
+	msgMondayShort    = "monday_short"
+	msgTuesdayShort   = "tuesday_short"
+	msgWednesdayShort = "wednesday_short"
+	msgThursdayShort  = "thursday_short"
+	msgFridayShort    = "friday_short"
+	msgSaturdayShort  = "saturday_short"
+	msgSundayShort    = "sunday_short"
+
+	msgWeekNumerator   = "week_numerator"
+	msgWeekDenominator = "week_denominator"
+
+	msgDeadline = "deadline"
+)
+
+// COMMANDS
+// Разделы справки и описания команд для /help и setMyCommands, см. Command.
+const (
+	msgCategoryHomework = "category_homework"
+	msgCategorySchedule = "category_schedule"
+	msgCategoryGames    = "category_games"
+	msgCategoryAuction  = "category_auction"
+	msgCategoryFun      = "category_fun"
+	msgCategoryChat     = "category_chat"
 
-	msgWinner = "@%s побеждает в аукционе!\nИ прибавляет %d см к своему пенису!"
+	msgCmdAddHomework           = "cmd_add"
+	msgCmdAddHomeworkDetails    = "cmd_add_details"
+	msgCmdGetHomework           = "cmd_get"
+	msgCmdGetHomeworkDetails    = "cmd_get_details"
+	msgCmdDeleteHomework        = "cmd_delete"
+	msgCmdCancelDialog          = "cmd_cancel"
+	msgCmdExportHomework        = "cmd_export_homework"
+	msgCmdExportHomeworkDetails = "cmd_export_homework_details"
+	msgCmdDigest                = "cmd_digest"
+	msgCmdDigestDetails         = "cmd_digest_details"
+	msgCmdDigestUsage           = "cmd_digest_usage"
+	msgCmdSchedule              = "cmd_schedule"
+	msgCmdScheduleDetails       = "cmd_schedule_details"
+	msgCmdScheduleUsage         = "cmd_schedule_usage"
+	msgCmdNextLesson            = "cmd_next"
+	msgCmdWeekParity            = "cmd_week"
+	msgCmdWeekParityDetails     = "cmd_week_details"
+	msgCmdWeekParityUsage       = "cmd_week_usage"
+	msgCmdScheduleNotify        = "cmd_schedule_notify"
+	msgCmdScheduleNotifyDetails = "cmd_schedule_notify_details"
+	msgCmdScheduleNotifyUsage   = "cmd_schedule_notify_usage"
+	msgCmdAddCalendarID         = "cmd_add_calendar"
+	msgCmdAddCalendarIDDetails  = "cmd_add_calendar_details"
+	msgCmdAddCalendarIDUsage    = "cmd_add_calendar_usage"
+	msgCmdDicStart              = "cmd_dick"
+	msgCmdDickTop               = "cmd_top_dick"
+	msgCmdDickDuel              = "cmd_duel"
+	msgCmdGetHP                 = "cmd_hp"
+	msgCmdGayStart              = "cmd_gay"
+	msgCmdGayStartDetails       = "cmd_gay_details"
+	msgCmdGayTop                = "cmd_top_gay"
+	msgCmdStartAuction          = "cmd_start_auction"
+	msgCmdAddDeposit            = "cmd_deposit"
+	msgCmdAddDepositDetails     = "cmd_deposit_details"
+	msgCmdAuction               = "cmd_auction"
+	msgCmdFinishAuction         = "cmd_finish_auction"
+	msgCmdFlip                  = "cmd_flip"
+	msgCmdXkcd                  = "cmd_xkcd"
+	msgCmdAnecdot               = "cmd_joke"
+	msgCmdHelp                  = "cmd_help"
+	msgCmdAll                   = "cmd_all"
+	msgCmdGetMyStats            = "cmd_my_stats"
+	msgCmdGetChatStats          = "cmd_chat_stats"
+	msgCmdGetChatID             = "cmd_chat_id"
+	msgCmdTimezone              = "cmd_timezone"
+	msgCmdTimezoneDetails       = "cmd_timezone_details"
+	msgCmdSettings              = "cmd_settings"
+	msgCmdSettingsDetails       = "cmd_settings_details"
+	msgCmdCooldown              = "cmd_cooldown"
+	msgCmdCooldownDetails       = "cmd_cooldown_details"
+	msgCmdAutoReply             = "cmd_autoreply"
+	msgCmdAutoReplyDetails      = "cmd_autoreply_details"
+	msgCmdAutoReplyUsage        = "cmd_autoreply_usage"
+	msgCmdWelcome               = "cmd_welcome"
+	msgCmdWelcomeDetails        = "cmd_welcome_details"
+	msgCmdWelcomeUsage          = "cmd_welcome_usage"
+	msgCmdGoodbye               = "cmd_goodbye"
+	msgCmdGoodbyeDetails        = "cmd_goodbye_details"
+	msgCmdGoodbyeUsage          = "cmd_goodbye_usage"
+	msgCmdRoles                 = "cmd_roles"
+	msgCmdGrantRole             = "cmd_grant"
+	msgCmdGrantRoleDetails      = "cmd_grant_details"
+	msgCmdRevokeRole            = "cmd_revoke"
+	msgCmdChangeDick            = "cmd_change_dick"
+	msgCmdSendMessageByAdmin    = "cmd_send_message"
 )
@@ -0,0 +1,177 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/lib/i18n"
+	"tg_ics_useful_bot/lib/utils"
+	"tg_ics_useful_bot/storage"
+)
+
+// Request входящее сообщение, которое проходит через цепочку middleware.
+type Request struct {
+	Text      string
+	Chat      *telegram.Chat
+	User      *telegram.User
+	MessageID int
+	// Document файл, отправленный вместе с сообщением, nil если его нет.
+	Document *telegram.Document
+
+	// DBUser и UserStats заполняет withUser.
+	DBUser    *storage.DBUser
+	UserStats *storage.DBUserStat
+	// Settings настройки чата, заполняет withChatSettings.
+	Settings *storage.DBChatSettings
+	// Locale сообщения на языке чата, заполняет withChatSettings.
+	Locale *i18n.Locale
+	// Command команда из сообщения, заполняет routeCommand. nil - сообщение не команда этому боту.
+	Command *Command
+}
+
+// Handler обрабатывает сообщение и возвращает ответ бота, nil - отвечать не нужно.
+type Handler func(p *Processor, req *Request) (*Response, error)
+
+// Middleware оборачивает обработчик: может изменить запрос, ответить сам или дополнить ответ next.
+type Middleware func(next Handler) Handler
+
+// allMiddlewares цепочка обработки сообщений, первый в списке вызывается первым.
+var allMiddlewares = []Middleware{
+	withUser,
+	withChatSettings,
+	countMessages,
+	continueDialogs,
+	autoReplies,
+	routeCommand,
+	deleteCommandMessage,
+	checkChatSettings,
+	checkPermission,
+	checkCooldown,
+}
+
+// chain оборачивает handler в middlewares так, что первый из них вызывается первым.
+func chain(handler Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// withUser находит или создаёт пользователя в базе, обновляет его данные из Telegram
+// и загружает статистику.
+func withUser(next Handler) Handler {
+	return func(p *Processor, req *Request) (*Response, error) {
+		dbUser, err := p.storage.GetUser(context.Background(), req.User.ID, req.Chat.ID) // TODO: добавить cache для dbUser
+		if err == storage.ErrUserNotExist {
+			dbUser, err = p.createNewUserInDB(req.Chat.ID, req.User)
+			if err != nil {
+				return nil, err
+			}
+		} else if err != nil {
+			return nil, err
+		}
+
+		if !dbUser.Active {
+			// пользователь пишет, значит он в чате, даже если бот пропустил его вход
+			if err := p.storage.SetUserActive(context.Background(), req.User.ID, req.Chat.ID, true); err != nil {
+				return nil, e.Wrap("can't activate user", err)
+			}
+			dbUser.Active = true
+		}
+
+		dbUser, err = p.userChangeInfo(req.User, dbUser)
+		if err != nil {
+			return nil, e.Wrap("can't update user info", err)
+		}
+
+		userStats, err := p.storage.GetUserStats(context.Background(), dbUser)
+		if err == storage.ErrUserNotExist {
+			return nil, e.Wrap("not find user stats", err)
+		} else if err != nil {
+			return nil, e.Wrap("not user stats", err)
+		}
+
+		req.DBUser, req.UserStats = dbUser, userStats
+		return next(p, req)
+	}
+}
+
+// countMessages увеличивает счётчик сообщений пользователя.
+func countMessages(next Handler) Handler {
+	return func(p *Processor, req *Request) (*Response, error) {
+		req.UserStats.MessageCount++
+		if err := p.storage.UpdateUserStats(context.Background(), req.UserStats); err != nil {
+			log.Print(err)
+		}
+		return next(p, req)
+	}
+}
+
+// continueDialogs передаёт сообщение, которое не является командой, в начатый пользователем диалог.
+func continueDialogs(next Handler) Handler {
+	return func(p *Processor, req *Request) (*Response, error) {
+		if utils.IsCommand(req.Text) {
+			return next(p, req)
+		}
+		msg, ok, err := p.continueDialog(req.Text, req.Chat, req.User)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return textResponse(msg, req.MessageID), nil
+		}
+		return next(p, req)
+	}
+}
+
+// routeCommand находит команду из сообщения. Обычные сообщения, команды другим ботам
+// и неизвестные команды дальше не передаются.
+func routeCommand(next Handler) Handler {
+	return func(p *Processor, req *Request) (*Response, error) {
+		req.Command = p.messageCommand(req.Text)
+		if req.Command == nil {
+			return nil, nil
+		}
+		log.Printf("[INFO] got new command '%s' from '%s' in '%s'", req.Text, req.User.Username, req.Chat.Title)
+		return next(p, req)
+	}
+}
+
+// deleteCommandMessage удаляет сообщение с командой перед ответом, если бот отвечает не на него
+// и это не выключено настройкой чата DeleteCommands.
+func deleteCommandMessage(next Handler) Handler {
+	return func(p *Processor, req *Request) (*Response, error) {
+		response, err := next(p, req)
+		if err != nil || response == nil || !req.Settings.DeleteCommands || response.repliesTo(req.MessageID) {
+			return response, err
+		}
+		response.actions = append([]action{deleteMessageAction{}}, response.actions...)
+		return response, nil
+	}
+}
+
+// execCommand выполняет команду: разбирает аргументы и вызывает её Executor.
+func execCommand(p *Processor, req *Request) (*Response, error) {
+	cmd := req.Command
+	var (
+		response *Response
+		err      error
+	)
+	params, argsErr := cmd.parseArgs(req.Text)
+	if docCmd, ok := cmd.Executor.(DocumentExecutor); ok && req.Document != nil {
+		response, err = docCmd.ExecDocument(p, req.Text, req.Document, req.User, req.Chat, req.MessageID)
+	} else if argsErr != nil {
+		response = argsErrorResponse(req.Locale, cmd, argsErr, req.MessageID)
+	} else {
+		response, err = cmd.Executor.Exec(p, req.Text, params, req.User, req.Chat, req.UserStats, req.MessageID)
+	}
+	if err != nil {
+		return nil, e.Wrap(fmt.Sprintf("can't select command from message: %s", req.Text), err)
+	}
+	if len(response.actions) == 0 {
+		log.Printf("Message: \"%s\" - do nothing", req.Text)
+	}
+	return response, nil
+}
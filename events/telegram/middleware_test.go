@@ -0,0 +1,78 @@
+package telegram
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(p *Processor, req *Request) (*Response, error) {
+				calls = append(calls, name+" before")
+				response, err := next(p, req)
+				calls = append(calls, name+" after")
+				return response, err
+			}
+		}
+	}
+	handler := chain(func(p *Processor, req *Request) (*Response, error) {
+		calls = append(calls, "handler")
+		return nil, nil
+	}, record("first"), record("second"))
+
+	if _, err := handler(nil, &Request{}); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	want := []string{"first before", "second before", "handler", "second after", "first after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("got %v, want %v", calls, want)
+	}
+}
+
+func TestDeleteCommandMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *Response
+		want     []action
+	}{
+		{
+			name:     "reply keeps command",
+			response: textResponse("ok", 10),
+			want:     []action{sendTextAction{text: "ok", replyTo: 10}},
+		},
+		{
+			name:     "message deletes command first",
+			response: textResponse("ok", 0),
+			want:     []action{deleteMessageAction{}, sendTextAction{text: "ok"}},
+		},
+		{
+			name:     "empty response deletes command",
+			response: newResponse(),
+			want:     []action{deleteMessageAction{}},
+		},
+	}
+	for _, tt := range tests {
+		handler := deleteCommandMessage(func(p *Processor, req *Request) (*Response, error) {
+			return tt.response, nil
+		})
+		response, err := handler(nil, &Request{MessageID: 10, Settings: defaultChatSettings(0)})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(response.actions, tt.want) {
+			t.Errorf("%s: got %#v, want %#v", tt.name, response.actions, tt.want)
+		}
+	}
+
+	settings := defaultChatSettings(0)
+	settings.DeleteCommands = false
+	handler := deleteCommandMessage(func(p *Processor, req *Request) (*Response, error) {
+		return textResponse("ok", 0), nil
+	})
+	response, _ := handler(nil, &Request{MessageID: 10, Settings: settings})
+	if want := []action{sendTextAction{text: "ok"}}; !reflect.DeepEqual(response.actions, want) {
+		t.Errorf("deletion disabled: got %#v, want %#v", response.actions, want)
+	}
+}
@@ -0,0 +1,181 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/lib/i18n"
+	"time"
+)
+
+const (
+	// adminCacheTTL через сколько список админов чата скачивается заново, даже если
+	// обновлений chat_member не было (они не приходят, если бот не админ в чате).
+	adminCacheTTL = 10 * time.Minute
+)
+
+// Role роль пользователя в чате, которую админы группы выдают командой /grant.
+type Role string
+
+const (
+	AuctioneerRole Role = "auctioneer"
+)
+
+// roles все роли с описанием для /roles, в порядке показа.
+var roles = []struct {
+	role Role
+	// description ключ описания роли.
+	description string
+}{
+	{AuctioneerRole, msgRoleAuctioneer},
+}
+
+// roleNames возвращает названия всех ролей.
+func roleNames() []string {
+	names := make([]string, 0, len(roles))
+	for _, r := range roles {
+		names = append(names, string(r.role))
+	}
+	return names
+}
+
+// adminList список админов чата и время, когда он был скачан.
+type adminList struct {
+	admins    []telegram.User
+	fetchedAt time.Time
+}
+
+// adminCache хранит списки админов чатов, чтобы не запрашивать getChatAdministrators на каждую команду.
+type adminCache struct {
+	ttl time.Duration
+
+	mu    sync.Mutex
+	chats map[int]*adminList
+}
+
+// newAdminCache создаёт кэш админов, списки в котором устаревают через ttl.
+func newAdminCache(ttl time.Duration) *adminCache {
+	return &adminCache{ttl: ttl, chats: make(map[int]*adminList)}
+}
+
+// Get возвращает админов чата из кэша или скачивает их через fetch, если списка нет или он устарел.
+func (c *adminCache) Get(chatID int, fetch func(chatID int) ([]telegram.User, error)) ([]telegram.User, error) {
+	c.mu.Lock()
+	list := c.chats[chatID]
+	c.mu.Unlock()
+	if list != nil && time.Since(list.fetchedAt) < c.ttl {
+		return list.admins, nil
+	}
+
+	admins, err := fetch(chatID)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.chats[chatID] = &adminList{admins: admins, fetchedAt: time.Now()}
+	c.mu.Unlock()
+	return admins, nil
+}
+
+// Invalidate удаляет список админов чата, например когда кого-то назначили админом.
+func (c *adminCache) Invalidate(chatID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.chats, chatID)
+}
+
+// chatAdmins возвращает админов чата.
+func (p *Processor) chatAdmins(chatID int) ([]telegram.User, error) {
+	return p.admins.Get(chatID, p.tg.ChatAdministrators)
+}
+
+// isChatAdmin определяет является ли пользователь админом в чате.
+func (p *Processor) isChatAdmin(user *telegram.User, chatID int) bool {
+	admins, err := p.chatAdmins(chatID)
+	if err != nil {
+		log.Printf("can't get admins in chat #%d: %v", chatID, err)
+	}
+	for _, admin := range admins {
+		if user.ID == admin.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRole показывает, выдана ли пользователю роль в чате.
+func (p *Processor) hasRole(user *telegram.User, chatID int, role Role) (bool, error) {
+	userRoles, err := p.storage.UserRoles(context.Background(), chatID, user.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, r := range userRoles {
+		if r == string(role) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// permitted проверяет, может ли пользователь выполнить команду. changes - команда вызвана
+// с аргументами или файлом, то есть меняет настройки. Админам бота можно всё.
+func (p *Processor) permitted(cmd *Command, user *telegram.User, chatID int, changes bool) (bool, error) {
+	if p.isAdmin(user.ID) {
+		return true, nil
+	}
+	switch cmd.Permission {
+	case Everyone:
+		return true, nil
+	case BotAdmin:
+		return false, nil
+	case ChatAdmin:
+		if !changes {
+			return true, nil
+		}
+	}
+
+	if p.isChatAdmin(user, chatID) {
+		return true, nil
+	}
+	if cmd.Role == "" {
+		return false, nil
+	}
+	return p.hasRole(user, chatID, cmd.Role)
+}
+
+// forbiddenMessage возвращает сообщение о том, кому доступна команда.
+func forbiddenMessage(lang *i18n.Locale, cmd *Command) string {
+	var message string
+	switch cmd.Permission {
+	case BotAdmin:
+		return lang.Text(msgErrorForbidden)
+	case ChatAdmin:
+		message = lang.Text(msgForbiddenChange, cmd.Name)
+	default:
+		message = lang.Text(msgForbiddenCommand, cmd.Name)
+	}
+	if cmd.Role != "" {
+		message += lang.Text(msgForbiddenRole, cmd.Role)
+	}
+	return message
+}
+
+// checkPermission не даёт выполнить команду пользователю без прав, указанных в Command.Permission.
+func checkPermission(next Handler) Handler {
+	return func(p *Processor, req *Request) (*Response, error) {
+		changes := req.Document != nil || len(strings.Fields(req.Text)) > 1
+		ok, err := p.permitted(req.Command, req.User, req.Chat.ID, changes)
+		if err != nil {
+			return nil, e.Wrap(fmt.Sprintf("can't check permission for %s", req.Command.Name), err)
+		}
+		if !ok {
+			log.Printf("[INFO] '%s' is not permitted to '%s' in '%s'", req.Command.Name, req.User.Username, req.Chat.Title)
+			return textResponse(forbiddenMessage(req.Locale, req.Command), req.MessageID), nil
+		}
+		return next(p, req)
+	}
+}
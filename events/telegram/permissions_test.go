@@ -0,0 +1,143 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"tg_ics_useful_bot/clients/telegram"
+	"tg_ics_useful_bot/storage"
+	"time"
+)
+
+func TestAdminCache(t *testing.T) {
+	calls := 0
+	fetch := func(chatID int) ([]telegram.User, error) {
+		calls++
+		return []telegram.User{{ID: chatID}}, nil
+	}
+
+	cache := newAdminCache(time.Hour)
+	for i := 0; i < 3; i++ {
+		admins, err := cache.Get(-100, fetch)
+		if err != nil || len(admins) != 1 || admins[0].ID != -100 {
+			t.Fatalf("Get: got %v, %v", admins, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("admins fetched %d times, want 1", calls)
+	}
+
+	cache.Invalidate(-100)
+	if _, err := cache.Get(-100, fetch); err != nil || calls != 2 {
+		t.Errorf("after Invalidate: fetched %d times, want 2 (err %v)", calls, err)
+	}
+
+	expired := newAdminCache(0)
+	expired.Get(-100, fetch)
+	expired.Get(-100, fetch)
+	if calls != 4 {
+		t.Errorf("expired cache: fetched %d times, want 4", calls)
+	}
+}
+
+func TestForbiddenMessage(t *testing.T) {
+	if got := forbiddenMessage(ruLocale, commandByName(ChangeDickCmd)); got != ruLocale.Text(msgErrorForbidden) {
+		t.Errorf("bot admin command: got %q", got)
+	}
+	if got := forbiddenMessage(ruLocale, commandByName(TimezoneCmd)); !strings.Contains(got, TimezoneCmd) {
+		t.Errorf("chat admin command: got %q", got)
+	}
+	if got := forbiddenMessage(ruLocale, commandByName(StartAuctionCmd)); !strings.Contains(got, string(AuctioneerRole)) {
+		t.Errorf("command with role: got %q, want role %s mentioned", got, AuctioneerRole)
+	}
+}
+
+func TestCommandRoles(t *testing.T) {
+	known := make(map[Role]bool)
+	for _, name := range roleNames() {
+		known[Role(name)] = true
+	}
+	for _, cmd := range allCommands {
+		if cmd.Role != "" && !known[cmd.Role] {
+			t.Errorf("%s: unknown role %q", cmd.Name, cmd.Role)
+		}
+		if cmd.Role != "" && cmd.Permission != ChatAdmin && cmd.Permission != ChatAdminOnly {
+			t.Errorf("%s: role has no effect with permission %d", cmd.Name, cmd.Permission)
+		}
+	}
+}
+
+func TestCheckPermission(t *testing.T) {
+	const (
+		chatID     = -100
+		botAdmin   = 1
+		chatAdmin  = 2
+		auctioneer = 3
+		member     = 4
+	)
+	s := newFakeStorage()
+	s.roles = []*storage.DBRole{{ChatID: chatID, TgID: auctioneer, Role: string(AuctioneerRole)}}
+	p := &Processor{
+		tg:      telegram.New("", "token", []int{botAdmin}),
+		storage: s,
+		admins:  newAdminCache(time.Hour),
+	}
+	// список админов уже в кэше, getChatAdministrators не вызывается
+	p.admins.Get(chatID, func(chatID int) ([]telegram.User, error) {
+		return []telegram.User{{ID: chatAdmin}}, nil
+	})
+
+	tests := []struct {
+		user int
+		text string
+		want bool
+	}{
+		{botAdmin, ChangeDickCmd + " 10", true},
+		{chatAdmin, ChangeDickCmd + " 10", false},
+		{botAdmin, FinishAuctionCmd, true},
+		{chatAdmin, FinishAuctionCmd, false},
+		{auctioneer, FinishAuctionCmd, false},
+
+		{chatAdmin, StartAuctionCmd, true},
+		{auctioneer, StartAuctionCmd, true},
+		{member, StartAuctionCmd, false},
+
+		{member, TimezoneCmd, true},
+		{member, TimezoneCmd + " Asia/Tokyo", false},
+		{auctioneer, TimezoneCmd + " Asia/Tokyo", false},
+		{chatAdmin, TimezoneCmd + " Asia/Tokyo", true},
+		{botAdmin, TimezoneCmd + " Asia/Tokyo", true},
+
+		{member, HelpCmd + " all", true},
+	}
+	for _, tt := range tests {
+		called := false
+		next := func(p *Processor, req *Request) (*Response, error) {
+			called = true
+			return nil, nil
+		}
+		cmd := commandByName(strings.Fields(tt.text)[0])
+		req := &Request{
+			Text:      tt.text,
+			Chat:      &telegram.Chat{ID: chatID},
+			User:      &telegram.User{ID: tt.user},
+			MessageID: 7,
+			Locale:    ruLocale,
+			Command:   cmd,
+		}
+		response, err := checkPermission(next)(p, req)
+		if err != nil {
+			t.Fatalf("user %d %q: %v", tt.user, tt.text, err)
+		}
+		if called != tt.want {
+			t.Errorf("user %d %q: permitted %v, want %v", tt.user, tt.text, called, tt.want)
+		}
+		if tt.want {
+			continue
+		}
+		// об отказе бот отвечает на сообщение с командой, кому она доступна
+		want := sendTextAction{text: forbiddenMessage(ruLocale, cmd), replyTo: req.MessageID}
+		if response == nil || len(response.actions) != 1 || response.actions[0] != want {
+			t.Errorf("user %d %q: got response %+v, want %+v", tt.user, tt.text, response, want)
+		}
+	}
+}
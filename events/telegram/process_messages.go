@@ -2,20 +2,31 @@ package telegram
 
 import (
 	"errors"
+	"fmt"
 	"tg_ics_useful_bot/clients/telegram"
 	"tg_ics_useful_bot/events"
 	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/lib/schedule"
 	"tg_ics_useful_bot/storage"
 )
 
 type Processor struct {
-	tg      *telegram.Client
-	offset  int
-	storage storage.Storage
+	tg        *telegram.Client
+	offset    int
+	storage   storage.Storage
+	schedules *schedule.Cache
+	// username имя бота без @, по нему определяются адресованные боту команды.
+	username string
+	// admins кэш админов групп, сбрасывается по обновлениям chat_member.
+	admins *adminCache
+	// handler обработчик сообщений: execCommand, обёрнутый в allMiddlewares.
+	handler Handler
 }
 
 type Meta struct {
-	MessageID int
+	MessageID  int
+	CallbackID string
+	Document   *telegram.Document
 
 	TgID      int
 	Username  string
@@ -28,6 +39,16 @@ type Meta struct {
 	ChatType            string
 	ChatTitle           string
 	ChatActiveUsernames []string
+
+	// OldMember и NewMember участник чата до и после изменения статуса,
+	// только для events.ChatMember и events.MyChatMember.
+	OldMember telegram.ChatMember
+	NewMember telegram.ChatMember
+
+	// NewChatMembers и LeftChatMember участники, вошедшие в чат или вышедшие из него,
+	// только для служебных сообщений.
+	NewChatMembers []telegram.User
+	LeftChatMember *telegram.User
 }
 
 var (
@@ -35,11 +56,20 @@ var (
 	ErrUnknownMetaType  = errors.New("unknown meta type")
 )
 
-func New(client *telegram.Client, storage storage.Storage) *Processor {
-	return &Processor{
-		tg:      client,
-		storage: storage,
+// New создаёт обработчик событий, узнавая у Telegram имя бота.
+func New(client *telegram.Client, storage storage.Storage) (*Processor, error) {
+	me, err := client.GetMe()
+	if err != nil {
+		return nil, e.Wrap("can't get bot username", err)
 	}
+	return &Processor{
+		tg:        client,
+		storage:   storage,
+		schedules: schedule.NewCache(scheduleCacheTTL),
+		username:  me.Username,
+		admins:    newAdminCache(adminCacheTTL),
+		handler:   chain(execCommand, allMiddlewares...),
+	}, nil
 }
 
 func (p *Processor) Fetch(limit int) ([]events.Event, error) {
@@ -67,6 +97,12 @@ func (p *Processor) Process(event events.Event) error {
 	switch event.Type {
 	case events.Message:
 		return p.processMessage(event)
+	case events.CallbackQuery:
+		return p.processCallbackQuery(event)
+	case events.ChatMember:
+		return p.processChatMember(event)
+	case events.MyChatMember:
+		return p.processMyChatMember(event)
 	default:
 		return e.Wrap("can't process message", ErrUnknownEventType)
 	}
@@ -80,32 +116,71 @@ func (p *Processor) processMessage(event events.Event) error {
 
 	messageID := meta.MessageID
 
-	user := &telegram.User{
-		ID:        meta.TgID,
-		IsBot:     meta.IsBot,
-		FirstName: meta.FirstName,
-		LastName:  meta.LastName,
-		Username:  meta.Username,
-		IsPremium: meta.IsPremium,
+	user, chat := meta.user(), meta.chat()
+	if chat.Type == "private" && !p.isAdmin(user.ID) {
+		return nil
+	}
+
+	if len(meta.NewChatMembers) > 0 || meta.LeftChatMember != nil {
+		return p.processMembersMessage(meta)
 	}
 
-	chat := &telegram.Chat{
-		ID:              meta.ChatID,
-		Type:            meta.ChatType,
-		Title:           meta.ChatTitle,
-		ActiveUsernames: meta.ChatActiveUsernames,
+	err = safeCall(func() error { return p.doCmd(event.Text, chat, user, messageID, meta.Document) })
+	if err != nil {
+		// об ошибках в обычных сообщениях только пишем в лог, иначе при проблемах с базой
+		// бот отвечал бы на каждое сообщение в чате
+		errorID := ""
+		if p.messageCommand(event.Text) != nil {
+			errorID = p.replyError(err, chat.ID, "")
+		} else {
+			errorID = reportError(err)
+		}
+		return e.Wrap(fmt.Sprintf("can't process message (error #%s)", errorID), err)
 	}
+
+	return nil
+}
+
+func (p *Processor) processCallbackQuery(event events.Event) error {
+	meta, err := meta(event)
+	if err != nil {
+		return e.Wrap("can't process callback query", err)
+	}
+
+	user, chat := meta.user(), meta.chat()
 	if chat.Type == "private" && !p.isAdmin(user.ID) {
 		return nil
 	}
 
-	if err = p.doCmd(event.Text, chat, user, messageID); err != nil {
-		return e.Wrap("can't process message", err)
+	err = safeCall(func() error { return p.doCallback(event.Text, chat, user, meta.MessageID, meta.CallbackID) })
+	if err != nil {
+		errorID := p.replyError(err, chat.ID, meta.CallbackID)
+		return e.Wrap(fmt.Sprintf("can't process callback query (error #%s)", errorID), err)
 	}
 
 	return nil
 }
 
+func (m Meta) user() *telegram.User {
+	return &telegram.User{
+		ID:        m.TgID,
+		IsBot:     m.IsBot,
+		FirstName: m.FirstName,
+		LastName:  m.LastName,
+		Username:  m.Username,
+		IsPremium: m.IsPremium,
+	}
+}
+
+func (m Meta) chat() *telegram.Chat {
+	return &telegram.Chat{
+		ID:              m.ChatID,
+		Type:            m.ChatType,
+		Title:           m.ChatTitle,
+		ActiveUsernames: m.ChatActiveUsernames,
+	}
+}
+
 func meta(event events.Event) (Meta, error) {
 	res, ok := event.Meta.(Meta)
 	if !ok {
@@ -126,6 +201,7 @@ func event(upd telegram.Update) events.Event {
 	if updType == events.Message {
 		res.Meta = Meta{
 			MessageID: upd.Message.ID,
+			Document:  upd.Message.Document,
 
 			TgID:      upd.Message.From.ID,
 			FirstName: upd.Message.From.FirstName,
@@ -138,17 +214,75 @@ func event(upd telegram.Update) events.Event {
 			ChatType:            upd.Message.Chat.Type,
 			ChatTitle:           upd.Message.Chat.Title,
 			ChatActiveUsernames: upd.Message.Chat.ActiveUsernames,
+
+			NewChatMembers: upd.Message.NewChatMembers,
+			LeftChatMember: upd.Message.LeftChatMember,
 		}
 	}
 
+	if updType == events.CallbackQuery {
+		res.Meta = Meta{
+			MessageID:  upd.CallbackQuery.Message.ID,
+			CallbackID: upd.CallbackQuery.ID,
+
+			TgID:      upd.CallbackQuery.From.ID,
+			FirstName: upd.CallbackQuery.From.FirstName,
+			LastName:  upd.CallbackQuery.From.LastName,
+			Username:  upd.CallbackQuery.From.Username,
+			IsBot:     upd.CallbackQuery.From.IsBot,
+			IsPremium: upd.CallbackQuery.From.IsPremium,
+
+			ChatID:              upd.CallbackQuery.Message.Chat.ID,
+			ChatType:            upd.CallbackQuery.Message.Chat.Type,
+			ChatTitle:           upd.CallbackQuery.Message.Chat.Title,
+			ChatActiveUsernames: upd.CallbackQuery.Message.Chat.ActiveUsernames,
+		}
+	}
+
+	if updType == events.ChatMember {
+		res.Meta = chatMemberMeta(upd.ChatMember)
+	}
+
+	if updType == events.MyChatMember {
+		res.Meta = chatMemberMeta(upd.MyChatMember)
+	}
+
 	return res
 }
 
+// chatMemberMeta возвращает данные изменения статуса участника чата.
+func chatMemberMeta(upd *telegram.ChatMemberUpdated) Meta {
+	member := upd.NewChatMember.User
+	return Meta{
+		TgID:      member.ID,
+		FirstName: member.FirstName,
+		LastName:  member.LastName,
+		Username:  member.Username,
+		IsBot:     member.IsBot,
+		IsPremium: member.IsPremium,
+
+		ChatID:    upd.Chat.ID,
+		ChatType:  upd.Chat.Type,
+		ChatTitle: upd.Chat.Title,
+
+		OldMember: upd.OldChatMember,
+		NewMember: upd.NewChatMember,
+	}
+}
+
 func fetchText(upd telegram.Update) string {
+	if upd.CallbackQuery != nil {
+		return upd.CallbackQuery.Data
+	}
+
 	if upd.Message == nil {
 		return ""
 	}
 
+	if upd.Message.Text == "" {
+		// команда к файлу приходит в подписи
+		return upd.Message.Caption
+	}
 	return upd.Message.Text
 }
 
@@ -156,6 +290,15 @@ func fetchType(upd telegram.Update) events.Type {
 	if upd.Message != nil {
 		return events.Message
 	}
+	if upd.CallbackQuery != nil {
+		return events.CallbackQuery
+	}
+	if upd.ChatMember != nil {
+		return events.ChatMember
+	}
+	if upd.MyChatMember != nil {
+		return events.MyChatMember
+	}
 	return events.Unknown
 }
 
@@ -0,0 +1,151 @@
+package telegram
+
+import (
+	"context"
+	"tg_ics_useful_bot/storage"
+)
+
+// fakeStorage хранилище в памяти для тестов. Методы, которые тестам не нужны, не реализованы
+// и паникуют через встроенный nil storage.Storage.
+type fakeStorage struct {
+	storage.Storage
+	calendars map[int]*storage.DBCalendar
+	users     []*storage.DBUser
+	dialogs   []*storage.DBDialogState
+	replies   []*storage.DBAutoReply
+	roles     []*storage.DBRole
+	cooldowns []*storage.DBCooldown
+	// commandCooldowns ограничения команд, настроенные через /cooldown.
+	commandCooldowns []*storage.DBCommandCooldown
+	notifies         map[int]*storage.DBScheduleNotify
+}
+
+func newFakeStorage() *fakeStorage {
+	return &fakeStorage{
+		calendars: make(map[int]*storage.DBCalendar),
+		notifies:  make(map[int]*storage.DBScheduleNotify),
+	}
+}
+
+func (s *fakeStorage) GetChatSettings(ctx context.Context, chatID int) (*storage.DBChatSettings, error) {
+	return nil, storage.ErrChatNotExist
+}
+
+func (s *fakeStorage) GetChatTimezone(ctx context.Context, chatID int) (string, error) {
+	return "", storage.ErrChatNotExist
+}
+
+func (s *fakeStorage) GetCalendar(ctx context.Context, chatID int) (*storage.DBCalendar, error) {
+	c, ok := s.calendars[chatID]
+	if !ok {
+		return nil, storage.ErrCalendarNotExist
+	}
+	return c, nil
+}
+
+func (s *fakeStorage) SaveCalendar(ctx context.Context, c *storage.DBCalendar) error {
+	s.calendars[c.ChatID] = c
+	return nil
+}
+
+func (s *fakeStorage) SaveScheduleNotify(ctx context.Context, n *storage.DBScheduleNotify) error {
+	s.notifies[n.ChatID] = n
+	return nil
+}
+
+func (s *fakeStorage) UserRoles(ctx context.Context, chatID, tgID int) ([]string, error) {
+	var roles []string
+	for _, r := range s.roles {
+		if r.ChatID == chatID && r.TgID == tgID {
+			roles = append(roles, r.Role)
+		}
+	}
+	return roles, nil
+}
+
+func (s *fakeStorage) ChatCommandCooldowns(ctx context.Context, chatID int) ([]*storage.DBCommandCooldown, error) {
+	var cooldowns []*storage.DBCommandCooldown
+	for _, c := range s.commandCooldowns {
+		if c.ChatID == chatID {
+			cooldowns = append(cooldowns, c)
+		}
+	}
+	return cooldowns, nil
+}
+
+func (s *fakeStorage) SaveCommandCooldown(ctx context.Context, c *storage.DBCommandCooldown) error {
+	s.commandCooldowns = append(s.commandCooldowns, c)
+	return nil
+}
+
+func (s *fakeStorage) GetCooldown(ctx context.Context, chatID, tgID int, command string) (*storage.DBCooldown, error) {
+	for _, c := range s.cooldowns {
+		if c.ChatID == chatID && c.TgID == tgID && c.Command == command {
+			return c, nil
+		}
+	}
+	return nil, storage.ErrCooldownNotExist
+}
+
+func (s *fakeStorage) SaveCooldown(ctx context.Context, c *storage.DBCooldown) error {
+	if old, err := s.GetCooldown(ctx, c.ChatID, c.TgID, c.Command); err == nil {
+		*old = *c
+		return nil
+	}
+	s.cooldowns = append(s.cooldowns, c)
+	return nil
+}
+
+func (s *fakeStorage) GetUser(ctx context.Context, tgID, chatID int) (*storage.DBUser, error) {
+	for _, u := range s.users {
+		if u.TgID == tgID && u.ChatID == chatID {
+			return u, nil
+		}
+	}
+	return nil, storage.ErrUserNotExist
+}
+
+func (s *fakeStorage) GetUserStats(ctx context.Context, u *storage.DBUser) (*storage.DBUserStat, error) {
+	return &storage.DBUserStat{ID: u.UserStatId}, nil
+}
+
+func (s *fakeStorage) UpdateUserStats(ctx context.Context, u *storage.DBUserStat) error {
+	return nil
+}
+
+func (s *fakeStorage) GetDialogState(ctx context.Context, chatID, tgID int) (*storage.DBDialogState, error) {
+	for _, d := range s.dialogs {
+		if d.ChatID == chatID && d.TgID == tgID {
+			return d, nil
+		}
+	}
+	return nil, storage.ErrDialogNotExist
+}
+
+func (s *fakeStorage) SaveDialogState(ctx context.Context, state *storage.DBDialogState) error {
+	if err := s.DeleteDialogState(ctx, state.ChatID, state.TgID); err != nil {
+		return err
+	}
+	s.dialogs = append(s.dialogs, state)
+	return nil
+}
+
+func (s *fakeStorage) DeleteDialogState(ctx context.Context, chatID, tgID int) error {
+	for i, d := range s.dialogs {
+		if d.ChatID == chatID && d.TgID == tgID {
+			s.dialogs = append(s.dialogs[:i], s.dialogs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (s *fakeStorage) ChatAutoReplies(ctx context.Context, chatID int) ([]*storage.DBAutoReply, error) {
+	var replies []*storage.DBAutoReply
+	for _, r := range s.replies {
+		if r.ChatID == chatID {
+			replies = append(replies, r)
+		}
+	}
+	return replies, nil
+}
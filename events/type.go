@@ -13,6 +13,9 @@ type Type int
 const (
 	Unknown Type = iota
 	Message
+	CallbackQuery
+	ChatMember
+	MyChatMember
 )
 
 type Event struct {
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"tg_ics_useful_bot/lib/e"
+	"tg_ics_useful_bot/lib/export"
+	"tg_ics_useful_bot/storage/sqlite"
+	"time"
+)
+
+const exportCmd = "export"
+
+// runExport выгружает домашнее задание чата в файл, чтобы делать резервные копии без запуска бота.
+// Использование: tg_ics_useful_bot export -chat {chat_id} [-format ics|csv|md] [-out file]
+func runExport(args []string) error {
+	fs := flag.NewFlagSet(exportCmd, flag.ExitOnError)
+	chatID := fs.Int("chat", 0, "id of telegram chat to export")
+	formatName := fs.String("format", string(export.CSV), "export format: ics, csv or md")
+	out := fs.String("out", "", "output file (stdout by default)")
+	dbPath := fs.String("db", storageSQLitePath, "path to sqlite storage")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *chatID == 0 {
+		return errors.New("chat id is not specified")
+	}
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	s, err := sqlite.New(*dbPath)
+	if err != nil {
+		return e.Wrap("can't open storage", err)
+	}
+	homeworks, err := s.GetAllHomework(context.Background(), *chatID)
+	if err != nil {
+		return err
+	}
+	data, err := export.Homework(format, homeworks, time.Now(), time.Local, export.RussianLabels)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return e.Wrap("can't create output file", err)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,310 @@
+package cmdargs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Kind тип значения аргумента.
+type Kind int
+
+const (
+	// IntKind целое число.
+	IntKind Kind = iota
+	// DurationKind длительность в формате Go (10m, 1h30m), число без единиц - минуты.
+	DurationKind
+	// UsernameKind имя пользователя, с @ или без.
+	UsernameKind
+	// MentionKind упоминание пользователя, обязательно с @.
+	MentionKind
+	// StringKind одно слово или строка в кавычках.
+	StringKind
+	// ChoiceKind одно из заранее заданных слов.
+	ChoiceKind
+	// RestKind весь остаток строки как есть.
+	RestKind
+)
+
+var (
+	ErrMissing = errors.New("missing argument")
+	ErrInvalid = errors.New("invalid argument")
+	ErrTooMany = errors.New("too many arguments")
+)
+
+// usernamePattern имя пользователя Telegram без @.
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{3,32}$`)
+
+// Error ошибка разбора аргументов команды.
+type Error struct {
+	// Arg аргумент, который не удалось разобрать, пустой для ErrTooMany.
+	Arg Arg
+	// Value значение, которое не удалось разобрать, или лишние аргументы.
+	Value string
+	Err   error
+}
+
+func (e *Error) Error() string {
+	if e.Arg.name == "" {
+		return fmt.Sprintf("%v: %q", e.Err, e.Value)
+	}
+	return fmt.Sprintf("%v %s: %q", e.Err, e.Arg.name, e.Value)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Arg описание одного аргумента команды.
+type Arg struct {
+	name       string
+	kind       Kind
+	optional   bool
+	def        string
+	hasDefault bool
+	choices    []string
+}
+
+func Int(name string) Arg {
+	return Arg{name: name, kind: IntKind}
+}
+
+func Duration(name string) Arg {
+	return Arg{name: name, kind: DurationKind}
+}
+
+func Username(name string) Arg {
+	return Arg{name: name, kind: UsernameKind}
+}
+
+func Mention(name string) Arg {
+	return Arg{name: name, kind: MentionKind}
+}
+
+func String(name string) Arg {
+	return Arg{name: name, kind: StringKind}
+}
+
+// Choice аргумент, принимающий одно из слов choices без учёта регистра.
+func Choice(name string, choices ...string) Arg {
+	return Arg{name: name, kind: ChoiceKind, choices: choices}
+}
+
+// Rest аргумент, забирающий весь остаток строки. Должен быть последним.
+func Rest(name string) Arg {
+	return Arg{name: name, kind: RestKind}
+}
+
+// Optional делает аргумент необязательным. Если очередное слово не подходит
+// необязательному аргументу, оно достаётся следующему.
+func (a Arg) Optional() Arg {
+	a.optional = true
+	return a
+}
+
+// Default делает аргумент необязательным со значением value по умолчанию.
+func (a Arg) Default(value string) Arg {
+	a.optional, a.def, a.hasDefault = true, value, true
+	return a
+}
+
+func (a Arg) Name() string {
+	return a.name
+}
+
+func (a Arg) Kind() Kind {
+	return a.kind
+}
+
+func (a Arg) Choices() []string {
+	return a.choices
+}
+
+// usage возвращает аргумент для справки: {name} для обязательного, [name] для необязательного.
+func (a Arg) usage() string {
+	text := a.name
+	switch a.kind {
+	case MentionKind:
+		text = "@" + a.name
+	case ChoiceKind:
+		text = strings.Join(a.choices, "|")
+	}
+	if a.optional {
+		return "[" + text + "]"
+	}
+	return "{" + text + "}"
+}
+
+// parse разбирает значение аргумента.
+func (a Arg) parse(value string) (any, error) {
+	switch a.kind {
+	case IntKind:
+		return strconv.Atoi(value)
+	case DurationKind:
+		if minutes, err := strconv.Atoi(value); err == nil {
+			return time.Duration(minutes) * time.Minute, nil
+		}
+		return time.ParseDuration(value)
+	case UsernameKind, MentionKind:
+		name, mention := strings.CutPrefix(value, "@")
+		if (a.kind == MentionKind && !mention) || !usernamePattern.MatchString(name) {
+			return nil, ErrInvalid
+		}
+		return name, nil
+	case ChoiceKind:
+		for _, c := range a.choices {
+			if strings.EqualFold(c, value) {
+				return c, nil
+			}
+		}
+		return nil, ErrInvalid
+	}
+	return value, nil
+}
+
+// Spec список аргументов команды по порядку.
+type Spec []Arg
+
+// Usage возвращает аргументы для справки, например {chat_id} {message}.
+func (s Spec) Usage() string {
+	parts := make([]string, 0, len(s))
+	for _, a := range s {
+		parts = append(parts, a.usage())
+	}
+	return strings.Join(parts, " ")
+}
+
+// Parse разбирает аргументы команды (текст после неё) по описанию.
+// Возвращает *Error с ErrMissing, ErrInvalid или ErrTooMany, если текст не подходит.
+func (s Spec) Parse(text string) (Values, error) {
+	values := Values{values: make(map[string]any, len(s))}
+	tokens := tokenize(text)
+
+	i := 0
+	// rejected первый необязательный аргумент, которому не подошло слово i:
+	// если слово никому не достанется, ошибка будет в нём, а не в лишних аргументах
+	var rejected *Error
+	rejectedAt := -1
+	for _, a := range s {
+		if a.kind == RestKind && i < len(tokens) {
+			values.values[a.name] = strings.TrimSpace(text[tokens[i].start:])
+			i = len(tokens)
+			continue
+		}
+		if i < len(tokens) {
+			v, err := a.parse(tokens[i].value)
+			if err == nil {
+				values.values[a.name] = v
+				i++
+				continue
+			}
+			if !a.optional {
+				return values, &Error{Arg: a, Value: tokens[i].value, Err: ErrInvalid}
+			}
+			if rejectedAt != i {
+				rejected, rejectedAt = &Error{Arg: a, Value: tokens[i].value, Err: ErrInvalid}, i
+			}
+		} else if !a.optional {
+			return values, &Error{Arg: a, Err: ErrMissing}
+		}
+
+		if a.hasDefault {
+			v, err := a.parse(a.def)
+			if err != nil {
+				return values, &Error{Arg: a, Value: a.def, Err: ErrInvalid}
+			}
+			values.values[a.name] = v
+		}
+	}
+
+	if i < len(tokens) {
+		if rejectedAt == i {
+			return values, rejected
+		}
+		return values, &Error{Value: strings.TrimSpace(text[tokens[i].start:]), Err: ErrTooMany}
+	}
+	return values, nil
+}
+
+// Values разобранные аргументы команды.
+type Values struct {
+	values map[string]any
+}
+
+// Has показывает, был ли задан аргумент (или у него есть значение по умолчанию).
+func (v Values) Has(name string) bool {
+	_, ok := v.values[name]
+	return ok
+}
+
+// Int возвращает значение IntKind аргумента, 0 если его нет.
+func (v Values) Int(name string) int {
+	i, _ := v.values[name].(int)
+	return i
+}
+
+// Duration возвращает значение DurationKind аргумента, 0 если его нет.
+func (v Values) Duration(name string) time.Duration {
+	d, _ := v.values[name].(time.Duration)
+	return d
+}
+
+// String возвращает значение строкового аргумента (имя пользователя без @, слово, остаток строки),
+// пустую строку если его нет.
+func (v Values) String(name string) string {
+	s, _ := v.values[name].(string)
+	return s
+}
+
+// token слово из текста аргументов и его начало в тексте.
+type token struct {
+	value string
+	start int
+}
+
+// tokenize разбивает текст на слова по пробелам. Текст в двойных кавычках или «ёлочках»
+// считается одним словом, \" внутри кавычек - кавычка.
+func tokenize(text string) []token {
+	tokens := make([]token, 0)
+	runes := []rune(text)
+	offset := func(i int) int { return len(string(runes[:i])) }
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+		start := i
+		var closing rune
+		switch runes[i] {
+		case '"':
+			closing = '"'
+		case '«':
+			closing = '»'
+		}
+
+		var b strings.Builder
+		if closing != 0 {
+			i++
+			for i < len(runes) && runes[i] != closing {
+				if runes[i] == '\\' && i+1 < len(runes) && runes[i+1] == closing {
+					i++
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			i++ // закрывающая кавычка
+		} else {
+			for i < len(runes) && !unicode.IsSpace(runes[i]) {
+				b.WriteRune(runes[i])
+				i++
+			}
+		}
+		tokens = append(tokens, token{value: b.String(), start: offset(start)})
+	}
+	return tokens
+}
@@ -0,0 +1,110 @@
+package cmdargs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Parse(t *testing.T) {
+	spec := Spec{Int("chat_id"), Rest("message")}
+	v, err := spec.Parse(" -100123   привет,  мир\nвторая строка")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if v.Int("chat_id") != -100123 || v.String("message") != "привет,  мир\nвторая строка" {
+		t.Errorf("got chat_id=%d message=%q", v.Int("chat_id"), v.String("message"))
+	}
+}
+
+func Test_ParseOptional(t *testing.T) {
+	spec := Spec{Int("number").Optional(), Rest("subject").Optional()}
+	tests := []struct {
+		text    string
+		number  int
+		subject string
+	}{
+		{"", 0, ""},
+		{"10", 10, ""},
+		{"10 Физическая культура", 10, "Физическая культура"},
+		{"Физика", 0, "Физика"},
+	}
+	for _, tt := range tests {
+		v, err := spec.Parse(tt.text)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.text, err)
+			continue
+		}
+		if v.Int("number") != tt.number || v.String("subject") != tt.subject {
+			t.Errorf("Parse(%q): got %d %q, want %d %q", tt.text, v.Int("number"), v.String("subject"), tt.number, tt.subject)
+		}
+	}
+}
+
+func Test_ParseKinds(t *testing.T) {
+	spec := Spec{
+		Mention("target"),
+		Username("user"),
+		Duration("timeout"),
+		Choice("format", "ics", "csv", "md").Default("csv"),
+		String("title").Optional(),
+	}
+	v, err := spec.Parse(`@first_user second_user 1h30m MD "Домашнее \"задание\""`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if v.String("target") != "first_user" || v.String("user") != "second_user" {
+		t.Errorf("got target=%q user=%q", v.String("target"), v.String("user"))
+	}
+	if v.Duration("timeout") != 90*time.Minute {
+		t.Errorf("got timeout=%v", v.Duration("timeout"))
+	}
+	if v.String("format") != "md" || v.String("title") != `Домашнее "задание"` {
+		t.Errorf("got format=%q title=%q", v.String("format"), v.String("title"))
+	}
+
+	v, err = spec.Parse("@first_user second_user 15 «с пробелом»")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if v.Duration("timeout") != 15*time.Minute || v.String("format") != "csv" || v.String("title") != "с пробелом" {
+		t.Errorf("got timeout=%v format=%q title=%q", v.Duration("timeout"), v.String("format"), v.String("title"))
+	}
+}
+
+func Test_ParseErrors(t *testing.T) {
+	spec := Spec{Int("chat_id"), Int("user_id"), Int("value")}
+	tests := []struct {
+		text string
+		err  error
+		arg  string
+	}{
+		{"1", ErrMissing, "user_id"},
+		{"", ErrMissing, "chat_id"},
+		{"1 abc 3", ErrInvalid, "user_id"},
+		{"1 2 3 4", ErrTooMany, ""},
+	}
+	for _, tt := range tests {
+		_, err := spec.Parse(tt.text)
+		var argErr *Error
+		if !errors.Is(err, tt.err) || !errors.As(err, &argErr) || argErr.Arg.Name() != tt.arg {
+			t.Errorf("Parse(%q): got %v, want %v for %q", tt.text, err, tt.err, tt.arg)
+		}
+	}
+
+	_, err := (Spec{Choice("format", "ics", "csv").Default("csv")}).Parse("pdf")
+	var argErr *Error
+	if !errors.Is(err, ErrInvalid) || !errors.As(err, &argErr) || argErr.Arg.Name() != "format" {
+		t.Errorf("unknown choice: got %v, want ErrInvalid for format", err)
+	}
+	if _, err := (Spec{Mention("target")}).Parse("username"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("mention without @: got %v, want ErrInvalid", err)
+	}
+}
+
+func Test_Usage(t *testing.T) {
+	spec := Spec{Mention("username"), Int("number").Optional(), Choice("format", "ics", "csv").Default("csv"), Rest("message")}
+	if got, want := spec.Usage(), "{@username} [number] [ics|csv] {message}"; got != want {
+		t.Errorf("Usage: got %q, want %q", got, want)
+	}
+}
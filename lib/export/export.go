@@ -0,0 +1,167 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"tg_ics_useful_bot/storage"
+	"time"
+	"unicode/utf8"
+)
+
+type Format string
+
+const (
+	ICS      Format = "ics"
+	CSV      Format = "csv"
+	Markdown Format = "md"
+)
+
+const (
+	dateLayout = "02.01.2006 15:04"
+	icsLayout  = "20060102T150405Z"
+
+	// icsLineLength максимальная длина строки iCalendar в байтах (RFC 5545).
+	icsLineLength = 75
+)
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// Labels заголовок и названия столбцов Markdown таблицы на языке чата.
+type Labels struct {
+	Title    string
+	Subject  string
+	Task     string
+	Created  string
+	Deadline string
+}
+
+// RussianLabels подписи Markdown таблицы по умолчанию.
+var RussianLabels = Labels{
+	Title:    "Домашнее задание",
+	Subject:  "Предмет",
+	Task:     "Задание",
+	Created:  "Добавлено",
+	Deadline: "Дедлайн",
+}
+
+// ParseFormat возвращает формат экспорта по его названию.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimPrefix(name, "."))); f {
+	case ICS, CSV, Markdown:
+		return f, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// FileName возвращает имя файла экспорта домашнего задания чата.
+func FileName(format Format, chatID int) string {
+	return fmt.Sprintf("homework_%d.%s", chatID, format)
+}
+
+// Homework формирует файл с домашним заданием в нужном формате.
+// Даты в Markdown выводятся в часовом поясе loc, заголовки берутся из labels.
+func Homework(format Format, homeworks []*storage.DBHomework, now time.Time, loc *time.Location,
+	labels Labels) ([]byte, error) {
+	switch format {
+	case ICS:
+		return homeworkICS(homeworks, now), nil
+	case CSV:
+		return homeworkCSV(homeworks)
+	case Markdown:
+		return homeworkMarkdown(homeworks, loc, labels), nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+// homeworkICS возвращает календарь с событиями для заданий, у которых есть дедлайн.
+func homeworkICS(homeworks []*storage.DBHomework, now time.Time) []byte {
+	var b bytes.Buffer
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//tg_ics_useful_bot//homework//RU")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	for _, hm := range homeworks {
+		if hm.Deadline == nil {
+			continue
+		}
+		writeICSLine(&b, "BEGIN:VEVENT")
+		writeICSLine(&b, fmt.Sprintf("UID:homework-%d@tg_ics_useful_bot", hm.ID))
+		writeICSLine(&b, "DTSTAMP:"+now.UTC().Format(icsLayout))
+		writeICSLine(&b, "DTSTART:"+hm.Deadline.UTC().Format(icsLayout))
+		writeICSLine(&b, "DTEND:"+hm.Deadline.Add(time.Hour).UTC().Format(icsLayout))
+		writeICSLine(&b, "SUMMARY:"+escapeICS(hm.Subject))
+		writeICSLine(&b, "DESCRIPTION:"+escapeICS(hm.Task))
+		writeICSLine(&b, "END:VEVENT")
+	}
+	writeICSLine(&b, "END:VCALENDAR")
+	return b.Bytes()
+}
+
+// writeICSLine записывает строку iCalendar, перенося слишком длинные строки.
+func writeICSLine(b *bytes.Buffer, line string) {
+	limit := icsLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// строка продолжения начинается с пробела, он тоже входит в длину
+		limit = icsLineLength - 1
+	}
+	b.WriteString(line + "\r\n")
+}
+
+// escapeICS экранирует текст для значений iCalendar.
+func escapeICS(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// homeworkCSV возвращает все задания в формате CSV.
+func homeworkCSV(homeworks []*storage.DBHomework) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if err := w.Write([]string{"id", "subject", "task", "created_at", "deadline"}); err != nil {
+		return nil, err
+	}
+	for _, hm := range homeworks {
+		deadline := ""
+		if hm.Deadline != nil {
+			deadline = hm.Deadline.Format(time.RFC3339)
+		}
+		record := []string{strconv.Itoa(hm.ID), hm.Subject, hm.Task, hm.CreatedAT.Format(time.RFC3339), deadline}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return b.Bytes(), w.Error()
+}
+
+// homeworkMarkdown возвращает все задания в виде Markdown таблицы с датами в часовом поясе loc.
+func homeworkMarkdown(homeworks []*storage.DBHomework, loc *time.Location, labels Labels) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n\n", escapeMarkdown(labels.Title))
+	fmt.Fprintf(&b, "| id | %s | %s | %s | %s |\n", escapeMarkdown(labels.Subject), escapeMarkdown(labels.Task),
+		escapeMarkdown(labels.Created), escapeMarkdown(labels.Deadline))
+	b.WriteString("|----|---|---|---|---|\n")
+	for _, hm := range homeworks {
+		deadline := ""
+		if hm.Deadline != nil {
+			deadline = hm.Deadline.In(loc).Format(dateLayout)
+		}
+		fmt.Fprintf(&b, "| %d | %s | %s | %s | %s |\n", hm.ID, escapeMarkdown(hm.Subject), escapeMarkdown(hm.Task),
+			hm.CreatedAT.In(loc).Format(dateLayout), deadline)
+	}
+	return b.Bytes()
+}
+
+// escapeMarkdown экранирует текст для ячейки Markdown таблицы.
+func escapeMarkdown(text string) string {
+	return strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>").Replace(text)
+}
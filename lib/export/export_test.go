@@ -0,0 +1,130 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+	"tg_ics_useful_bot/storage"
+	"time"
+)
+
+func testHomeworks() []*storage.DBHomework {
+	created := time.Date(2024, time.February, 5, 10, 0, 0, 0, time.UTC)
+	deadline := time.Date(2024, time.February, 7, 9, 30, 0, 0, time.UTC)
+	return []*storage.DBHomework{
+		{ID: 1, Subject: "Физика", Task: "задачи 1, 2 и \"3\"\nи конспект", CreatedAT: created, Deadline: &deadline},
+		{ID: 2, Subject: "Алгебра | геометрия", Task: "№5; №6", CreatedAT: created},
+	}
+}
+
+func Test_HomeworkCSV(t *testing.T) {
+	data, err := Homework(CSV, testHomeworks(), time.Now(), time.UTC, RussianLabels)
+	if err != nil {
+		t.Fatalf("Homework: %v", err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("can't read csv: %v\n%s", err, data)
+	}
+	want := [][]string{
+		{"id", "subject", "task", "created_at", "deadline"},
+		{"1", "Физика", "задачи 1, 2 и \"3\"\nи конспект", "2024-02-05T10:00:00Z", "2024-02-07T09:30:00Z"},
+		{"2", "Алгебра | геометрия", "№5; №6", "2024-02-05T10:00:00Z", ""},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("got %q, want %q", records, want)
+	}
+
+	empty, err := Homework(CSV, nil, time.Now(), time.UTC, RussianLabels)
+	if err != nil {
+		t.Fatalf("Homework: %v", err)
+	}
+	if got := string(empty); got != "id,subject,task,created_at,deadline\n" {
+		t.Errorf("empty csv: got %q", got)
+	}
+}
+
+func Test_HomeworkMarkdown(t *testing.T) {
+	data, err := Homework(Markdown, testHomeworks(), time.Now(), time.UTC, RussianLabels)
+	if err != nil {
+		t.Fatalf("Homework: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	want := []string{
+		"| 1 | Физика | задачи 1, 2 и \"3\"<br>и конспект | 05.02.2024 10:00 | 07.02.2024 09:30 |",
+		`| 2 | Алгебра \| геометрия | №5; №6 | 05.02.2024 10:00 |  |`,
+	}
+	if got := lines[len(lines)-2:]; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	empty, _ := Homework(Markdown, nil, time.Now(), time.UTC, RussianLabels)
+	if lines := strings.Split(strings.TrimSpace(string(empty)), "\n"); len(lines) != 4 {
+		t.Errorf("empty markdown must have only the header, got %q", empty)
+	}
+}
+
+func Test_HomeworkMarkdownLocation(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*60*60)
+	// дедлайн 15.03.2025 00:00 по Москве, из базы приходит в UTC
+	deadline := time.Date(2025, time.March, 15, 0, 0, 0, 0, msk).UTC()
+	created := time.Date(2025, time.March, 10, 22, 0, 0, 0, time.UTC)
+	homeworks := []*storage.DBHomework{{ID: 1, Subject: "Физика", Task: "задачи", CreatedAT: created, Deadline: &deadline}}
+
+	data, err := Homework(Markdown, homeworks, time.Now(), msk, RussianLabels)
+	if err != nil {
+		t.Fatalf("Homework: %v", err)
+	}
+	want := "| 1 | Физика | задачи | 11.03.2025 01:00 | 15.03.2025 00:00 |"
+	if !strings.Contains(string(data), want) {
+		t.Errorf("no %q in\n%s", want, data)
+	}
+}
+
+func Test_HomeworkMarkdownLabels(t *testing.T) {
+	labels := Labels{Title: "Homework", Subject: "Subject", Task: "Task", Created: "Added", Deadline: "Deadline"}
+	data, err := Homework(Markdown, nil, time.Now(), time.UTC, labels)
+	if err != nil {
+		t.Fatalf("Homework: %v", err)
+	}
+	lines := strings.Split(string(data), "\n")
+	if lines[0] != "# Homework" || lines[2] != "| id | Subject | Task | Added | Deadline |" {
+		t.Errorf("header is not localized:\n%s", data)
+	}
+}
+
+func Test_HomeworkICS(t *testing.T) {
+	now := time.Date(2024, time.February, 6, 0, 0, 0, 0, time.UTC)
+	data, err := Homework(ICS, testHomeworks(), now, time.UTC, RussianLabels)
+	if err != nil {
+		t.Fatalf("Homework: %v", err)
+	}
+	text := string(data)
+	if n := strings.Count(text, "BEGIN:VEVENT"); n != 1 {
+		t.Errorf("got %d events, want 1 (only homework with deadline)", n)
+	}
+	for _, want := range []string{
+		"DTSTART:20240207T093000Z\r\n",
+		`DESCRIPTION:задачи 1\, 2 и "3"\nи конспект` + "\r\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("no %q in\n%s", want, text)
+		}
+	}
+	for _, line := range strings.Split(text, "\r\n") {
+		if len(line) > icsLineLength {
+			t.Errorf("line longer than %d bytes: %q", icsLineLength, line)
+		}
+	}
+}
+
+func Test_ParseFormat(t *testing.T) {
+	if f, err := ParseFormat(".CSV"); err != nil || f != CSV {
+		t.Errorf("ParseFormat(.CSV) = %q, %v", f, err)
+	}
+	if _, err := ParseFormat("json"); err != ErrUnknownFormat {
+		t.Errorf("ParseFormat(json): got %v, want ErrUnknownFormat", err)
+	}
+}
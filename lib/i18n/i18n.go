@@ -0,0 +1,87 @@
+// Package i18n каталоги сообщений бота на разных языках с формами множественного числа.
+package i18n
+
+import "fmt"
+
+// PluralRule возвращает номер формы множественного числа для n.
+type PluralRule func(n int) int
+
+// RussianPlural формы "1 минута", "2 минуты", "5 минут".
+func RussianPlural(n int) int {
+	if n < 0 {
+		n = -n
+	}
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return 0
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// EnglishPlural формы "1 minute", "2 minutes".
+func EnglishPlural(n int) int {
+	if n == 1 || n == -1 {
+		return 0
+	}
+	return 1
+}
+
+// Messages сообщения по ключам. У сообщения одна форма или по форме на каждый вариант PluralRule.
+type Messages map[string][]string
+
+// Locale каталог сообщений на одном языке.
+type Locale struct {
+	// Code код языка, который хранится в настройках чата.
+	Code string
+	// Name название языка для пользователей.
+	Name string
+	// Rule выбирает форму множественного числа.
+	Rule     PluralRule
+	Messages Messages
+}
+
+// Extend возвращает новый каталог на основе l, в котором messages заменяют сообщения l.
+func (l *Locale) Extend(code, name string, messages Messages) *Locale {
+	merged := make(Messages, len(l.Messages))
+	for key, forms := range l.Messages {
+		merged[key] = forms
+	}
+	for key, forms := range messages {
+		merged[key] = forms
+	}
+	return &Locale{Code: code, Name: name, Rule: l.Rule, Messages: merged}
+}
+
+// Text возвращает сообщение key, отформатированное с args.
+// Если сообщения нет в каталоге, возвращает сам ключ.
+func (l *Locale) Text(key string, args ...any) string {
+	forms := l.Messages[key]
+	if len(forms) == 0 {
+		return key
+	}
+	return format(forms[0], args)
+}
+
+// Plural возвращает форму сообщения key для числа n, отформатированную с args.
+func (l *Locale) Plural(key string, n int, args ...any) string {
+	forms := l.Messages[key]
+	if len(forms) == 0 {
+		return key
+	}
+	i := l.Rule(n)
+	if i >= len(forms) {
+		i = len(forms) - 1
+	}
+	return format(forms[i], args)
+}
+
+// format форматирует сообщение, только если есть аргументы, чтобы не трогать "%" в готовом тексте.
+func format(text string, args []any) string {
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
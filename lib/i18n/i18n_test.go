@@ -0,0 +1,46 @@
+package i18n
+
+import "testing"
+
+func TestRussianPlural(t *testing.T) {
+	tests := map[int]int{0: 2, 1: 0, 2: 1, 4: 1, 5: 2, 11: 2, 12: 2, 14: 2, 21: 0, 22: 1, 111: 2, 101: 0, -3: 1}
+	for n, want := range tests {
+		if got := RussianPlural(n); got != want {
+			t.Errorf("RussianPlural(%d): got %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestLocale(t *testing.T) {
+	en := &Locale{Code: "en", Rule: EnglishPlural, Messages: Messages{
+		"wait":    {"wait %d second", "wait %d seconds"},
+		"percent": {"100%"},
+		"hello":   {"hello, %s"},
+	}}
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{en.Plural("wait", 1, 1), "wait 1 second"},
+		{en.Plural("wait", 5, 5), "wait 5 seconds"},
+		{en.Text("percent"), "100%"},
+		{en.Text("hello", "Bob"), "hello, Bob"},
+		{en.Text("missing"), "missing"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+
+	clean := en.Extend("en-clean", "Clean", Messages{"hello": {"hi, %s"}})
+	if got := clean.Text("hello", "Bob"); got != "hi, Bob" {
+		t.Errorf("extended: got %q", got)
+	}
+	if got := clean.Plural("wait", 2, 2); got != "wait 2 seconds" {
+		t.Errorf("extended keeps base messages: got %q", got)
+	}
+	if en.Text("hello", "Bob") != "hello, Bob" {
+		t.Error("Extend changed the base locale")
+	}
+}
@@ -0,0 +1,205 @@
+package schedule
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// cacheWeeks сколько недель, начиная с текущей, хранится в снимке расписания.
+	cacheWeeks = 3
+)
+
+// snapshot сохранённое расписание чата в промежутке [from, to).
+type snapshot struct {
+	lessons   []Lesson
+	from, to  time.Time
+	fetchedAt time.Time
+}
+
+// covers показывает, входит ли промежуток [from, to) в снимок.
+func (s *snapshot) covers(from, to time.Time) bool {
+	return !from.Before(s.from) && !to.After(s.to)
+}
+
+// Cache хранит снимки расписания чатов, чтобы не скачивать календарь на каждый запрос.
+type Cache struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	snapshots map[int]*snapshot
+}
+
+// NewCache создаёт кэш расписания, снимки в котором устаревают через ttl.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, snapshots: make(map[int]*snapshot)}
+}
+
+// Provider возвращает источник расписания чата chatID, читающий занятия через кэш.
+func (c *Cache) Provider(chatID int, calendar CalendarProvider) CalendarProvider {
+	return &cachedProvider{cache: c, chatID: chatID, calendar: calendar}
+}
+
+// Invalidate удаляет снимок расписания чата, например после смены календаря.
+func (c *Cache) Invalidate(chatID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.snapshots, chatID)
+}
+
+// Refresh заново скачивает расписание чата и возвращает изменения занятий,
+// которые ещё не начались к моменту now. При первом обновлении изменений нет.
+func (c *Cache) Refresh(chatID int, calendar CalendarProvider, now time.Time) ([]Change, error) {
+	c.mu.Lock()
+	old := c.snapshots[chatID]
+	c.mu.Unlock()
+
+	fresh, err := c.fetch(chatID, calendar, now)
+	if err != nil {
+		return nil, err
+	}
+	if old == nil {
+		return nil, nil
+	}
+
+	// сравниваются только будущие занятия из общей части снимков
+	from, to := now, fresh.to
+	if old.to.Before(to) {
+		to = old.to
+	}
+	return Diff(lessonsBetween(old.lessons, from, to), lessonsBetween(fresh.lessons, from, to)), nil
+}
+
+// fetch скачивает расписание на cacheWeeks недель, начиная с недели now, и сохраняет снимок.
+func (c *Cache) fetch(chatID int, calendar CalendarProvider, now time.Time) (*snapshot, error) {
+	from := StartOfWeek(now)
+	to := from.AddDate(0, 0, 7*cacheWeeks)
+	lessons, err := calendar.Lessons(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &snapshot{lessons: lessons, from: from, to: to, fetchedAt: now}
+	c.mu.Lock()
+	c.snapshots[chatID] = s
+	c.mu.Unlock()
+	return s, nil
+}
+
+// cachedProvider источник расписания, который отдаёт занятия из снимка, пока он не устарел.
+type cachedProvider struct {
+	cache    *Cache
+	chatID   int
+	calendar CalendarProvider
+}
+
+func (p *cachedProvider) Lessons(from, to time.Time) ([]Lesson, error) {
+	now := time.Now().In(from.Location())
+
+	p.cache.mu.Lock()
+	s := p.cache.snapshots[p.chatID]
+	p.cache.mu.Unlock()
+
+	if s == nil || now.Sub(s.fetchedAt) > p.cache.ttl || !s.covers(from, to) {
+		window := &snapshot{from: StartOfWeek(now), to: StartOfWeek(now).AddDate(0, 0, 7*cacheWeeks)}
+		if !window.covers(from, to) {
+			// промежуток за пределами снимка - читаем календарь напрямую
+			return p.calendar.Lessons(from, to)
+		}
+		var err error
+		if s, err = p.cache.fetch(p.chatID, p.calendar, now); err != nil {
+			return nil, err
+		}
+	}
+	return lessonsBetween(s.lessons, from, to), nil
+}
+
+// lessonsBetween возвращает копию занятий, начинающихся в промежутке [from, to).
+func lessonsBetween(lessons []Lesson, from, to time.Time) []Lesson {
+	result := make([]Lesson, 0)
+	for _, l := range lessons {
+		if !l.DateTime.Before(from) && l.DateTime.Before(to) {
+			result = append(result, l)
+		}
+	}
+	return result
+}
+
+// ChangeKind тип изменения занятия.
+type ChangeKind int
+
+const (
+	LessonAdded ChangeKind = iota
+	LessonCancelled
+	LessonMoved
+)
+
+// Change изменение в расписании. Для перенесённого занятия заполнены Old и New,
+// для добавленного - только New, для отменённого - только Old.
+type Change struct {
+	Kind ChangeKind
+	Old  Lesson
+	New  Lesson
+}
+
+// Diff сравнивает два расписания и возвращает изменения, отсортированные по времени.
+// Занятия сопоставляются по названию: если занятие с тем же названием пропало в одно время
+// и появилось в другое, оно считается перенесённым.
+func Diff(old, new []Lesson) []Change {
+	removed := lessonsDifference(old, new)
+	added := lessonsDifference(new, old)
+
+	changes := make([]Change, 0)
+	used := make([]bool, len(added))
+	for _, o := range removed {
+		moved := false
+		for i, n := range added {
+			if !used[i] && n.Name == o.Name {
+				used[i], moved = true, true
+				changes = append(changes, Change{Kind: LessonMoved, Old: o, New: n})
+				break
+			}
+		}
+		if !moved {
+			changes = append(changes, Change{Kind: LessonCancelled, Old: o})
+		}
+	}
+	for i, n := range added {
+		if !used[i] {
+			changes = append(changes, Change{Kind: LessonAdded, New: n})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].time().Before(changes[j].time())
+	})
+	return changes
+}
+
+// time возвращает время, по которому изменение упорядочивается.
+func (c Change) time() time.Time {
+	if c.Kind == LessonAdded {
+		return c.New.DateTime
+	}
+	return c.Old.DateTime
+}
+
+// lessonsDifference возвращает занятия из a, которых нет в b (с тем же названием и временем).
+func lessonsDifference(a, b []Lesson) []Lesson {
+	result := make([]Lesson, 0)
+	used := make([]bool, len(b))
+	for _, l := range a {
+		found := false
+		for i, other := range b {
+			if !used[i] && other.Name == l.Name && other.DateTime.Equal(l.DateTime) {
+				used[i], found = true, true
+				break
+			}
+		}
+		if !found {
+			result = append(result, l)
+		}
+	}
+	return result
+}
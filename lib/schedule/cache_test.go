@@ -0,0 +1,67 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeProvider struct {
+	lessons []Lesson
+	calls   int
+}
+
+func (f *fakeProvider) Lessons(from, to time.Time) ([]Lesson, error) {
+	f.calls++
+	return lessonsBetween(f.lessons, from, to), nil
+}
+
+func Test_Diff(t *testing.T) {
+	day := time.Date(2024, time.February, 5, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time { return day.Add(time.Duration(hour) * time.Hour) }
+
+	old := []Lesson{{Name: "Алгебра", DateTime: at(9)}, {Name: "Физика", DateTime: at(11)}, {Name: "История", DateTime: at(13)}}
+	new := []Lesson{{Name: "Алгебра", DateTime: at(9)}, {Name: "Физика", DateTime: at(15)}, {Name: "Химия", DateTime: at(17)}}
+
+	changes := Diff(old, new)
+	want := []Change{
+		{Kind: LessonMoved, Old: Lesson{Name: "Физика", DateTime: at(11)}, New: Lesson{Name: "Физика", DateTime: at(15)}},
+		{Kind: LessonCancelled, Old: Lesson{Name: "История", DateTime: at(13)}},
+		{Kind: LessonAdded, New: Lesson{Name: "Химия", DateTime: at(17)}},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("got %d changes, want %d: %v", len(changes), len(want), changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("change %d: got %v, want %v", i, changes[i], want[i])
+		}
+	}
+
+	if changes := Diff(old, old); len(changes) != 0 {
+		t.Errorf("got changes for the same schedule: %v", changes)
+	}
+}
+
+func Test_CacheRefresh(t *testing.T) {
+	now := time.Now()
+	provider := &fakeProvider{lessons: []Lesson{{Name: "Алгебра", DateTime: now.Add(time.Hour)}}}
+	cache := NewCache(time.Hour)
+
+	if changes, err := cache.Refresh(1, provider, now); err != nil || len(changes) != 0 {
+		t.Fatalf("first refresh: got %v, %v", changes, err)
+	}
+
+	cached := cache.Provider(1, provider)
+	if _, err := cached.Lessons(StartOfDay(now), StartOfDay(now).AddDate(0, 0, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if provider.calls != 1 {
+		t.Errorf("got %d calls to provider, want lessons from cache", provider.calls)
+	}
+
+	provider.lessons = nil
+	changes, err := cache.Refresh(1, provider, now)
+	if err != nil || len(changes) != 1 || changes[0].Kind != LessonCancelled {
+		t.Errorf("second refresh: got %v, %v", changes, err)
+	}
+}
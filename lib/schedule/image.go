@@ -0,0 +1,312 @@
+package schedule
+
+import (
+	"bytes"
+	"errors"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	timeColumnWidth = 64
+	dayColumnWidth  = 230
+	headerHeight    = 44
+	hourHeight      = 84
+	blockPadding    = 4
+	textPadding     = 6
+
+	// defaultLessonDuration длительность занятия на картинке, если у события нет времени окончания.
+	defaultLessonDuration = 90 * time.Minute
+)
+
+var ErrNoLessons = errors.New("no lessons in period")
+
+var (
+	backgroundColor = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	headerColor     = color.RGBA{R: 0x34, G: 0x3a, B: 0x40, A: 0xff}
+	gridColor       = color.RGBA{R: 0xde, G: 0xe2, B: 0xe6, A: 0xff}
+	textColor       = color.RGBA{R: 0x21, G: 0x25, B: 0x29, A: 0xff}
+	mutedTextColor  = color.RGBA{R: 0x6c, G: 0x75, B: 0x7d, A: 0xff}
+
+	// typeColors цвета занятий: фон и рамка.
+	typeColors = map[LessonType][2]color.RGBA{
+		Lecture:     {{R: 0xcf, G: 0xe2, B: 0xff, A: 0xff}, {R: 0x0d, G: 0x6e, B: 0xfd, A: 0xff}},
+		Lab:         {{R: 0xd1, G: 0xf0, B: 0xd9, A: 0xff}, {R: 0x19, G: 0x87, B: 0x54, A: 0xff}},
+		Seminar:     {{R: 0xff, G: 0xf1, B: 0xc2, A: 0xff}, {R: 0xfd, G: 0x7e, B: 0x14, A: 0xff}},
+		OtherLesson: {{R: 0xe9, G: 0xec, B: 0xef, A: 0xff}, {R: 0x6c, G: 0x75, B: 0x7d, A: 0xff}},
+	}
+)
+
+var (
+	fontsOnce   sync.Once
+	regularFont *sfnt.Font
+	boldFont    *sfnt.Font
+	fontsErr    error
+)
+
+// ScheduleImage возвращает расписание в промежутке [from, to) в виде PNG таблицы:
+// дни по столбцам, время по строкам, занятия раскрашены по типу.
+// Время выводится в часовом поясе from, дни подписываются сокращениями shortDayName.
+// Если занятий нет, возвращает ErrNoLessons.
+func ScheduleImage(calendar CalendarProvider, from, to time.Time, shortDayName DayName) ([]byte, error) {
+	lessons, err := calendar.Lessons(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(lessons) == 0 {
+		return nil, ErrNoLessons
+	}
+	for i := range lessons {
+		lessons[i].DateTime = lessons[i].DateTime.In(from.Location())
+		lessons[i].End = lessons[i].End.In(from.Location())
+		if !lessons[i].End.After(lessons[i].DateTime) {
+			lessons[i].End = lessons[i].DateTime.Add(defaultLessonDuration)
+		}
+	}
+
+	img, err := renderTimetable(lessons, timetableDays(lessons, from, to), shortDayName)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// timetableDays возвращает начала дней промежутка [from, to). Выходные без занятий пропускаются.
+func timetableDays(lessons []Lesson, from, to time.Time) []time.Time {
+	days := make([]time.Time, 0, 7)
+	for day := StartOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		weekend := day.Weekday() == time.Saturday || day.Weekday() == time.Sunday
+		if weekend && len(lessonsOfDay(lessons, day)) == 0 {
+			continue
+		}
+		days = append(days, day)
+	}
+	return days
+}
+
+// lessonsOfDay возвращает занятия, которые начинаются в день day.
+func lessonsOfDay(lessons []Lesson, day time.Time) []Lesson {
+	return lessonsBetween(lessons, day, day.AddDate(0, 0, 1))
+}
+
+// renderTimetable рисует таблицу расписания.
+func renderTimetable(lessons []Lesson, days []time.Time, shortDayName DayName) (*image.RGBA, error) {
+	regular, bold, err := newFaces()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _, _ = regular.Close(), bold.Close() }()
+
+	firstHour, lastHour := 24, 0
+	for _, l := range lessons {
+		if l.DateTime.Hour() < firstHour {
+			firstHour = l.DateTime.Hour()
+		}
+		end := l.End
+		if !sameDay(end, l.DateTime) {
+			end = StartOfDay(l.DateTime).Add(24*time.Hour - time.Minute)
+		}
+		hour := end.Hour()
+		if end.Minute() > 0 {
+			hour++
+		}
+		if hour > lastHour {
+			lastHour = hour
+		}
+	}
+
+	width := timeColumnWidth + len(days)*dayColumnWidth
+	height := headerHeight + (lastHour-firstHour)*hourHeight + 1
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	fill(img, img.Bounds(), backgroundColor)
+
+	// сетка по часам
+	for hour := firstHour; hour <= lastHour; hour++ {
+		y := headerHeight + (hour-firstHour)*hourHeight
+		fill(img, image.Rect(0, y, width, y+1), gridColor)
+		if hour == lastHour {
+			break
+		}
+		drawText(img, regular, mutedTextColor, time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC).Format(timeLayout),
+			textPadding, y+textPadding)
+	}
+
+	// заголовки и занятия по дням
+	fill(img, image.Rect(0, 0, width, headerHeight), headerColor)
+	for i, day := range days {
+		x := timeColumnWidth + i*dayColumnWidth
+		fill(img, image.Rect(x, 0, x+1, height), gridColor)
+		drawText(img, bold, backgroundColor, shortDayName(day.Weekday())+" "+day.Format(dateLayout),
+			x+textPadding, (headerHeight-lineHeight(bold))/2)
+
+		dayStart := StartOfDay(day).Add(time.Duration(firstHour) * time.Hour)
+		dayLessons := lessonsOfDay(lessons, day)
+		lanes, lanesCount := assignLanes(dayLessons)
+		laneWidth := dayColumnWidth / lanesCount
+		for j, l := range dayLessons {
+			end := l.End
+			if !sameDay(end, l.DateTime) {
+				end = StartOfDay(day).Add(time.Duration(lastHour) * time.Hour)
+			}
+			block := image.Rect(
+				x+lanes[j]*laneWidth+blockPadding,
+				headerHeight+int(l.DateTime.Sub(dayStart).Minutes())*hourHeight/60+blockPadding/2,
+				x+(lanes[j]+1)*laneWidth-blockPadding,
+				headerHeight+int(end.Sub(dayStart).Minutes())*hourHeight/60-blockPadding/2,
+			)
+			drawLesson(img, regular, bold, l, block)
+		}
+	}
+	return img, nil
+}
+
+// assignLanes распределяет пересекающиеся по времени занятия дня по соседним полосам.
+// Возвращает номер полосы каждого занятия и число полос.
+func assignLanes(lessons []Lesson) ([]int, int) {
+	lanes := make([]int, len(lessons))
+	laneEnds := make([]time.Time, 0)
+	for i, l := range lessons {
+		lane := -1
+		for j, end := range laneEnds {
+			if !end.After(l.DateTime) {
+				lane = j
+				break
+			}
+		}
+		if lane < 0 {
+			lane = len(laneEnds)
+			laneEnds = append(laneEnds, time.Time{})
+		}
+		laneEnds[lane] = l.End
+		lanes[i] = lane
+	}
+	if len(laneEnds) == 0 {
+		return lanes, 1
+	}
+	return lanes, len(laneEnds)
+}
+
+// drawLesson рисует блок занятия: название, время и аудиторию, сколько поместится по высоте.
+func drawLesson(img *image.RGBA, regular, bold font.Face, l Lesson, block image.Rectangle) {
+	colors := typeColors[l.Type]
+	fill(img, block, colors[1])
+	fill(img, image.Rect(block.Min.X+3, block.Min.Y, block.Max.X, block.Max.Y), colors[0])
+
+	maxWidth := block.Dx() - textPadding - 3
+	lines := make([]textLine, 0)
+	for _, line := range wrapText(bold, l.Name, maxWidth) {
+		lines = append(lines, textLine{text: line, face: bold, color: textColor})
+	}
+	timeText := l.DateTime.Format(timeLayout) + "-" + l.End.Format(timeLayout)
+	lines = append(lines, textLine{text: timeText, face: regular, color: mutedTextColor})
+	if details := strings.TrimSpace(l.Room + " " + l.Teacher); details != "" {
+		for _, line := range wrapText(regular, details, maxWidth) {
+			lines = append(lines, textLine{text: line, face: regular, color: mutedTextColor})
+		}
+	}
+
+	y := block.Min.Y + textPadding/2
+	for _, line := range lines {
+		if y+lineHeight(line.face) > block.Max.Y {
+			break
+		}
+		drawText(img, line.face, line.color, line.text, block.Min.X+3+textPadding/2, y)
+		y += lineHeight(line.face)
+	}
+}
+
+type textLine struct {
+	text  string
+	face  font.Face
+	color color.Color
+}
+
+// wrapText разбивает текст на строки не шире maxWidth пикселей. Слишком длинные слова обрезаются.
+func wrapText(face font.Face, text string, maxWidth int) []string {
+	lines := make([]string, 0)
+	line := ""
+	for _, word := range strings.Fields(text) {
+		candidate := strings.TrimSpace(line + " " + word)
+		if font.MeasureString(face, candidate).Ceil() <= maxWidth {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		line = truncateText(face, word, maxWidth)
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// truncateText обрезает текст с многоточием, чтобы он был не шире maxWidth пикселей.
+func truncateText(face font.Face, text string, maxWidth int) string {
+	if font.MeasureString(face, text).Ceil() <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && font.MeasureString(face, string(runes)+"…").Ceil() > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// drawText пишет текст так, что его верхняя граница находится на y.
+func drawText(img *image.RGBA, face font.Face, c color.Color, text string, x, y int) {
+	d := font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y+face.Metrics().Ascent.Ceil()),
+	}
+	d.DrawString(text)
+}
+
+func lineHeight(face font.Face) int {
+	return face.Metrics().Height.Ceil()
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// newFaces создаёт начертания шрифтов Go для одной картинки: font.Face нельзя использовать из нескольких горутин.
+func newFaces() (font.Face, font.Face, error) {
+	fontsOnce.Do(func() {
+		if regularFont, fontsErr = opentype.Parse(goregular.TTF); fontsErr != nil {
+			return
+		}
+		boldFont, fontsErr = opentype.Parse(gobold.TTF)
+	})
+	if fontsErr != nil {
+		return nil, nil, fontsErr
+	}
+
+	regular, err := opentype.NewFace(regularFont, &opentype.FaceOptions{Size: 13, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, nil, err
+	}
+	bold, err := opentype.NewFace(boldFont, &opentype.FaceOptions{Size: 14, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, nil, err
+	}
+	return regular, bold, nil
+}
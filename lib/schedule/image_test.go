@@ -0,0 +1,40 @@
+package schedule
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+	"time"
+)
+
+func Test_ScheduleImage(t *testing.T) {
+	monday := time.Date(2024, time.February, 19, 0, 0, 0, 0, time.UTC)
+	at := func(day, hour, minute int) time.Time {
+		return monday.AddDate(0, 0, day).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	provider := &fakeProvider{lessons: []Lesson{
+		{Name: "Математический анализ", DateTime: at(0, 9, 0), End: at(0, 10, 35), Room: "ауд. 301", Type: Lecture},
+		{Name: "Физика", DateTime: at(0, 9, 30), End: at(0, 11, 5), Type: Lab},
+		{Name: "История", DateTime: at(2, 13, 0), Type: Seminar},
+	}}
+
+	data, err := ScheduleImage(provider, monday, monday.AddDate(0, 0, 7), time.Weekday.String)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("can't decode image: %v", err)
+	}
+
+	// 5 рабочих дней без выходных, с 9:00 до 15:00
+	wantWidth := timeColumnWidth + 5*dayColumnWidth
+	wantHeight := headerHeight + 6*hourHeight + 1
+	if b := img.Bounds(); b.Dx() != wantWidth || b.Dy() != wantHeight {
+		t.Errorf("got image %dx%d, want %dx%d", b.Dx(), b.Dy(), wantWidth, wantHeight)
+	}
+
+	if _, err = ScheduleImage(&fakeProvider{}, monday, monday.AddDate(0, 0, 7), time.Weekday.String); err != ErrNoLessons {
+		t.Errorf("got error %v, want %v", err, ErrNoLessons)
+	}
+}
@@ -0,0 +1,191 @@
+package schedule
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// LessonType тип занятия.
+type LessonType string
+
+const (
+	OtherLesson LessonType = ""
+	Lecture     LessonType = "lecture"
+	Lab         LessonType = "lab"
+	Seminar     LessonType = "seminar"
+)
+
+// WeekParity неделя, по которой проходит занятие: числитель (нечётная) или знаменатель (чётная).
+type WeekParity int
+
+const (
+	AnyWeek WeekParity = iota
+	Numerator
+	Denominator
+)
+
+// Lesson занятие из расписания.
+type Lesson struct {
+	Name     string
+	DateTime time.Time
+	End      time.Time
+	Room     string
+	Teacher  string
+	Type     LessonType
+	Week     WeekParity
+}
+
+// minDescriptionMarker минимальная длина пометки типа занятия, которая ищется в описании события.
+const minDescriptionMarker = 6
+
+// typeMarkers слова в названии или описании события, по которым определяется тип занятия.
+var typeMarkers = map[string]LessonType{
+	"лек": Lecture, "лекция": Lecture, "лк": Lecture, "lecture": Lecture,
+	"лаб": Lab, "лабораторная": Lab, "лр": Lab, "lab": Lab,
+	"сем": Seminar, "семинар": Seminar, "пр": Seminar, "практ": Seminar, "практика": Seminar,
+	"практическое": Seminar, "seminar": Seminar, "practice": Seminar,
+}
+
+// weekMarkers слова в названии события, по которым определяется неделя занятия.
+var weekMarkers = map[string]WeekParity{
+	"числ": Numerator, "числитель": Numerator, "нечет": Numerator, "нечёт": Numerator,
+	"нечетная": Numerator, "нечётная": Numerator, "odd": Numerator,
+	"знам": Denominator, "знаменатель": Denominator, "чет": Denominator, "чёт": Denominator,
+	"четная": Denominator, "чётная": Denominator, "even": Denominator,
+}
+
+var (
+	// teacherPattern фамилия с инициалами: Иванов И.И., Иванов И. И.
+	teacherPattern = regexp.MustCompile(`[А-ЯЁ][а-яё]+(?:-[А-ЯЁ][а-яё]+)?\s+[А-ЯЁ]\.\s?(?:[А-ЯЁ]\.)?`)
+	// teacherLinePattern строка описания с явным указанием преподавателя.
+	teacherLinePattern = regexp.MustCompile(`(?i)^\s*(?:преподаватель|преп\.?|teacher|lecturer)\s*:?\s*(.+)$`)
+	// roomPattern номер аудитории в тексте: ауд. 301, аудитория 5-12, room 101.
+	roomPattern = regexp.MustCompile(`(?i)(?:аудитория|ауд\.?|room)\s*([\p{L}\d][\p{L}\d\-/.]*)`)
+	// bracketsPattern пометка в скобках в названии события.
+	bracketsPattern = regexp.MustCompile(`\s*[(\[]([^)\]]*)[)\]]`)
+)
+
+// NewLesson разбирает событие календаря в занятие: тип и неделя определяются по пометкам
+// в названии (например, "Физика (лаб, знам)"), аудитория - по месту проведения или описанию,
+// преподаватель - по описанию.
+func NewLesson(summary, location, description string, start, end time.Time) Lesson {
+	l := Lesson{DateTime: start, End: end, Room: strings.TrimSpace(location)}
+	if l.End.Before(l.DateTime) {
+		l.End = l.DateTime
+	}
+
+	l.Name = bracketsPattern.ReplaceAllStringFunc(summary, func(group string) string {
+		lessonType, week, ok := parseMarkers(bracketsPattern.FindStringSubmatch(group)[1])
+		if !ok {
+			return group
+		}
+		if lessonType != OtherLesson {
+			l.Type = lessonType
+		}
+		if week != AnyWeek {
+			l.Week = week
+		}
+		return ""
+	})
+	l.Name = strings.TrimSpace(l.Name)
+	if l.Name == "" {
+		l.Name = strings.TrimSpace(summary)
+	}
+
+	for _, token := range tokens(summary) {
+		if l.Type == OtherLesson {
+			l.Type = typeMarkers[token]
+		}
+		if l.Week == AnyWeek {
+			l.Week = weekMarkers[token]
+		}
+	}
+	// в описании сокращения (пр, лаб) легко спутать с другими словами, ищем только полные названия
+	for _, token := range tokens(description) {
+		if l.Type == OtherLesson && len([]rune(token)) >= minDescriptionMarker {
+			l.Type = typeMarkers[token]
+		}
+	}
+
+	if l.Room == "" {
+		if m := roomPattern.FindStringSubmatch(description); m != nil {
+			l.Room = m[0]
+		}
+	}
+	l.Teacher = parseTeacher(description)
+	return l
+}
+
+// parseMarkers разбирает пометку в скобках. ok равен false, если в ней есть что-то кроме типа и недели.
+func parseMarkers(text string) (LessonType, WeekParity, bool) {
+	lessonType, week := OtherLesson, AnyWeek
+	words := tokens(text)
+	if len(words) == 0 {
+		return lessonType, week, false
+	}
+	for _, token := range words {
+		if t, ok := typeMarkers[token]; ok {
+			lessonType = t
+		} else if w, ok := weekMarkers[token]; ok {
+			week = w
+		} else {
+			return OtherLesson, AnyWeek, false
+		}
+	}
+	return lessonType, week, true
+}
+
+// parseTeacher ищет преподавателя в описании события.
+func parseTeacher(description string) string {
+	for _, line := range strings.Split(description, "\n") {
+		if m := teacherLinePattern.FindStringSubmatch(line); m != nil {
+			return strings.TrimSpace(m[1])
+		}
+	}
+	return strings.TrimSpace(teacherPattern.FindString(description))
+}
+
+// tokens разбивает текст на слова в нижнем регистре.
+func tokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// ParityOf возвращает, числитель или знаменатель неделя, в которую входит t.
+// numeratorWeek - любой день недели, которая считается числителем.
+func ParityOf(t, numeratorWeek time.Time) WeekParity {
+	days := StartOfWeek(t).Sub(StartOfWeek(numeratorWeek.In(t.Location()))).Hours() / 24
+	if int(math.Round(days/7))%2 == 0 {
+		return Numerator
+	}
+	return Denominator
+}
+
+// WithWeekParity возвращает источник расписания, который убирает занятия,
+// помеченные числителем или знаменателем, из недель другого типа.
+func WithWeekParity(calendar CalendarProvider, numeratorWeek time.Time) CalendarProvider {
+	return &parityProvider{calendar: calendar, numeratorWeek: numeratorWeek}
+}
+
+type parityProvider struct {
+	calendar      CalendarProvider
+	numeratorWeek time.Time
+}
+
+func (p *parityProvider) Lessons(from, to time.Time) ([]Lesson, error) {
+	lessons, err := p.calendar.Lessons(from, to)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Lesson, 0, len(lessons))
+	for _, l := range lessons {
+		if l.Week == AnyWeek || l.Week == ParityOf(l.DateTime, p.numeratorWeek) {
+			result = append(result, l)
+		}
+	}
+	return result, nil
+}
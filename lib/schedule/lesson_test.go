@@ -0,0 +1,57 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_NewLesson(t *testing.T) {
+	start := time.Date(2024, time.February, 19, 9, 45, 0, 0, time.UTC)
+	end := start.Add(95 * time.Minute)
+
+	tests := []struct {
+		summary, location, description string
+		want                           Lesson
+	}{
+		{
+			summary:     "Физика (лаб, знам)",
+			description: "Преподаватель: Петров П.П.\nауд. 5-12",
+			want:        Lesson{Name: "Физика", Room: "ауд. 5-12", Teacher: "Петров П.П.", Type: Lab, Week: Denominator},
+		},
+		{
+			summary:     "Лекция Математический анализ",
+			location:    "Главный корпус, 301",
+			description: "Иванов И. И.",
+			want:        Lesson{Name: "Лекция Математический анализ", Room: "Главный корпус, 301", Teacher: "Иванов И. И.", Type: Lecture},
+		},
+		{
+			summary:     "История (онлайн)",
+			description: "Семинар, проспект Ленина",
+			want:        Lesson{Name: "История (онлайн)", Type: Seminar},
+		},
+	}
+	for _, tt := range tests {
+		tt.want.DateTime, tt.want.End = start, end
+		if got := NewLesson(tt.summary, tt.location, tt.description, start, end); got != tt.want {
+			t.Errorf("NewLesson(%q): got %+v, want %+v", tt.summary, got, tt.want)
+		}
+	}
+}
+
+func Test_WeekParity(t *testing.T) {
+	numerator := time.Date(2024, time.February, 7, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		day  time.Time
+		want WeekParity
+	}{
+		{time.Date(2024, time.February, 5, 8, 0, 0, 0, time.UTC), Numerator},
+		{time.Date(2024, time.February, 18, 23, 0, 0, 0, time.UTC), Denominator},
+		{time.Date(2024, time.February, 19, 8, 0, 0, 0, time.UTC), Numerator},
+		{time.Date(2024, time.January, 31, 8, 0, 0, 0, time.UTC), Denominator},
+	}
+	for _, tt := range tests {
+		if got := ParityOf(tt.day, numerator); got != tt.want {
+			t.Errorf("ParityOf(%s): got %d, want %d", tt.day.Format("02.01"), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package schedule
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	google_calendar "tg_ics_useful_bot/clients/google-calendar"
+	"tg_ics_useful_bot/clients/ical"
+	"time"
+)
+
+const (
+	GoogleKind = "google"
+	ICSKind    = "ics"
+)
+
+// CalendarProvider источник расписания.
+type CalendarProvider interface {
+	// Lessons возвращает занятия, начинающиеся в промежутке [from, to), отсортированные по времени.
+	Lessons(from, to time.Time) ([]Lesson, error)
+}
+
+// NewProvider возвращает источник расписания по типу календаря.
+// source - Google Calendar ID или ссылка на .ics, data - содержимое загруженного .ics файла.
+// Если data не пустая, календарь читается из неё, а не по ссылке.
+func NewProvider(kind, source, data string) (CalendarProvider, error) {
+	switch kind {
+	case GoogleKind, "":
+		return googleProvider(source), nil
+	case ICSKind:
+		return &icsProvider{url: source, data: data}, nil
+	}
+	return nil, fmt.Errorf("unknown calendar kind %s", kind)
+}
+
+// IsICSLink показывает, является ли source ссылкой на .ics календарь.
+func IsICSLink(source string) bool {
+	for _, prefix := range []string{"http://", "https://", "webcal://"} {
+		if strings.HasPrefix(strings.ToLower(source), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// googleProvider расписание из Google Calendar по его ID.
+type googleProvider string
+
+func (g googleProvider) Lessons(from, to time.Time) ([]Lesson, error) {
+	lessons, err := google_calendar.Lessons(string(g), from, to)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Lesson, 0, len(lessons))
+	for _, l := range lessons {
+		result = append(result, NewLesson(l.Name, l.Location, l.Description, l.DateTime, l.End))
+	}
+	return result, nil
+}
+
+// icsProvider расписание из iCalendar: из загруженного файла или, если его нет, по ссылке.
+type icsProvider struct {
+	url  string
+	data string
+}
+
+func (i *icsProvider) Lessons(from, to time.Time) ([]Lesson, error) {
+	data := []byte(i.data)
+	if i.data == "" {
+		var err error
+		if data, err = ical.Fetch(i.url); err != nil {
+			return nil, err
+		}
+	}
+	cal, err := ical.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Lesson, 0)
+	for _, ev := range cal.Occurrences(from, to) {
+		if ev.AllDay {
+			continue
+		}
+		result = append(result, NewLesson(ev.Summary, ev.Location, ev.Description, ev.Start, ev.End))
+	}
+	return result, nil
+}
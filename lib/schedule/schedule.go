@@ -2,73 +2,116 @@ package schedule
 
 import (
 	"fmt"
-	google_calendar "tg_ics_useful_bot/clients/google-calendar"
+	"strings"
+	"tg_ics_useful_bot/lib/utils"
 	"time"
 )
 
-func ScheduleCmd(calendarID string) (string, error) {
-	result := ""
-	dayToLessons, err := google_calendar.Lessons(calendarID)
+const (
+	timeLayout = "15:04"
+	dateLayout = "02.01"
+
+	// nextLessonWindow насколько далеко вперёд ищется следующее занятие.
+	nextLessonWindow = 14 * 24 * time.Hour
+)
+
+var typeIcons = map[LessonType]string{
+	Lecture: "📖",
+	Lab:     "🔬",
+	Seminar: "✏️",
+}
+
+// DayName возвращает название дня недели для заголовков расписания, например на языке чата.
+type DayName func(day time.Weekday) string
+
+// ScheduleCmd возвращает расписание на неделю, в которую входит now.
+func ScheduleCmd(calendar CalendarProvider, now time.Time, dayName DayName) (string, error) {
+	from := StartOfWeek(now)
+	return ScheduleForPeriod(calendar, from, from.AddDate(0, 0, 7), dayName)
+}
+
+// ScheduleForPeriod возвращает расписание в промежутке [from, to), сгруппированное по дням.
+// Время занятий выводится в часовом поясе from.
+func ScheduleForPeriod(calendar CalendarProvider, from, to time.Time, dayName DayName) (string, error) {
+	lessons, err := calendar.Lessons(from, to)
 	if err != nil {
 		return "", err
 	}
-	for day := time.Sunday; day <= time.Saturday; day++ {
-		lessons := dayToLessons[day]
-		if len(lessons) > 0 {
-			result += "\n"
-			switch day {
-			case time.Monday:
-				result += "*Понедельник*"
-			case time.Tuesday:
-				result += "*Вторник*"
-			case time.Wednesday:
-				result += "*Среда*"
-			case time.Thursday:
-				result += "*Четверг*"
-			case time.Friday:
-				result += "*Пятница*"
-			case time.Saturday:
-				result += "*Суббота*"
-			}
-			result += "\n"
-			for _, l := range lessons {
-				result += fmt.Sprintf("%s - %s\n", l.Name, l.DateTime.Format("15:04"))
-			}
+	for i := range lessons {
+		lessons[i].DateTime = lessons[i].DateTime.In(from.Location())
+		lessons[i].End = lessons[i].End.In(from.Location())
+	}
+	result := ""
+	for i, l := range lessons {
+		if i == 0 || !sameDay(l.DateTime, lessons[i-1].DateTime) {
+			result += fmt.Sprintf("\n*%s %s*\n", dayName(l.DateTime.Weekday()), l.DateTime.Format(dateLayout))
 		}
+		result += lessonText(l)
 	}
 	return result, nil
 }
 
-func ScheduleByDay(day time.Weekday, calendarID string) (string, error) {
-	dayToLessons, err := google_calendar.Lessons(calendarID)
-	if err != nil {
-		return "", err
+// lessonText возвращает строку расписания для занятия: время, тип, название,
+// а на следующей строке аудиторию и преподавателя. Поля из календаря экранируются для Markdown.
+func lessonText(l Lesson) string {
+	result := l.DateTime.Format(timeLayout)
+	if l.End.After(l.DateTime) {
+		result += "-" + l.End.Format(timeLayout)
 	}
-	result := ""
-	lessons := dayToLessons[day]
-
-	if len(lessons) == 0 {
-		return "", nil
+	if icon, ok := typeIcons[l.Type]; ok {
+		result += " " + icon
 	}
+	result += " " + utils.EscapeMarkdown(l.Name) + "\n"
+	if details := l.Details(); details != "" {
+		result += "      " + utils.EscapeMarkdown(details) + "\n"
+	}
+	return result
+}
 
-	switch day {
-	case time.Monday:
-		result += "Понедельник"
-	case time.Tuesday:
-		result += "Вторник"
-	case time.Wednesday:
-		result += "Среда"
-	case time.Thursday:
-		result += "Четверг"
-	case time.Friday:
-		result += "Пятница"
-	case time.Saturday:
-		result += "Суббота"
+// Details возвращает аудиторию и преподавателя занятия, пустую строку если они неизвестны.
+func (l Lesson) Details() string {
+	details := make([]string, 0, 2)
+	if l.Room != "" {
+		details = append(details, "📍 "+l.Room)
 	}
-	result += "\n"
+	if l.Teacher != "" {
+		details = append(details, "👤 "+l.Teacher)
+	}
+	return strings.Join(details, ", ")
+}
 
-	for _, l := range lessons {
-		result += fmt.Sprintf("%s - %s\n", l.Name, l.DateTime.Format("15:04"))
+// ScheduleByDay возвращает расписание на день day, пустую строку если занятий нет.
+func ScheduleByDay(day time.Time, calendar CalendarProvider, dayName DayName) (string, error) {
+	from := StartOfDay(day)
+	return ScheduleForPeriod(calendar, from, from.AddDate(0, 0, 1), dayName)
+}
+
+// NextLesson возвращает ближайшее занятие, которое начнётся после now, nil если занятий нет.
+// Время занятия возвращается в часовом поясе now.
+func NextLesson(calendar CalendarProvider, now time.Time) (*Lesson, error) {
+	lessons, err := calendar.Lessons(now, now.Add(nextLessonWindow))
+	if err != nil {
+		return nil, err
 	}
-	return result, nil
+	if len(lessons) == 0 {
+		return nil, nil
+	}
+	lesson := lessons[0]
+	lesson.DateTime = lesson.DateTime.In(now.Location())
+	return &lesson, nil
+}
+
+// StartOfDay возвращает начало дня t в его часовом поясе.
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek возвращает начало понедельника недели, в которую входит t.
+func StartOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return StartOfDay(t).AddDate(0, 0, -offset)
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
 }
@@ -0,0 +1,25 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ScheduleForPeriodMarkdown(t *testing.T) {
+	day := time.Date(2024, time.February, 5, 0, 0, 0, 0, time.UTC)
+	calendar := &fakeProvider{lessons: []Lesson{
+		{Name: "Физ_ра", Room: "ауд. 3_12", Teacher: "*Иванов*", DateTime: day.Add(9 * time.Hour)},
+	}}
+	dayName := func(day time.Weekday) string { return day.String() }
+
+	text, err := ScheduleForPeriod(calendar, day, day.AddDate(0, 0, 1), dayName)
+	if err != nil {
+		t.Fatalf("ScheduleForPeriod: %v", err)
+	}
+	for _, want := range []string{"*Monday 05.02*", `Физ\_ра`, `📍 ауд. 3\_12, 👤 \*Иванов\*`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("no %q in\n%s", want, text)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package utils
 
-import "strings"
+import (
+	"strings"
+	"unicode/utf8"
+)
 
 func StringContains(x, str string) bool {
 	for _, ch := range str {
@@ -30,3 +33,34 @@ func Abs(a int) int {
 	}
 	return -1 * a
 }
+
+// SplitText делит текст на части длиной не больше maxLen байт.
+// Старается резать по переводам строк, затем по пробелам, не разрывая UTF-8 символы.
+func SplitText(text string, maxLen int) []string {
+	parts := make([]string, 0, len(text)/maxLen+1)
+	for len(text) > maxLen {
+		cut := strings.LastIndex(text[:maxLen], "\n")
+		if cut <= 0 {
+			cut = strings.LastIndex(text[:maxLen], " ")
+		}
+		if cut <= 0 {
+			cut = maxLen
+			for cut > 0 && !utf8.RuneStart(text[cut]) {
+				cut--
+			}
+		}
+		parts = append(parts, text[:cut])
+		text = strings.TrimLeft(text[cut:], "\n ")
+	}
+	if len(text) > 0 || len(parts) == 0 {
+		parts = append(parts, text)
+	}
+	return parts
+}
+
+// EscapeMarkdown экранирует символы разметки Markdown, чтобы текст выводился как есть.
+func EscapeMarkdown(text string) string {
+	return markdownReplacer.Replace(text)
+}
+
+var markdownReplacer = strings.NewReplacer("_", "\\_", "*", "\\*", "`", "\\`", "[", "\\[")
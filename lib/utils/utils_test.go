@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_SplitText(t *testing.T) {
+	testCases := []struct {
+		text   string
+		maxLen int
+		want   []string
+	}{
+		{"", 10, []string{""}},
+		{"короткий", 100, []string{"короткий"}},
+		{"первая строка\nвторая", 26, []string{"первая строка", "вторая"}},
+		{"abc def ghi", 7, []string{"abc", "def ghi"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"жжжж", 3, []string{"ж", "ж", "ж", "ж"}},
+	}
+
+	for _, tc := range testCases {
+		res := SplitText(tc.text, tc.maxLen)
+		if strings.Join(res, "|") != strings.Join(tc.want, "|") {
+			t.Errorf("In \"%s\" result: %q, want: %q", tc.text, res, tc.want)
+		}
+		for _, part := range res {
+			if len(part) > tc.maxLen {
+				t.Errorf("In \"%s\" part %q is longer than %d", tc.text, part, tc.maxLen)
+			}
+		}
+	}
+}
+
+func Test_EscapeMarkdown(t *testing.T) {
+	if got, want := EscapeMarkdown("Физ_ра *[ауд. 3_12]* `x`"), "Физ\\_ра \\*\\[ауд. 3\\_12]\\* \\`x\\`"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
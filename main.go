@@ -3,11 +3,14 @@ package main
 import (
 	"flag"
 	"log"
+	"os"
+	google_calendar "tg_ics_useful_bot/clients/google-calendar"
 	tgClient "tg_ics_useful_bot/clients/telegram"
 	"tg_ics_useful_bot/config"
 	"tg_ics_useful_bot/consumer/event-consumer"
 	"tg_ics_useful_bot/events/telegram"
 	"tg_ics_useful_bot/storage/sqlite"
+	_ "time/tzdata"
 )
 
 const (
@@ -17,7 +20,15 @@ const (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == exportCmd {
+		if err := runExport(os.Args[2:]); err != nil {
+			log.Fatal("[ERROR] can't export homework: ", err)
+		}
+		return
+	}
+
 	cfg := config.New()
+	google_calendar.CredentialsFile = cfg.GoogleCredentials
 
 	//s, err := postgres.New(cfg)
 	//if err != nil {
@@ -30,10 +41,19 @@ func main() {
 	}
 
 
-	eventsProcessor := telegram.New(
+	eventsProcessor, err := telegram.New(
 		tgClient.New(tgBotHost, cfg.TelegramToken, cfg.AdminsID),
 		s,
 	)
+	if err != nil {
+		log.Fatal("[ERROR] can't start bot: ", err)
+	}
+
+	if err = eventsProcessor.SetCommands(); err != nil {
+		log.Print("[ERROR] can't set bot commands: ", err)
+	}
+
+	eventsProcessor.RunJobs()
 
 	log.Print("[INFO] service started")
 
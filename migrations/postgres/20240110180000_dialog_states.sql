@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS dialog_states
+(
+    chat_id BIGINT NOT NULL,
+    tg_id BIGINT NOT NULL,
+    dialog VARCHAR NOT NULL,
+    step INTEGER NOT NULL DEFAULT 0,
+    data VARCHAR NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (chat_id, tg_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS dialog_states;
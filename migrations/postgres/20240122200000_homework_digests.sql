@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS homework_digests
+(
+    chat_id BIGINT PRIMARY KEY NOT NULL UNIQUE,
+    weekday INTEGER NOT NULL,
+    send_time VARCHAR NOT NULL,
+    pin BOOLEAN NOT NULL DEFAULT FALSE,
+    last_sent_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS homework_digests;
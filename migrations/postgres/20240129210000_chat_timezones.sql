@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS chat_timezones
+(
+    chat_id BIGINT PRIMARY KEY NOT NULL UNIQUE,
+    timezone VARCHAR NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS chat_timezones;
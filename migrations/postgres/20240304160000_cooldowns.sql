@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS cooldowns
+(
+    chat_id BIGINT NOT NULL,
+    tg_id BIGINT NOT NULL,
+    command VARCHAR NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    warned BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (chat_id, tg_id, command)
+);
+
+CREATE TABLE IF NOT EXISTS command_cooldowns
+(
+    chat_id BIGINT NOT NULL,
+    command VARCHAR NOT NULL,
+    per_user INTEGER NOT NULL DEFAULT 0,
+    per_chat INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (chat_id, command)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS command_cooldowns;
+DROP TABLE IF EXISTS cooldowns;
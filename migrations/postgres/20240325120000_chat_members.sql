@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE chat_settings ADD COLUMN greetings BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE chat_settings ADD COLUMN welcome VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE chat_settings ADD COLUMN goodbye VARCHAR NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE chat_settings DROP COLUMN goodbye;
+ALTER TABLE chat_settings DROP COLUMN welcome;
+ALTER TABLE chat_settings DROP COLUMN greetings;
+ALTER TABLE users DROP COLUMN active;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE homeworks ADD COLUMN deadline TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE homeworks DROP COLUMN deadline;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE calendars ADD COLUMN kind VARCHAR NOT NULL DEFAULT 'google';
+ALTER TABLE calendars ADD COLUMN ics_data TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE calendars DROP COLUMN ics_data;
+ALTER TABLE calendars DROP COLUMN kind;
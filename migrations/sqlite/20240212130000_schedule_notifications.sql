@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS schedule_notifications
+(
+    chat_id BIGINT PRIMARY KEY NOT NULL UNIQUE,
+    send_time VARCHAR NOT NULL,
+    remind_before INTEGER NOT NULL DEFAULT 0,
+    last_sent_at TIMESTAMP NOT NULL,
+    last_reminded_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS schedule_notifications;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE calendars ADD COLUMN numerator_week TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE calendars DROP COLUMN numerator_week;
@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS chat_roles
+(
+    chat_id BIGINT NOT NULL,
+    tg_id BIGINT NOT NULL,
+    role VARCHAR NOT NULL,
+    PRIMARY KEY (chat_id, tg_id, role)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS chat_roles;
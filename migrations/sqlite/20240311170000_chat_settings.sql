@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS chat_settings
+(
+    chat_id BIGINT PRIMARY KEY NOT NULL UNIQUE,
+    games BOOLEAN NOT NULL DEFAULT TRUE,
+    auto_replies BOOLEAN NOT NULL DEFAULT TRUE,
+    delete_commands BOOLEAN NOT NULL DEFAULT TRUE,
+    language VARCHAR NOT NULL DEFAULT 'ru'
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS chat_settings;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS auto_replies
+(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    chat_id BIGINT NOT NULL,
+    kind VARCHAR NOT NULL,
+    pattern VARCHAR NOT NULL,
+    reply VARCHAR NOT NULL,
+    probability INTEGER NOT NULL DEFAULT 100,
+    cooldown INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS auto_replies_chat_id ON auto_replies (chat_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS auto_replies;
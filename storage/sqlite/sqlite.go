@@ -99,9 +99,9 @@ func (s *Storage) UserByUsername(ctx context.Context, username string, chatID in
 	return &user, nil
 }
 
-// UsersByChat возвращает всех пользователей из базы данных, которые находятся в одном телеграм чате.
+// UsersByChat возвращает всех пользователей из базы данных, которые состоят в одном телеграм чате.
 func (s *Storage) UsersByChat(ctx context.Context, chatID int) ([]*storage.DBUser, error) {
-	q := `SELECT * FROM users WHERE chat_id = $1 ORDER BY -dick_size`
+	q := `SELECT * FROM users WHERE chat_id = $1 AND active ORDER BY -dick_size`
 
 	users := []*storage.DBUser{}
 
@@ -113,6 +113,40 @@ func (s *Storage) UsersByChat(ctx context.Context, chatID int) ([]*storage.DBUse
 	return users, nil
 }
 
+// SetUserActive отмечает, что пользователь вошёл в чат или вышел из него.
+func (s *Storage) SetUserActive(ctx context.Context, tgID, chatID int, active bool) error {
+	q := `UPDATE users SET active = $1 WHERE tg_id = $2 AND chat_id = $3`
+	if _, err := s.db.ExecContext(ctx, q, active, tgID, chatID); err != nil {
+		return e.Wrap(fmt.Sprintf("can't set user #%d active in chat #%d", tgID, chatID), err)
+	}
+	return nil
+}
+
+// DeactivateChat убирает чат, из которого удалили бота: отмечает всех пользователей вышедшими
+// и удаляет рассылки, диалоги и ограничения команд. Игры, домашние задания и настройки остаются
+// на случай, если бота вернут. Всё делается в одной транзакции, чтобы чат не остался убранным наполовину.
+func (s *Storage) DeactivateChat(ctx context.Context, chatID int) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return e.Wrap(fmt.Sprintf("can't deactivate chat #%d", chatID), err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET active = $1 WHERE chat_id = $2`, false, chatID); err != nil {
+		return e.Wrap(fmt.Sprintf("can't deactivate users in chat #%d", chatID), err)
+	}
+	for _, table := range []string{"homework_digests", "schedule_notifications", "dialog_states", "cooldowns"} {
+		q := fmt.Sprintf(`DELETE FROM %s WHERE chat_id = $1`, table)
+		if _, err := tx.ExecContext(ctx, q, chatID); err != nil {
+			return e.Wrap(fmt.Sprintf("can't clean %s in chat #%d", table, chatID), err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return e.Wrap(fmt.Sprintf("can't deactivate chat #%d", chatID), err)
+	}
+	return nil
+}
+
 // GetGayOfDay возвращает запись о пидоре дне из базы данных.
 func (s *Storage) GetGayOfDay(ctx context.Context, chatID int) (*storage.DBGay, error) {
 	q := `SELECT * FROM gays WHERE chat_id = $1`
@@ -150,41 +184,121 @@ func (s *Storage) RemoveGayOfDay(ctx context.Context, chatID int) error {
 	q := `DELETE FROM gays WHERE chat_id = $1`
 
 	if _, err := s.db.ExecContext(ctx, q, chatID); err != nil {
-		return e.Wrap(fmt.Sprintf("[ERROR] can't remove gay %d %s: ", chatID), err)
+		return e.Wrap(fmt.Sprintf("[ERROR] can't remove gay in chat %d: ", chatID), err)
 	}
 	return nil
 }
 
-// GetCalendarID возвращает Google Calendar ID из базы данных.
-func (s *Storage) GetCalendarID(ctx context.Context, chatID int) (string, error) {
-	q := `SELECT * from calendars WHERE chat_id = $1`
+// GetChatTimezone возвращает часовой пояс чата в формате IANA (Europe/Moscow).
+func (s *Storage) GetChatTimezone(ctx context.Context, chatID int) (string, error) {
+	q := `SELECT timezone FROM chat_timezones WHERE chat_id = $1`
 
-	var id int
-	var calendarID string
-	err := s.db.QueryRowContext(ctx, q, chatID).Scan(&id, &calendarID)
+	var timezone string
+	err := s.db.GetContext(ctx, &timezone, q, chatID)
+	if err == sql.ErrNoRows {
+		return "", storage.ErrChatNotExist
+	}
+
+	if err != nil {
+		return "", e.Wrap(fmt.Sprintf("can't get timezone of chat #%d", chatID), err)
+	}
+	return timezone, nil
+}
+
+// SetChatTimezone сохраняет часовой пояс чата.
+func (s *Storage) SetChatTimezone(ctx context.Context, chatID int, timezone string) error {
+	q := `INSERT INTO chat_timezones (chat_id, timezone) VALUES ($1, $2) ON CONFLICT (chat_id) DO UPDATE SET timezone = excluded.timezone`
+	if _, err := s.db.ExecContext(ctx, q, chatID, timezone); err != nil {
+		return e.Wrap(fmt.Sprintf("can't set timezone of chat #%d", chatID), err)
+	}
+	return nil
+}
+
+// GetChatSettings возвращает настройки чата.
+func (s *Storage) GetChatSettings(ctx context.Context, chatID int) (*storage.DBChatSettings, error) {
+	q := `SELECT * FROM chat_settings WHERE chat_id = $1`
+
+	settings := storage.DBChatSettings{}
+	err := s.db.GetContext(ctx, &settings, q, chatID)
 	if err == sql.ErrNoRows {
-		return "", storage.ErrUserNotExist
+		return nil, storage.ErrChatNotExist
 	}
 
 	if err != nil {
-		return "", e.Wrap(fmt.Sprintf("[ERROR] can't get calendar_id from table calendars chat id: %d", chatID), err)
+		return nil, e.Wrap(fmt.Sprintf("can't get settings of chat #%d", chatID), err)
 	}
-	return calendarID, nil
+	return &settings, nil
 }
 
-// AddCalendarID добавляет Google Calendar ID в базу данных.
-func (s *Storage) AddCalendarID(ctx context.Context, chatID int, calendarID string) error {
-	q := `INSERT INTO calendars (chat_id, calendar_id) VALUES ($1, $2) ON CONFLICT (chat_id) DO UPDATE SET calendar_id = $3`
-	if _, err := s.db.ExecContext(ctx, q, chatID, calendarID, calendarID); err != nil {
-		return e.Wrap(fmt.Sprintf("can't update or create calendar_id in chat #%d: ", chatID), err)
+// SaveChatSettings создаёт или обновляет настройки чата.
+func (s *Storage) SaveChatSettings(ctx context.Context, c *storage.DBChatSettings) error {
+	q := `INSERT INTO chat_settings (chat_id, games, auto_replies, delete_commands, language, greetings, welcome, goodbye)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (chat_id) DO UPDATE SET games = excluded.games, auto_replies = excluded.auto_replies,
+			delete_commands = excluded.delete_commands, language = excluded.language, greetings = excluded.greetings,
+			welcome = excluded.welcome, goodbye = excluded.goodbye`
+	if _, err := s.db.ExecContext(ctx, q, c.ChatID, c.Games, c.AutoReplies, c.DeleteCommands, c.Language,
+		c.Greetings, c.Welcome, c.Goodbye); err != nil {
+		return e.Wrap(fmt.Sprintf("can't save settings of chat #%d", c.ChatID), err)
+	}
+	return nil
+}
+
+// GetCalendar возвращает привязанный к чату календарь.
+func (s *Storage) GetCalendar(ctx context.Context, chatID int) (*storage.DBCalendar, error) {
+	q := `SELECT chat_id, calendar_id, kind, ics_data, numerator_week FROM calendars WHERE chat_id = $1`
+
+	var c storage.DBCalendar
+	err := s.db.GetContext(ctx, &c, q, chatID)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrCalendarNotExist
+	}
+
+	if err != nil {
+		return nil, e.Wrap(fmt.Sprintf("[ERROR] can't get calendar from table calendars chat id: %d", chatID), err)
+	}
+	return &c, nil
+}
+
+// SetNumeratorWeek сохраняет неделю, которая считается числителем в расписании чата.
+func (s *Storage) SetNumeratorWeek(ctx context.Context, chatID int, week time.Time) error {
+	q := `UPDATE calendars SET numerator_week = $1 WHERE chat_id = $2`
+	res, err := s.db.ExecContext(ctx, q, week, chatID)
+	if err != nil {
+		return e.Wrap(fmt.Sprintf("can't set numerator week in chat #%d", chatID), err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return storage.ErrCalendarNotExist
+	}
+	return nil
+}
+
+// AllCalendars возвращает календари всех чатов.
+func (s *Storage) AllCalendars(ctx context.Context) ([]*storage.DBCalendar, error) {
+	q := `SELECT chat_id, calendar_id, kind, ics_data, numerator_week FROM calendars`
+
+	calendars := []*storage.DBCalendar{}
+	err := s.db.SelectContext(ctx, &calendars, q)
+	if err != nil {
+		return nil, e.Wrap("can't get all calendars", err)
+	}
+	return calendars, nil
+}
+
+// SaveCalendar привязывает календарь к чату, заменяя предыдущий.
+func (s *Storage) SaveCalendar(ctx context.Context, c *storage.DBCalendar) error {
+	q := `INSERT INTO calendars (chat_id, calendar_id, kind, ics_data) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (chat_id) DO UPDATE SET calendar_id = excluded.calendar_id, kind = excluded.kind, ics_data = excluded.ics_data`
+	if _, err := s.db.ExecContext(ctx, q, c.ChatID, c.CalendarID, c.Kind, c.Data); err != nil {
+		return e.Wrap(fmt.Sprintf("can't update or create calendar in chat #%d: ", c.ChatID), err)
 	}
 	return nil
 }
 
 // AddHomework добавляет запись домашнего задания в таблицу базы данных.
-func (s *Storage) AddHomework(ctx context.Context, chatID int, subject string, task string) error {
-	q := `INSERT INTO homeworks (chat_id, subject, task, created_at) VALUES ($1, $2, $3, $4)`
-	if _, err := s.db.ExecContext(ctx, q, chatID, subject, task, time.Now()); err != nil {
+func (s *Storage) AddHomework(ctx context.Context, chatID int, subject string, task string, deadline *time.Time) error {
+	q := `INSERT INTO homeworks (chat_id, subject, task, created_at, deadline) VALUES ($1, $2, $3, $4, $5)`
+	if _, err := s.db.ExecContext(ctx, q, chatID, subject, task, time.Now(), deadline); err != nil {
 		return e.Wrap("can't add homework:", err)
 	}
 	return nil
@@ -202,6 +316,18 @@ func (s *Storage) GetHomeworkByChatID(ctx context.Context, chatID int, limit int
 	return homeworks, nil
 }
 
+// GetAllHomework возвращает все записи домашнего задания в чате.
+func (s *Storage) GetAllHomework(ctx context.Context, chatID int) ([]*storage.DBHomework, error) {
+	q := `SELECT * from homeworks WHERE chat_id = $1 ORDER BY created_at DESC`
+
+	homeworks := []*storage.DBHomework{}
+	err := s.db.SelectContext(ctx, &homeworks, q, chatID)
+	if err != nil {
+		return nil, e.Wrap("can't get all homeworks", err)
+	}
+	return homeworks, nil
+}
+
 // GetHomeworkBySubject возвращает запись домашнего задания по названию предмета.
 func (s *Storage) GetHomeworkBySubject(ctx context.Context, chatID int, subject string) ([]*storage.DBHomework, error) {
 	q := `SELECT * from homeworks WHERE chat_id = $1 AND subject = $2 ORDER BY created_at DESC `
@@ -214,6 +340,44 @@ func (s *Storage) GetHomeworkBySubject(ctx context.Context, chatID int, subject
 	return homeworks, nil
 }
 
+// GetHomeworkPage возвращает страницу записей домашнего задания, пустой subject - все предметы.
+func (s *Storage) GetHomeworkPage(ctx context.Context, chatID int, subject string, limit, offset int) ([]*storage.DBHomework, error) {
+	q := `SELECT * from homeworks WHERE chat_id = $1 AND ($2 = '' OR subject = $2) ORDER BY created_at DESC LIMIT $3 OFFSET $4`
+
+	homeworks := []*storage.DBHomework{}
+	err := s.db.SelectContext(ctx, &homeworks, q, chatID, subject, limit, offset)
+	if err != nil {
+		return nil, e.Wrap("can't get homeworks page", err)
+	}
+	return homeworks, nil
+}
+
+// CountHomework возвращает количество записей домашнего задания в чате, пустой subject - все предметы.
+func (s *Storage) CountHomework(ctx context.Context, chatID int, subject string) (int, error) {
+	q := `SELECT COUNT(*) from homeworks WHERE chat_id = $1 AND ($2 = '' OR subject = $2)`
+
+	var count int
+	err := s.db.GetContext(ctx, &count, q, chatID, subject)
+	if err != nil {
+		return 0, e.Wrap("can't count homeworks", err)
+	}
+	return count, nil
+}
+
+// GetHomeworkByPeriod возвращает домашнее задание, добавленное в промежутке [from, to).
+// SQLite сравнивает время как строки, поэтому границы переводятся в часовой пояс сервера,
+// в котором записан created_at.
+func (s *Storage) GetHomeworkByPeriod(ctx context.Context, chatID int, from, to time.Time) ([]*storage.DBHomework, error) {
+	q := `SELECT * from homeworks WHERE chat_id = $1 AND created_at >= $2 AND created_at < $3 ORDER BY subject, created_at`
+
+	homeworks := []*storage.DBHomework{}
+	err := s.db.SelectContext(ctx, &homeworks, q, chatID, from.In(time.Local), to.In(time.Local))
+	if err != nil {
+		return nil, e.Wrap("can't get homeworks by period", err)
+	}
+	return homeworks, nil
+}
+
 // DeleteHomework удаляет домашнее задание из базы данных.
 func (s *Storage) DeleteHomework(ctx context.Context, id int) error {
 	q := `DELETE FROM homeworks WHERE id = $1`
@@ -224,6 +388,289 @@ func (s *Storage) DeleteHomework(ctx context.Context, id int) error {
 	return nil
 }
 
+// GetDigest возвращает настройки еженедельной сводки домашнего задания чата.
+func (s *Storage) GetDigest(ctx context.Context, chatID int) (*storage.DBDigest, error) {
+	q := `SELECT * FROM homework_digests WHERE chat_id = $1`
+
+	digest := storage.DBDigest{}
+	err := s.db.GetContext(ctx, &digest, q, chatID)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrDigestNotExist
+	}
+
+	if err != nil {
+		return nil, e.Wrap(fmt.Sprintf("can't get digest in chat #%d", chatID), err)
+	}
+	return &digest, nil
+}
+
+// AllDigests возвращает настройки сводок всех чатов.
+func (s *Storage) AllDigests(ctx context.Context) ([]*storage.DBDigest, error) {
+	q := `SELECT * FROM homework_digests`
+
+	digests := []*storage.DBDigest{}
+	err := s.db.SelectContext(ctx, &digests, q)
+	if err != nil {
+		return nil, e.Wrap("can't get all digests", err)
+	}
+	return digests, nil
+}
+
+// SaveDigest создаёт или обновляет настройки сводки чата.
+func (s *Storage) SaveDigest(ctx context.Context, d *storage.DBDigest) error {
+	q := `INSERT INTO homework_digests (chat_id, weekday, send_time, pin, last_sent_at) VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (chat_id) DO UPDATE SET weekday = excluded.weekday, send_time = excluded.send_time,
+			pin = excluded.pin, last_sent_at = excluded.last_sent_at`
+	if _, err := s.db.ExecContext(ctx, q, d.ChatID, d.Weekday, d.SendTime, d.Pin, d.LastSentAt); err != nil {
+		return e.Wrap(fmt.Sprintf("can't save digest in chat #%d", d.ChatID), err)
+	}
+	return nil
+}
+
+// DeleteDigest отключает сводку в чате.
+func (s *Storage) DeleteDigest(ctx context.Context, chatID int) error {
+	q := `DELETE FROM homework_digests WHERE chat_id = $1`
+	if _, err := s.db.ExecContext(ctx, q, chatID); err != nil {
+		return e.Wrap(fmt.Sprintf("can't delete digest in chat #%d", chatID), err)
+	}
+	return nil
+}
+
+// GetScheduleNotify возвращает настройки утренней рассылки расписания чата.
+func (s *Storage) GetScheduleNotify(ctx context.Context, chatID int) (*storage.DBScheduleNotify, error) {
+	q := `SELECT * FROM schedule_notifications WHERE chat_id = $1`
+
+	notify := storage.DBScheduleNotify{}
+	err := s.db.GetContext(ctx, &notify, q, chatID)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrScheduleNotifyNotExist
+	}
+
+	if err != nil {
+		return nil, e.Wrap(fmt.Sprintf("can't get schedule notify in chat #%d", chatID), err)
+	}
+	return &notify, nil
+}
+
+// AllScheduleNotifies возвращает настройки рассылки расписания всех чатов.
+func (s *Storage) AllScheduleNotifies(ctx context.Context) ([]*storage.DBScheduleNotify, error) {
+	q := `SELECT * FROM schedule_notifications`
+
+	notifies := []*storage.DBScheduleNotify{}
+	err := s.db.SelectContext(ctx, &notifies, q)
+	if err != nil {
+		return nil, e.Wrap("can't get all schedule notifies", err)
+	}
+	return notifies, nil
+}
+
+// SaveScheduleNotify создаёт или обновляет настройки рассылки расписания чата.
+func (s *Storage) SaveScheduleNotify(ctx context.Context, n *storage.DBScheduleNotify) error {
+	q := `INSERT INTO schedule_notifications (chat_id, send_time, remind_before, last_sent_at, last_reminded_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (chat_id) DO UPDATE SET send_time = excluded.send_time, remind_before = excluded.remind_before,
+			last_sent_at = excluded.last_sent_at, last_reminded_at = excluded.last_reminded_at`
+	if _, err := s.db.ExecContext(ctx, q, n.ChatID, n.SendTime, n.RemindBefore, n.LastSentAt, n.LastRemindedAt); err != nil {
+		return e.Wrap(fmt.Sprintf("can't save schedule notify in chat #%d", n.ChatID), err)
+	}
+	return nil
+}
+
+// DeleteScheduleNotify отключает рассылку расписания в чате.
+func (s *Storage) DeleteScheduleNotify(ctx context.Context, chatID int) error {
+	q := `DELETE FROM schedule_notifications WHERE chat_id = $1`
+	if _, err := s.db.ExecContext(ctx, q, chatID); err != nil {
+		return e.Wrap(fmt.Sprintf("can't delete schedule notify in chat #%d", chatID), err)
+	}
+	return nil
+}
+
+// GetDialogState возвращает состояние незавершённого диалога пользователя в чате.
+func (s *Storage) GetDialogState(ctx context.Context, chatID, tgID int) (*storage.DBDialogState, error) {
+	q := `SELECT * FROM dialog_states WHERE chat_id = $1 AND tg_id = $2`
+
+	state := storage.DBDialogState{}
+	err := s.db.GetContext(ctx, &state, q, chatID, tgID)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrDialogNotExist
+	}
+
+	if err != nil {
+		return nil, e.Wrap(fmt.Sprintf("can't get dialog state tg id: %d, chat id: %d", tgID, chatID), err)
+	}
+	return &state, nil
+}
+
+// SaveDialogState создаёт или обновляет состояние диалога пользователя в чате.
+func (s *Storage) SaveDialogState(ctx context.Context, state *storage.DBDialogState) error {
+	q := `INSERT INTO dialog_states (chat_id, tg_id, dialog, step, data, updated_at) VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (chat_id, tg_id) DO UPDATE SET dialog = excluded.dialog, step = excluded.step,
+			data = excluded.data, updated_at = excluded.updated_at`
+	_, err := s.db.ExecContext(ctx, q, state.ChatID, state.TgID, state.Dialog, state.Step, state.Data, state.UpdatedAt)
+	if err != nil {
+		return e.Wrap("can't save dialog state", err)
+	}
+	return nil
+}
+
+// DeleteDialogState удаляет состояние диалога пользователя в чате.
+func (s *Storage) DeleteDialogState(ctx context.Context, chatID, tgID int) error {
+	q := `DELETE FROM dialog_states WHERE chat_id = $1 AND tg_id = $2`
+	_, err := s.db.ExecContext(ctx, q, chatID, tgID)
+	if err != nil {
+		return e.Wrap("can't delete dialog state", err)
+	}
+	return nil
+}
+
+// ChatRoles возвращает роли, выданные пользователям в чате.
+func (s *Storage) ChatRoles(ctx context.Context, chatID int) ([]*storage.DBRole, error) {
+	q := `SELECT * FROM chat_roles WHERE chat_id = $1 ORDER BY role, tg_id`
+
+	roles := []*storage.DBRole{}
+	err := s.db.SelectContext(ctx, &roles, q, chatID)
+	if err != nil {
+		return nil, e.Wrap(fmt.Sprintf("can't get roles in chat #%d", chatID), err)
+	}
+	return roles, nil
+}
+
+// UserRoles возвращает роли пользователя в чате.
+func (s *Storage) UserRoles(ctx context.Context, chatID, tgID int) ([]string, error) {
+	q := `SELECT role FROM chat_roles WHERE chat_id = $1 AND tg_id = $2`
+
+	roles := []string{}
+	err := s.db.SelectContext(ctx, &roles, q, chatID, tgID)
+	if err != nil {
+		return nil, e.Wrap(fmt.Sprintf("can't get roles tg id: %d, chat id: %d", tgID, chatID), err)
+	}
+	return roles, nil
+}
+
+// GrantRole выдаёт пользователю роль в чате, повторная выдача ничего не меняет.
+func (s *Storage) GrantRole(ctx context.Context, r *storage.DBRole) error {
+	q := `INSERT INTO chat_roles (chat_id, tg_id, role) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+	if _, err := s.db.ExecContext(ctx, q, r.ChatID, r.TgID, r.Role); err != nil {
+		return e.Wrap(fmt.Sprintf("can't grant role %s in chat #%d", r.Role, r.ChatID), err)
+	}
+	return nil
+}
+
+// RevokeRole забирает роль у пользователя в чате. Если роль не была выдана, возвращает storage.ErrRoleNotExist.
+func (s *Storage) RevokeRole(ctx context.Context, r *storage.DBRole) error {
+	q := `DELETE FROM chat_roles WHERE chat_id = $1 AND tg_id = $2 AND role = $3`
+	res, err := s.db.ExecContext(ctx, q, r.ChatID, r.TgID, r.Role)
+	if err != nil {
+		return e.Wrap(fmt.Sprintf("can't revoke role %s in chat #%d", r.Role, r.ChatID), err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return storage.ErrRoleNotExist
+	}
+	return nil
+}
+
+// GetCooldown возвращает время, до которого команда недоступна пользователю в чате (tgID 0 - всему чату).
+func (s *Storage) GetCooldown(ctx context.Context, chatID, tgID int, command string) (*storage.DBCooldown, error) {
+	q := `SELECT * FROM cooldowns WHERE chat_id = $1 AND tg_id = $2 AND command = $3`
+
+	cooldown := storage.DBCooldown{}
+	err := s.db.GetContext(ctx, &cooldown, q, chatID, tgID, command)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrCooldownNotExist
+	}
+
+	if err != nil {
+		return nil, e.Wrap(fmt.Sprintf("can't get cooldown %s tg id: %d, chat id: %d", command, tgID, chatID), err)
+	}
+	return &cooldown, nil
+}
+
+// SaveCooldown создаёт или обновляет ограничение на вызов команды.
+func (s *Storage) SaveCooldown(ctx context.Context, c *storage.DBCooldown) error {
+	q := `INSERT INTO cooldowns (chat_id, tg_id, command, expires_at, warned) VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (chat_id, tg_id, command) DO UPDATE SET expires_at = excluded.expires_at, warned = excluded.warned`
+	if _, err := s.db.ExecContext(ctx, q, c.ChatID, c.TgID, c.Command, c.ExpiresAt, c.Warned); err != nil {
+		return e.Wrap(fmt.Sprintf("can't save cooldown %s in chat #%d", c.Command, c.ChatID), err)
+	}
+	return nil
+}
+
+// DeleteExpiredCooldowns удаляет ограничения, истёкшие к моменту now.
+func (s *Storage) DeleteExpiredCooldowns(ctx context.Context, now time.Time) error {
+	q := `DELETE FROM cooldowns WHERE expires_at < $1`
+	if _, err := s.db.ExecContext(ctx, q, now); err != nil {
+		return e.Wrap("can't delete expired cooldowns", err)
+	}
+	return nil
+}
+
+// ChatCommandCooldowns возвращает настроенные в чате ограничения частоты вызова команд.
+func (s *Storage) ChatCommandCooldowns(ctx context.Context, chatID int) ([]*storage.DBCommandCooldown, error) {
+	q := `SELECT * FROM command_cooldowns WHERE chat_id = $1`
+
+	cooldowns := []*storage.DBCommandCooldown{}
+	err := s.db.SelectContext(ctx, &cooldowns, q, chatID)
+	if err != nil {
+		return nil, e.Wrap(fmt.Sprintf("can't get command cooldowns in chat #%d", chatID), err)
+	}
+	return cooldowns, nil
+}
+
+// SaveCommandCooldown создаёт или обновляет ограничение частоты вызова команды в чате.
+func (s *Storage) SaveCommandCooldown(ctx context.Context, c *storage.DBCommandCooldown) error {
+	q := `INSERT INTO command_cooldowns (chat_id, command, per_user, per_chat) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (chat_id, command) DO UPDATE SET per_user = excluded.per_user, per_chat = excluded.per_chat`
+	if _, err := s.db.ExecContext(ctx, q, c.ChatID, c.Command, c.PerUser, c.PerChat); err != nil {
+		return e.Wrap(fmt.Sprintf("can't save command cooldown %s in chat #%d", c.Command, c.ChatID), err)
+	}
+	return nil
+}
+
+// DeleteCommandCooldown возвращает команде в чате ограничения по умолчанию.
+func (s *Storage) DeleteCommandCooldown(ctx context.Context, chatID int, command string) error {
+	q := `DELETE FROM command_cooldowns WHERE chat_id = $1 AND command = $2`
+	if _, err := s.db.ExecContext(ctx, q, chatID, command); err != nil {
+		return e.Wrap(fmt.Sprintf("can't delete command cooldown %s in chat #%d", command, chatID), err)
+	}
+	return nil
+}
+
+// ChatAutoReplies возвращает правила автоответов чата в порядке добавления.
+func (s *Storage) ChatAutoReplies(ctx context.Context, chatID int) ([]*storage.DBAutoReply, error) {
+	q := `SELECT * FROM auto_replies WHERE chat_id = $1 ORDER BY id`
+
+	replies := []*storage.DBAutoReply{}
+	err := s.db.SelectContext(ctx, &replies, q, chatID)
+	if err != nil {
+		return nil, e.Wrap(fmt.Sprintf("can't get auto replies in chat #%d", chatID), err)
+	}
+	return replies, nil
+}
+
+// AddAutoReply добавляет правило автоответа и возвращает его id.
+func (s *Storage) AddAutoReply(ctx context.Context, r *storage.DBAutoReply) (int, error) {
+	q := `INSERT INTO auto_replies (chat_id, kind, pattern, reply, probability, cooldown) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	var id int
+	err := s.db.QueryRowContext(ctx, q, r.ChatID, r.Kind, r.Pattern, r.Reply, r.Probability, r.Cooldown).Scan(&id)
+	if err != nil {
+		return 0, e.Wrap(fmt.Sprintf("can't add auto reply in chat #%d", r.ChatID), err)
+	}
+	return id, nil
+}
+
+// DeleteAutoReply удаляет правило автоответа чата.
+func (s *Storage) DeleteAutoReply(ctx context.Context, chatID, id int) error {
+	q := `DELETE FROM auto_replies WHERE chat_id = $1 AND id = $2`
+	res, err := s.db.ExecContext(ctx, q, chatID, id)
+	if err != nil {
+		return e.Wrap(fmt.Sprintf("can't delete auto reply #%d in chat #%d", id, chatID), err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return storage.ErrAutoReplyNotExist
+	}
+	return nil
+}
+
 // CreateUserStats создаёт статистику пользователя в базе данных.
 func (s *Storage) CreateUserStats(ctx context.Context, u *storage.DBUserStat) (int, error) {
 	q := `INSERT INTO user_stats (message_count, dick_plus_count, dick_minus_count, yes_count, no_count, duels_count, 
@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"tg_ics_useful_bot/storage"
+	"time"
+)
+
+// newTestStorage создаёт хранилище в памяти со всеми миграциями.
+func newTestStorage(t *testing.T) *Storage {
+	t.Helper()
+	s, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("can't open db: %v", err)
+	}
+	// у каждого соединения :memory: своя база
+	s.db.SetMaxOpenConns(1)
+	t.Cleanup(func() { s.db.Close() })
+
+	files, err := filepath.Glob("../../migrations/sqlite/*.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no migrations: %v", err)
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatalf("can't read %s: %v", f, err)
+		}
+		up, _, _ := strings.Cut(string(data), "-- +goose Down")
+		if _, err := s.db.Exec(up); err != nil {
+			t.Fatalf("can't apply %s: %v", f, err)
+		}
+	}
+	return s
+}
+
+func TestGetHomeworkByPeriodTimezone(t *testing.T) {
+	defer func(local *time.Location) { time.Local = local }(time.Local)
+	time.Local = time.UTC
+
+	s := newTestStorage(t)
+	ctx := context.Background()
+	// 22:30 UTC 5 февраля - уже 6 февраля во Владивостоке
+	created := time.Date(2024, time.February, 5, 22, 30, 0, 0, time.UTC)
+	q := `INSERT INTO homeworks (chat_id, subject, task, created_at) VALUES ($1, $2, $3, $4)`
+	if _, err := s.db.Exec(q, 1, "Физика", "задачи", created); err != nil {
+		t.Fatalf("can't add homework: %v", err)
+	}
+
+	vladivostok := time.FixedZone("VLAT", 10*60*60)
+	day := time.Date(2024, time.February, 6, 0, 0, 0, 0, vladivostok)
+	homeworks, err := s.GetHomeworkByPeriod(ctx, 1, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("GetHomeworkByPeriod: %v", err)
+	}
+	if len(homeworks) != 1 {
+		t.Errorf("homework of 06.02 in +10: got %d, want 1", len(homeworks))
+	}
+
+	homeworks, err = s.GetHomeworkByPeriod(ctx, 1, day.AddDate(0, 0, -1), day)
+	if err != nil {
+		t.Fatalf("GetHomeworkByPeriod: %v", err)
+	}
+	if len(homeworks) != 0 {
+		t.Errorf("homework of 05.02 in +10: got %d, want 0", len(homeworks))
+	}
+}
+
+func TestRevokeRole(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+	role := &storage.DBRole{ChatID: 1, TgID: 2, Role: "auctioneer"}
+	if err := s.GrantRole(ctx, role); err != nil {
+		t.Fatalf("GrantRole: %v", err)
+	}
+	if err := s.RevokeRole(ctx, role); err != nil {
+		t.Fatalf("RevokeRole: %v", err)
+	}
+	if err := s.RevokeRole(ctx, role); err != storage.ErrRoleNotExist {
+		t.Errorf("RevokeRole of not granted role: got %v, want ErrRoleNotExist", err)
+	}
+}
+
+func TestDeactivateChatRollback(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+	if err := s.CreateUser(ctx, &storage.DBUser{TgID: 1, ChatID: 1, Username: "user"}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	// последний DELETE падает, UPDATE пользователей должен откатиться
+	if _, err := s.db.Exec(`DROP TABLE cooldowns`); err != nil {
+		t.Fatalf("can't drop cooldowns: %v", err)
+	}
+	if err := s.DeactivateChat(ctx, 1); err == nil {
+		t.Fatal("DeactivateChat: expected error")
+	}
+	user, err := s.GetUser(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if !user.Active {
+		t.Error("user deactivated by a failed DeactivateChat")
+	}
+}
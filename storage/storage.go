@@ -11,6 +11,8 @@ type Storage interface {
 	CreateUser(ctx context.Context, u *DBUser) error
 	UpdateUser(ctx context.Context, u *DBUser) error
 	UsersByChat(ctx context.Context, chatID int) ([]*DBUser, error)
+	SetUserActive(ctx context.Context, tgID, chatID int, active bool) error
+	DeactivateChat(ctx context.Context, chatID int) error
 
 	UserByUsername(ctx context.Context, username string, chatID int) (*DBUser, error)
 
@@ -18,20 +20,72 @@ type Storage interface {
 	CreateGayOfDay(ctx context.Context, gay *DBGay) error
 	RemoveGayOfDay(ctx context.Context, chatID int) error
 
-	GetCalendarID(ctx context.Context, chatID int) (string, error)
-	AddCalendarID(ctx context.Context, chatID int, calendarID string) error
+	GetChatTimezone(ctx context.Context, chatID int) (string, error)
+	SetChatTimezone(ctx context.Context, chatID int, timezone string) error
 
-	AddHomework(ctx context.Context, chatID int, subject string, task string) error
+	GetChatSettings(ctx context.Context, chatID int) (*DBChatSettings, error)
+	SaveChatSettings(ctx context.Context, c *DBChatSettings) error
+
+	GetCalendar(ctx context.Context, chatID int) (*DBCalendar, error)
+	AllCalendars(ctx context.Context) ([]*DBCalendar, error)
+	SaveCalendar(ctx context.Context, c *DBCalendar) error
+	SetNumeratorWeek(ctx context.Context, chatID int, week time.Time) error
+
+	AddHomework(ctx context.Context, chatID int, subject string, task string, deadline *time.Time) error
+	GetAllHomework(ctx context.Context, chatID int) ([]*DBHomework, error)
 	GetHomeworkByChatID(ctx context.Context, chatID int, limit int) ([]*DBHomework, error)
 	GetHomeworkBySubject(ctx context.Context, chatID int, subject string) ([]*DBHomework, error)
+	GetHomeworkPage(ctx context.Context, chatID int, subject string, limit, offset int) ([]*DBHomework, error)
+	CountHomework(ctx context.Context, chatID int, subject string) (int, error)
+	GetHomeworkByPeriod(ctx context.Context, chatID int, from, to time.Time) ([]*DBHomework, error)
 	DeleteHomework(ctx context.Context, rowID int) error
 
+	GetDigest(ctx context.Context, chatID int) (*DBDigest, error)
+	AllDigests(ctx context.Context) ([]*DBDigest, error)
+	SaveDigest(ctx context.Context, d *DBDigest) error
+	DeleteDigest(ctx context.Context, chatID int) error
+
+	GetScheduleNotify(ctx context.Context, chatID int) (*DBScheduleNotify, error)
+	AllScheduleNotifies(ctx context.Context) ([]*DBScheduleNotify, error)
+	SaveScheduleNotify(ctx context.Context, n *DBScheduleNotify) error
+	DeleteScheduleNotify(ctx context.Context, chatID int) error
+
+	GetDialogState(ctx context.Context, chatID, tgID int) (*DBDialogState, error)
+	SaveDialogState(ctx context.Context, state *DBDialogState) error
+	DeleteDialogState(ctx context.Context, chatID, tgID int) error
+
+	ChatRoles(ctx context.Context, chatID int) ([]*DBRole, error)
+	UserRoles(ctx context.Context, chatID, tgID int) ([]string, error)
+	GrantRole(ctx context.Context, r *DBRole) error
+	RevokeRole(ctx context.Context, r *DBRole) error
+
+	GetCooldown(ctx context.Context, chatID, tgID int, command string) (*DBCooldown, error)
+	SaveCooldown(ctx context.Context, c *DBCooldown) error
+	DeleteExpiredCooldowns(ctx context.Context, now time.Time) error
+	ChatCommandCooldowns(ctx context.Context, chatID int) ([]*DBCommandCooldown, error)
+	SaveCommandCooldown(ctx context.Context, c *DBCommandCooldown) error
+	DeleteCommandCooldown(ctx context.Context, chatID int, command string) error
+
+	ChatAutoReplies(ctx context.Context, chatID int) ([]*DBAutoReply, error)
+	AddAutoReply(ctx context.Context, r *DBAutoReply) (int, error)
+	DeleteAutoReply(ctx context.Context, chatID, id int) error
+
 	CreateUserStats(ctx context.Context, u *DBUserStat) (int, error)
 	GetUserStats(ctx context.Context, u *DBUser) (*DBUserStat, error)
 	UpdateUserStats(ctx context.Context, u *DBUserStat) error
 }
 
-var ErrUserNotExist = errors.New("user not exists")
+var (
+	ErrUserNotExist           = errors.New("user not exists")
+	ErrDialogNotExist         = errors.New("dialog not exists")
+	ErrDigestNotExist         = errors.New("digest not exists")
+	ErrChatNotExist           = errors.New("chat settings not exists")
+	ErrCalendarNotExist       = errors.New("calendar not exists")
+	ErrScheduleNotifyNotExist = errors.New("schedule notify not exists")
+	ErrCooldownNotExist       = errors.New("cooldown not exists")
+	ErrAutoReplyNotExist      = errors.New("auto reply not exists")
+	ErrRoleNotExist           = errors.New("role not exists")
+)
 
 type DBUser struct {
 	ID                 int       `json:"-" db:"id"`
@@ -52,6 +106,8 @@ type DBUser struct {
 	Points             int       `db:"points"`
 	CurDickChangeCount int       `db:"cur_dick_change_count"`
 	MaxDickChangeCount int       `db:"max_dick_change_count"`
+	// Active пользователь состоит в чате. Вышедшие из чата не попадают в UsersByChat.
+	Active bool `db:"active"`
 }
 
 type DBGay struct {
@@ -62,12 +118,104 @@ type DBGay struct {
 	CreatedAt time.Time `db:"created_at"`
 }
 
+// DBChatSettings настройки чата из /settings. Games - игры на пенисах и аукцион,
+// AutoReplies - ответы бота на обычные сообщения, DeleteCommands - удалять сообщения с командами,
+// Greetings - приветствовать новых участников и прощаться с ушедшими. Welcome и Goodbye - тексты
+// приветствия и прощания чата, пустая строка - текст по умолчанию.
+type DBChatSettings struct {
+	ChatID         int    `db:"chat_id"`
+	Games          bool   `db:"games"`
+	AutoReplies    bool   `db:"auto_replies"`
+	DeleteCommands bool   `db:"delete_commands"`
+	Language       string `db:"language"`
+	Greetings      bool   `db:"greetings"`
+	Welcome        string `db:"welcome"`
+	Goodbye        string `db:"goodbye"`
+}
+
 type DBHomework struct {
-	ID        int       `db:"id"`
+	ID        int        `db:"id"`
+	ChatID    int        `db:"chat_id"`
+	Subject   string     `db:"subject"`
+	Task      string     `db:"task"`
+	CreatedAT time.Time  `db:"created_at"`
+	Deadline  *time.Time `db:"deadline"`
+}
+
+// DBCalendar календарь чата. Kind - тип источника (google или ics),
+// CalendarID - Google Calendar ID или ссылка на .ics, Data - содержимое загруженного .ics файла.
+// NumeratorWeek - день недели, которая считается числителем, nil если недели не различаются.
+type DBCalendar struct {
+	ChatID        int        `db:"chat_id"`
+	CalendarID    string     `db:"calendar_id"`
+	Kind          string     `db:"kind"`
+	Data          string     `db:"ics_data"`
+	NumeratorWeek *time.Time `db:"numerator_week"`
+}
+
+type DBDigest struct {
+	ChatID     int       `db:"chat_id"`
+	Weekday    int       `db:"weekday"`
+	SendTime   string    `db:"send_time"`
+	Pin        bool      `db:"pin"`
+	LastSentAt time.Time `db:"last_sent_at"`
+}
+
+// DBScheduleNotify настройки утренней рассылки расписания.
+// RemindBefore - за сколько минут до занятия напоминать о нём, 0 - не напоминать.
+type DBScheduleNotify struct {
+	ChatID         int       `db:"chat_id"`
+	SendTime       string    `db:"send_time"`
+	RemindBefore   int       `db:"remind_before"`
+	LastSentAt     time.Time `db:"last_sent_at"`
+	LastRemindedAt time.Time `db:"last_reminded_at"`
+}
+
+type DBDialogState struct {
 	ChatID    int       `db:"chat_id"`
-	Subject   string    `db:"subject"`
-	Task      string    `db:"task"`
-	CreatedAT time.Time `db:"created_at"`
+	TgID      int       `db:"tg_id"`
+	Dialog    string    `db:"dialog"`
+	Step      int       `db:"step"`
+	Data      string    `db:"data"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// DBRole роль пользователя в чате, выданная командой /grant.
+type DBRole struct {
+	ChatID int    `db:"chat_id"`
+	TgID   int    `db:"tg_id"`
+	Role   string `db:"role"`
+}
+
+// DBCooldown время, до которого команда недоступна пользователю в чате.
+// TgID 0 - ограничение для всего чата. Warned - пользователю уже ответили, что нужно подождать.
+type DBCooldown struct {
+	ChatID    int       `db:"chat_id"`
+	TgID      int       `db:"tg_id"`
+	Command   string    `db:"command"`
+	ExpiresAt time.Time `db:"expires_at"`
+	Warned    bool      `db:"warned"`
+}
+
+// DBCommandCooldown ограничение частоты вызова команды в чате вместо ограничения по умолчанию.
+// PerUser и PerChat в секундах, 0 - без ограничения.
+type DBCommandCooldown struct {
+	ChatID  int    `db:"chat_id"`
+	Command string `db:"command"`
+	PerUser int    `db:"per_user"`
+	PerChat int    `db:"per_chat"`
+}
+
+// DBAutoReply правило автоответа в чате: на сообщение, подходящее под Pattern, бот отвечает Reply.
+// Kind - "regex" или "rhyme", Probability - шанс ответа в процентах, Cooldown - в секундах.
+type DBAutoReply struct {
+	ID          int    `db:"id"`
+	ChatID      int    `db:"chat_id"`
+	Kind        string `db:"kind"`
+	Pattern     string `db:"pattern"`
+	Reply       string `db:"reply"`
+	Probability int    `db:"probability"`
+	Cooldown    int    `db:"cooldown"`
 }
 
 type DBUserStat struct {